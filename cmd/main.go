@@ -6,10 +6,15 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"avito-backend-trainee-autumn-2025/internal/api/handler"
 	"avito-backend-trainee-autumn-2025/internal/api/route"
+	"avito-backend-trainee-autumn-2025/internal/config"
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/internal/repository/memory"
 	"avito-backend-trainee-autumn-2025/internal/repository/postgres"
+	"avito-backend-trainee-autumn-2025/internal/repository/sqlite"
 	"avito-backend-trainee-autumn-2025/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -19,28 +24,40 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	pool, err := postgres.NewPool(ctx)
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("db connect error: %v", err)
+		log.Fatalf("config error: %v", err)
 	}
-	defer pool.Close()
 
-	txManager := postgres.NewTxManager(pool)
+	selector, err := usecase.NewConfiguredReviewerSelector(cfg.ReviewerStrategy, cfg.TeamStrategies, cfg.ReviewerWeights)
+	if err != nil {
+		log.Fatalf("reviewer selector error: %v", err)
+	}
 
-	userRepo := postgres.NewUserRepository(pool)
-	teamRepo := postgres.NewTeamRepository(pool)
-	prRepo := postgres.NewPRRepository(pool)
+	st, err := openStorage(ctx, cfg)
+	if err != nil {
+		log.Fatalf("db connect error: %v", err)
+	}
+	defer st.close()
 
-	userUC := usecase.NewUserUsecase(userRepo, prRepo, txManager)
-	teamUC := usecase.NewTeamUsecase(teamRepo, userRepo, txManager)
-	prUC := usecase.NewPRUsecase(userRepo, prRepo, txManager)
+	userUC := usecase.NewUserUsecase(st.user, st.pr, st.tx, selector)
+	teamUC := usecase.NewTeamUsecase(st.team, st.user, st.tx, selector)
+	prUC := usecase.NewPRUsecase(st.user, st.pr, st.tx, selector, usecase.MergePolicy{
+		MinApprovals:            cfg.MergeMinApprovals,
+		BlockOnChangesRequested: cfg.MergeBlockOnChangesRequested,
+		AllowOverride:           cfg.MergeAllowOverride,
+	})
+	statsUC := usecase.NewStatsUsecase(st.stats)
 
 	userHandler := &handler.UserHandler{UserUsecase: userUC}
 	teamHandler := &handler.TeamHandler{TeamUsecase: teamUC}
 	prHandler := &handler.PRHandler{PRUsecase: prUC}
+	statsHandler := &handler.StatsHandler{StatsUsecase: statsUC}
+
+	go runAbsenceHandoffs(ctx, userUC, cfg.AbsenceCheckInterval)
 
 	router := gin.Default()
-	route.Register(router, prHandler, teamHandler, userHandler)
+	route.Register(router, prHandler, teamHandler, userHandler, statsHandler)
 
 	serverErr := make(chan error, 1)
 	go func() {
@@ -60,3 +77,83 @@ func main() {
 
 	os.Exit(0)
 }
+
+// storage — репозитории и менеджер транзакций выбранного хранилища
+type storage struct {
+	user  domain.UserRepository
+	team  domain.TeamRepository
+	pr    domain.PRRepository
+	stats domain.StatsRepository
+	tx    domain.TxManager
+	close func()
+}
+
+// openStorage подключает хранилище cfg.Storage: postgres по переменным окружения пула, файл SQLite
+// с применением миграций или пустое хранилище в памяти
+func openStorage(ctx context.Context, cfg *config.Config) (*storage, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		store := memory.NewStore()
+		log.Printf("using in-memory storage: data is lost on restart")
+
+		return &storage{
+			user:  memory.NewUserRepository(store),
+			team:  memory.NewTeamRepository(store),
+			pr:    memory.NewPRRepository(store),
+			stats: memory.NewStatsRepository(store),
+			tx:    memory.NewTxManager(store),
+			close: func() {},
+		}, nil
+	case config.StorageSQLite:
+		db, err := sqlite.Open(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("using sqlite storage: %s", cfg.SQLitePath)
+
+		return &storage{
+			user:  sqlite.NewUserRepository(db),
+			team:  sqlite.NewTeamRepository(db),
+			pr:    sqlite.NewPRRepository(db),
+			stats: sqlite.NewStatsRepository(db),
+			tx:    sqlite.NewTxManager(db),
+			close: func() { db.Close() },
+		}, nil
+	}
+
+	pool, err := postgres.NewPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage{
+		user:  postgres.NewUserRepository(pool),
+		team:  postgres.NewTeamRepository(pool),
+		pr:    postgres.NewPRRepository(pool),
+		stats: postgres.NewStatsRepository(pool),
+		tx:    postgres.NewTxManager(pool),
+		close: pool.Close,
+	}, nil
+}
+
+// runAbsenceHandoffs периодически передает заместителям открытые ревью пользователей, чье отсутствие началось
+func runAbsenceHandoffs(ctx context.Context, userUC domain.UserUsecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := userUC.HandOffAbsences(ctx)
+			if err != nil {
+				log.Printf("absence handoff error: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("absence handoff: processed %d absences", n)
+			}
+		}
+	}
+}
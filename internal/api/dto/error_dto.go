@@ -7,4 +7,6 @@ type ErrorResponseDTO struct {
 type ErrorDTO struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// Details уточняет ошибку, например перечисляет невыполненные условия merge
+	Details []string `json:"details,omitempty"`
 }
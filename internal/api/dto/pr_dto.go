@@ -2,17 +2,53 @@ package dto
 
 import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
+	"encoding/json"
 	"time"
 )
 
 type PullRequestDTO struct {
-	PullRequestID     string     `json:"pull_request_id"`
-	PullRequestName   string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            string     `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         *time.Time `json:"createdAt,omitempty"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	PullRequestID     string                `json:"pull_request_id"`
+	PullRequestName   string                `json:"pull_request_name"`
+	AuthorID          string                `json:"author_id"`
+	Status            string                `json:"status"`
+	IsDraft           bool                  `json:"is_draft"`
+	AssignedReviewers []string              `json:"assigned_reviewers"`
+	CreatedAt         *time.Time            `json:"createdAt,omitempty"`
+	MergedAt          *time.Time            `json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time            `json:"closedAt,omitempty"`
+	MergeOverrideBy   string                `json:"merge_override_by,omitempty"`
+	MissingReviewers  int                   `json:"missing_reviewers,omitempty"`
+	FallbackReviewers []FallbackReviewerDTO `json:"fallback_reviewers,omitempty"`
+	SkippedAtCapacity []string              `json:"skipped_at_capacity,omitempty"`
+	// ReviewerStates, если задан, выводится в assigned_reviewers вместо списка user_id (?expand=reviewers)
+	ReviewerStates []ReviewerStateDTO `json:"-"`
+}
+
+// FallbackReviewerDTO — ревьювер, назначенный из резервной команды
+type FallbackReviewerDTO struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type ReviewerStateDTO struct {
+	UserID    string     `json:"user_id"`
+	State     string     `json:"state"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+func (d PullRequestDTO) MarshalJSON() ([]byte, error) {
+	type plain PullRequestDTO
+	if d.ReviewerStates == nil {
+		return json.Marshal(plain(d))
+	}
+
+	return json.Marshal(struct {
+		plain
+		AssignedReviewers []ReviewerStateDTO `json:"assigned_reviewers"`
+	}{
+		plain:             plain(d),
+		AssignedReviewers: d.ReviewerStates,
+	})
 }
 
 type PullRequestShortDTO struct {
@@ -26,6 +62,7 @@ type PullRequestCreateRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	IsDraft         bool   `json:"is_draft"`
 }
 
 type PullRequestCreateResponse struct {
@@ -34,12 +71,47 @@ type PullRequestCreateResponse struct {
 
 type PullRequestMergeRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	// Override — смержить в обход политики merge, если она это разрешает
+	Override bool `json:"override"`
 }
 
 type PullRequestMergeResponse struct {
 	PR PullRequestDTO `json:"pr"`
 }
 
+type PullRequestCloseRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type PullRequestCloseResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
+type PullRequestReopenRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type PullRequestReopenResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
+type PullRequestMarkReadyRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type PullRequestMarkReadyResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
+type PullRequestReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type PullRequestReviewResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
 type PullRequestReassignRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
@@ -50,6 +122,55 @@ type PullRequestReassignResponse struct {
 	ReplacedBy string         `json:"replaced_by"`
 }
 
+type ReviewReassignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	ReplacedBy    string `json:"replaced_by,omitempty"`
+}
+
+type AssignmentEventDTO struct {
+	EventID        int64     `json:"event_id"`
+	Type           string    `json:"type"`
+	UserID         string    `json:"user_id,omitempty"`
+	PreviousUserID string    `json:"previous_user_id,omitempty"`
+	Actor          string    `json:"actor"`
+	Reason         string    `json:"reason"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type PullRequestHistoryResponse struct {
+	PullRequestID string               `json:"pull_request_id"`
+	Events        []AssignmentEventDTO `json:"events"`
+}
+
+// ReviewerProfileDTO — профиль ревьювера вместе с его решением по PR
+type ReviewerProfileDTO struct {
+	UserDTO
+	State     string     `json:"state"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+type TimelineEventDTO struct {
+	Type           string    `json:"type"`
+	At             time.Time `json:"at"`
+	UserID         string    `json:"user_id,omitempty"`
+	PreviousUserID string    `json:"previous_user_id,omitempty"`
+	Actor          string    `json:"actor,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+}
+
+type PullRequestGetResponse struct {
+	PR        PullRequestDTO       `json:"pr"`
+	Author    *UserDTO             `json:"author"`
+	Reviewers []ReviewerProfileDTO `json:"reviewers"`
+	Timeline  []TimelineEventDTO   `json:"timeline"`
+}
+
+type PullRequestListResponse struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	NextCursor   string           `json:"next_cursor,omitempty"`
+}
+
 func ToPullRequestDTO(pr *domain.PullRequest) PullRequestDTO {
 	if pr == nil {
 		return PullRequestDTO{}
@@ -60,12 +181,44 @@ func ToPullRequestDTO(pr *domain.PullRequest) PullRequestDTO {
 		PullRequestName:   pr.Name,
 		AuthorID:          pr.AuthorID,
 		Status:            string(pr.Status),
+		IsDraft:           pr.IsDraft,
 		AssignedReviewers: append([]string(nil), pr.Reviewers...),
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
+		ClosedAt:          pr.ClosedAt,
+		MergeOverrideBy:   pr.MergeOverrideBy,
+		MissingReviewers:  pr.MissingReviewers,
+		FallbackReviewers: toFallbackReviewerDTOs(pr.FallbackReviewers),
+		SkippedAtCapacity: pr.SkippedAtCapacity,
 	}
 }
 
+func toFallbackReviewerDTOs(reviewers []*domain.FallbackReviewer) []FallbackReviewerDTO {
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	res := make([]FallbackReviewerDTO, 0, len(reviewers))
+	for _, r := range reviewers {
+		res = append(res, FallbackReviewerDTO{UserID: r.UserID, TeamName: r.TeamName})
+	}
+	return res
+}
+
+// ToReviewerStateDTOs конвертирует решения ревьюверов; для nil возвращает пустой срез,
+// чтобы развернутый assigned_reviewers не превратился в список user_id
+func ToReviewerStateDTOs(states []*domain.ReviewerState) []ReviewerStateDTO {
+	res := make([]ReviewerStateDTO, 0, len(states))
+	for _, st := range states {
+		res = append(res, ReviewerStateDTO{
+			UserID:    st.UserID,
+			State:     string(st.State),
+			UpdatedAt: st.UpdatedAt,
+		})
+	}
+	return res
+}
+
 func ToPullRequestShortDTO(pr *domain.PullRequest) PullRequestShortDTO {
 	if pr == nil {
 		return PullRequestShortDTO{}
@@ -107,9 +260,116 @@ func ToPullRequestMergeResponse(pr *domain.PullRequest) PullRequestMergeResponse
 	}
 }
 
+func ToPullRequestCloseResponse(pr *domain.PullRequest) PullRequestCloseResponse {
+	return PullRequestCloseResponse{
+		PR: ToPullRequestDTO(pr),
+	}
+}
+
+func ToPullRequestReopenResponse(pr *domain.PullRequest) PullRequestReopenResponse {
+	return PullRequestReopenResponse{
+		PR: ToPullRequestDTO(pr),
+	}
+}
+
+func ToPullRequestMarkReadyResponse(pr *domain.PullRequest) PullRequestMarkReadyResponse {
+	return PullRequestMarkReadyResponse{
+		PR: ToPullRequestDTO(pr),
+	}
+}
+
+func ToPullRequestReviewResponse(pr *domain.PullRequest) PullRequestReviewResponse {
+	return PullRequestReviewResponse{
+		PR: ToPullRequestDTO(pr),
+	}
+}
+
 func ToPullRequestReassignResponse(pr *domain.PullRequest, replacedBy string) PullRequestReassignResponse {
 	return PullRequestReassignResponse{
 		PR:         ToPullRequestDTO(pr),
 		ReplacedBy: replacedBy,
 	}
 }
+
+// SplitReviewReassignments делит замены ревьюверов на выполненные и те, для которых не нашлось кандидата
+func SplitReviewReassignments(items []*domain.ReviewReassignment) (reassigned, notReassigned []ReviewReassignmentDTO) {
+	for _, it := range items {
+		d := ReviewReassignmentDTO{
+			PullRequestID: it.PRID,
+			OldUserID:     it.OldReviewerID,
+			ReplacedBy:    it.NewReviewerID,
+		}
+		if it.NewReviewerID == "" {
+			notReassigned = append(notReassigned, d)
+		} else {
+			reassigned = append(reassigned, d)
+		}
+	}
+
+	return reassigned, notReassigned
+}
+
+func ToPullRequestHistoryResponse(prID string, events []*domain.AssignmentEvent) PullRequestHistoryResponse {
+	res := make([]AssignmentEventDTO, 0, len(events))
+	for _, e := range events {
+		res = append(res, AssignmentEventDTO{
+			EventID:        e.ID,
+			Type:           string(e.Type),
+			UserID:         e.UserID,
+			PreviousUserID: e.PreviousUserID,
+			Actor:          e.Actor,
+			Reason:         e.Reason,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+
+	return PullRequestHistoryResponse{
+		PullRequestID: prID,
+		Events:        res,
+	}
+}
+
+func ToPullRequestListResponse(page *domain.Page[*domain.PullRequest]) PullRequestListResponse {
+	return PullRequestListResponse{
+		PullRequests: ToPullRequestDTOs(page.Items),
+		NextCursor:   page.NextCursor,
+	}
+}
+
+func ToPullRequestGetResponse(details *domain.PRDetails) PullRequestGetResponse {
+	resp := PullRequestGetResponse{
+		PR:        ToPullRequestDTO(details.PR),
+		Reviewers: make([]ReviewerProfileDTO, 0, len(details.Reviewers)),
+		Timeline:  make([]TimelineEventDTO, 0, len(details.Timeline)),
+	}
+	if details.Author != nil {
+		author := ToUserDTO(details.Author)
+		resp.Author = &author
+	}
+
+	states := make(map[string]*domain.ReviewerState, len(details.PR.ReviewerStates))
+	for _, st := range details.PR.ReviewerStates {
+		states[st.UserID] = st
+	}
+	for _, u := range details.Reviewers {
+		profile := ReviewerProfileDTO{UserDTO: ToUserDTO(u), State: string(domain.ReviewPending)}
+		if st, ok := states[u.ID]; ok {
+			profile.State = string(st.State)
+			profile.UpdatedAt = st.UpdatedAt
+		}
+		resp.Reviewers = append(resp.Reviewers, profile)
+	}
+
+	for _, e := range details.Timeline {
+		resp.Timeline = append(resp.Timeline, TimelineEventDTO{
+			Type:           string(e.Type),
+			At:             e.At,
+			UserID:         e.UserID,
+			PreviousUserID: e.PreviousUserID,
+			Actor:          e.Actor,
+			Reason:         e.Reason,
+		})
+	}
+
+	return resp
+}
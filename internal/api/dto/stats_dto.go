@@ -0,0 +1,91 @@
+package dto
+
+import "avito-backend-trainee-autumn-2025/internal/domain"
+
+type UserAssignmentStatsDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+	Total    int    `json:"total"`
+	Open     int    `json:"open"`
+	Merged   int    `json:"merged"`
+}
+
+type PRReassignmentStatsDTO struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	Status          string `json:"status"`
+	Reviewers       int    `json:"reviewers"`
+	Reassignments   int    `json:"reassignments"`
+}
+
+type TeamStatsDTO struct {
+	TeamName      string `json:"team_name"`
+	Members       int    `json:"members"`
+	ActiveMembers int    `json:"active_members"`
+	OpenPRs       int    `json:"open_prs"`
+	MergedPRs     int    `json:"merged_prs"`
+	Assignments   int    `json:"assignments"`
+	Reassignments int    `json:"reassignments"`
+}
+
+type StatsUsersResponse struct {
+	Users []UserAssignmentStatsDTO `json:"users"`
+}
+
+type StatsPullRequestsResponse struct {
+	PullRequests []PRReassignmentStatsDTO `json:"pull_requests"`
+}
+
+type StatsTeamsResponse struct {
+	Teams []TeamStatsDTO `json:"teams"`
+}
+
+func ToStatsUsersResponse(stats []*domain.UserAssignmentStats) StatsUsersResponse {
+	res := make([]UserAssignmentStatsDTO, 0, len(stats))
+	for _, s := range stats {
+		res = append(res, UserAssignmentStatsDTO{
+			UserID:   s.UserID,
+			Username: s.Username,
+			TeamName: s.TeamName,
+			IsActive: s.IsActive,
+			Total:    s.Total,
+			Open:     s.Open,
+			Merged:   s.Merged,
+		})
+	}
+	return StatsUsersResponse{Users: res}
+}
+
+func ToStatsPullRequestsResponse(stats []*domain.PRReassignmentStats) StatsPullRequestsResponse {
+	res := make([]PRReassignmentStatsDTO, 0, len(stats))
+	for _, s := range stats {
+		res = append(res, PRReassignmentStatsDTO{
+			PullRequestID:   s.PRID,
+			PullRequestName: s.Name,
+			AuthorID:        s.AuthorID,
+			Status:          string(s.Status),
+			Reviewers:       s.Reviewers,
+			Reassignments:   s.Reassignments,
+		})
+	}
+	return StatsPullRequestsResponse{PullRequests: res}
+}
+
+func ToStatsTeamsResponse(stats []*domain.TeamStats) StatsTeamsResponse {
+	res := make([]TeamStatsDTO, 0, len(stats))
+	for _, s := range stats {
+		res = append(res, TeamStatsDTO{
+			TeamName:      s.TeamName,
+			Members:       s.Members,
+			ActiveMembers: s.ActiveMembers,
+			OpenPRs:       s.OpenPRs,
+			MergedPRs:     s.MergedPRs,
+			Assignments:   s.Assignments,
+			Reassignments: s.Reassignments,
+		})
+	}
+	return StatsTeamsResponse{Teams: res}
+}
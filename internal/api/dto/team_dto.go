@@ -9,17 +9,110 @@ type TeamMemberDTO struct {
 }
 
 type TeamDTO struct {
+	TeamName   string          `json:"team_name"`
+	Members    []TeamMemberDTO `json:"members"`
+	IsArchived bool            `json:"is_archived"`
+}
+
+type TeamAddRequest struct {
+	TeamName     string          `json:"team_name"`
+	Members      []TeamMemberDTO `json:"members"`
+	MoveExisting bool            `json:"move_existing"`
+}
+
+type TeamAddResponse struct {
+	Team          TeamDTO                 `json:"team"`
+	MovedUserIDs  []string                `json:"moved_user_ids,omitempty"`
+	Reassigned    []ReviewReassignmentDTO `json:"reassigned,omitempty"`
+	NotReassigned []ReviewReassignmentDTO `json:"not_reassigned,omitempty"`
+}
+
+type TeamGetResponse = TeamDTO
+
+type TeamDeactivateUsersRequest struct {
+	TeamName  string   `json:"team_name"`
+	UserIDs   []string `json:"user_ids"`
+	AllExcept bool     `json:"all_except"`
+}
+
+type TeamDeactivateUsersResponse struct {
+	TeamName           string                  `json:"team_name"`
+	DeactivatedUserIDs []string                `json:"deactivated_user_ids"`
+	Reassigned         []ReviewReassignmentDTO `json:"reassigned"`
+	NotReassigned      []ReviewReassignmentDTO `json:"not_reassigned"`
+}
+
+type TeamAddMembersRequest struct {
 	TeamName string          `json:"team_name"`
 	Members  []TeamMemberDTO `json:"members"`
+	Move     bool            `json:"move"`
 }
 
-type TeamAddRequest = TeamDTO
+type TeamAddMembersResponse struct {
+	Team          TeamDTO                 `json:"team"`
+	MovedUserIDs  []string                `json:"moved_user_ids"`
+	Reassigned    []ReviewReassignmentDTO `json:"reassigned"`
+	NotReassigned []ReviewReassignmentDTO `json:"not_reassigned"`
+}
 
-type TeamAddResponse struct {
+type TeamRemoveMembersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type TeamRemoveMembersResponse struct {
+	Team           TeamDTO                 `json:"team"`
+	RemovedUserIDs []string                `json:"removed_user_ids"`
+	Reassigned     []ReviewReassignmentDTO `json:"reassigned"`
+	NotReassigned  []ReviewReassignmentDTO `json:"not_reassigned"`
+}
+
+type TeamShortDTO struct {
+	TeamName   string `json:"team_name"`
+	IsArchived bool   `json:"is_archived"`
+}
+
+type TeamListResponse struct {
+	Teams      []TeamShortDTO `json:"teams"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type TeamResponse struct {
 	Team TeamDTO `json:"team"`
 }
 
-type TeamGetResponse = TeamDTO
+type TeamRenameRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type TeamSettingsDTO struct {
+	TeamName              string   `json:"team_name"`
+	MinReviewers          int      `json:"min_reviewers"`
+	MaxReviewers          int      `json:"max_reviewers"`
+	FallbackTeams         []string `json:"fallback_teams"`
+	DefaultMaxOpenReviews *int     `json:"default_max_open_reviews"`
+}
+
+type TeamSettingsResponse struct {
+	Settings TeamSettingsDTO `json:"settings"`
+}
+
+type TeamArchiveRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+type TeamDeleteRequest struct {
+	TeamName string `json:"team_name"`
+	Force    bool   `json:"force"`
+}
+
+type TeamDeleteResponse struct {
+	TeamName        string                  `json:"team_name"`
+	ReleasedUserIDs []string                `json:"released_user_ids"`
+	Reassigned      []ReviewReassignmentDTO `json:"reassigned"`
+	NotReassigned   []ReviewReassignmentDTO `json:"not_reassigned"`
+}
 
 func ToTeamMemberDTOs(users []*domain.User) []TeamMemberDTO {
 	res := make([]TeamMemberDTO, 0, len(users))
@@ -37,7 +130,102 @@ func ToTeamMemberDTOs(users []*domain.User) []TeamMemberDTO {
 
 func ToTeamDTO(team *domain.Team) TeamDTO {
 	return TeamDTO{
-		TeamName: team.Name,
-		Members:  ToTeamMemberDTOs(team.Members),
+		TeamName:   team.Name,
+		Members:    ToTeamMemberDTOs(team.Members),
+		IsArchived: team.ArchivedAt != nil,
+	}
+}
+
+func ToTeamDeactivateUsersResponse(res *domain.TeamDeactivation) TeamDeactivateUsersResponse {
+	reassigned, notReassigned := splitReassignmentsNonNil(res.Reassignments)
+
+	return TeamDeactivateUsersResponse{
+		TeamName:           res.TeamName,
+		DeactivatedUserIDs: append([]string{}, res.Deactivated...),
+		Reassigned:         reassigned,
+		NotReassigned:      notReassigned,
+	}
+}
+
+func ToTeamAddResponse(res *domain.TeamMembersChange) TeamAddResponse {
+	reassigned, notReassigned := SplitReviewReassignments(res.Reassignments)
+
+	return TeamAddResponse{
+		Team:          ToTeamDTO(res.Team),
+		MovedUserIDs:  res.UserIDs,
+		Reassigned:    reassigned,
+		NotReassigned: notReassigned,
+	}
+}
+
+func ToTeamAddMembersResponse(res *domain.TeamMembersChange) TeamAddMembersResponse {
+	reassigned, notReassigned := splitReassignmentsNonNil(res.Reassignments)
+
+	return TeamAddMembersResponse{
+		Team:          ToTeamDTO(res.Team),
+		MovedUserIDs:  append([]string{}, res.UserIDs...),
+		Reassigned:    reassigned,
+		NotReassigned: notReassigned,
+	}
+}
+
+func ToTeamRemoveMembersResponse(res *domain.TeamMembersChange) TeamRemoveMembersResponse {
+	reassigned, notReassigned := splitReassignmentsNonNil(res.Reassignments)
+
+	return TeamRemoveMembersResponse{
+		Team:           ToTeamDTO(res.Team),
+		RemovedUserIDs: append([]string{}, res.UserIDs...),
+		Reassigned:     reassigned,
+		NotReassigned:  notReassigned,
+	}
+}
+
+func ToTeamDeleteResponse(res *domain.TeamMembersChange) TeamDeleteResponse {
+	reassigned, notReassigned := splitReassignmentsNonNil(res.Reassignments)
+
+	return TeamDeleteResponse{
+		TeamName:        res.Team.Name,
+		ReleasedUserIDs: append([]string{}, res.UserIDs...),
+		Reassigned:      reassigned,
+		NotReassigned:   notReassigned,
+	}
+}
+
+// splitReassignmentsNonNil как SplitReviewReassignments, но вместо nil возвращает пустые срезы,
+// чтобы в JSON были [] вместо null
+func splitReassignmentsNonNil(reassignments []*domain.ReviewReassignment) ([]ReviewReassignmentDTO, []ReviewReassignmentDTO) {
+	reassigned, notReassigned := SplitReviewReassignments(reassignments)
+	if reassigned == nil {
+		reassigned = []ReviewReassignmentDTO{}
+	}
+	if notReassigned == nil {
+		notReassigned = []ReviewReassignmentDTO{}
+	}
+
+	return reassigned, notReassigned
+}
+
+func ToTeamListResponse(page *domain.Page[*domain.Team]) TeamListResponse {
+	resp := TeamListResponse{
+		Teams:      make([]TeamShortDTO, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, t := range page.Items {
+		resp.Teams = append(resp.Teams, TeamShortDTO{
+			TeamName:   t.Name,
+			IsArchived: t.ArchivedAt != nil,
+		})
+	}
+
+	return resp
+}
+
+func ToTeamSettingsDTO(settings *domain.TeamSettings) TeamSettingsDTO {
+	return TeamSettingsDTO{
+		TeamName:              settings.TeamName,
+		MinReviewers:          settings.MinReviewers,
+		MaxReviewers:          settings.MaxReviewers,
+		FallbackTeams:         append([]string{}, settings.FallbackTeams...),
+		DefaultMaxOpenReviews: settings.DefaultMaxOpenReviews,
 	}
 }
@@ -1,10 +1,16 @@
 package dto
 
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"time"
+)
+
 type UserDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"`
 }
 
 type UsersSetIsActiveRequest struct {
@@ -13,10 +19,128 @@ type UsersSetIsActiveRequest struct {
 }
 
 type UsersSetIsActiveResponse struct {
+	User          UserDTO                 `json:"user"`
+	Reassigned    []ReviewReassignmentDTO `json:"reassigned,omitempty"`
+	NotReassigned []ReviewReassignmentDTO `json:"not_reassigned,omitempty"`
+}
+
+type UsersSetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type UsersSetMaxOpenReviewsResponse struct {
 	User UserDTO `json:"user"`
 }
 
+type AbsenceDTO struct {
+	AbsenceID   int64      `json:"absence_id"`
+	UserID      string     `json:"user_id"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	DelegateID  string     `json:"delegate_id,omitempty"`
+	HandedOffAt *time.Time `json:"handed_off_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type UsersAddAbsenceRequest struct {
+	UserID     string    `json:"user_id"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	DelegateID string    `json:"delegate_id"`
+}
+
+type UsersAddAbsenceResponse struct {
+	Absence       AbsenceDTO              `json:"absence"`
+	Reassigned    []ReviewReassignmentDTO `json:"reassigned,omitempty"`
+	NotReassigned []ReviewReassignmentDTO `json:"not_reassigned,omitempty"`
+}
+
+type UsersDeleteAbsenceRequest struct {
+	AbsenceID int64 `json:"absence_id"`
+}
+
+type UsersAbsenceResponse struct {
+	Absence AbsenceDTO `json:"absence"`
+}
+
+type UsersAbsencesResponse struct {
+	UserID   string       `json:"user_id"`
+	Absences []AbsenceDTO `json:"absences"`
+}
+
 type UsersGetReviewResponse struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
 }
+
+type UsersListResponse struct {
+	Users      []UserDTO `json:"users"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+func ToUserDTO(user *domain.User) UserDTO {
+	return UserDTO{
+		UserID:         user.ID,
+		Username:       user.Name,
+		TeamName:       user.TeamName,
+		IsActive:       user.IsActive,
+		MaxOpenReviews: user.MaxOpenReviews,
+	}
+}
+
+func ToUsersSetIsActiveResponse(user *domain.User, reassignments []*domain.ReviewReassignment) UsersSetIsActiveResponse {
+	reassigned, notReassigned := SplitReviewReassignments(reassignments)
+
+	return UsersSetIsActiveResponse{
+		User:          ToUserDTO(user),
+		Reassigned:    reassigned,
+		NotReassigned: notReassigned,
+	}
+}
+
+func ToUsersListResponse(page *domain.Page[*domain.User]) UsersListResponse {
+	resp := UsersListResponse{
+		Users:      make([]UserDTO, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, u := range page.Items {
+		resp.Users = append(resp.Users, ToUserDTO(u))
+	}
+
+	return resp
+}
+
+func ToAbsenceDTO(a *domain.Absence) AbsenceDTO {
+	return AbsenceDTO{
+		AbsenceID:   a.ID,
+		UserID:      a.UserID,
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+		DelegateID:  a.DelegateID,
+		HandedOffAt: a.HandedOffAt,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func ToUsersAddAbsenceResponse(absence *domain.Absence, reassignments []*domain.ReviewReassignment) UsersAddAbsenceResponse {
+	reassigned, notReassigned := SplitReviewReassignments(reassignments)
+
+	return UsersAddAbsenceResponse{
+		Absence:       ToAbsenceDTO(absence),
+		Reassigned:    reassigned,
+		NotReassigned: notReassigned,
+	}
+}
+
+func ToUsersAbsencesResponse(userID string, absences []*domain.Absence) UsersAbsencesResponse {
+	resp := UsersAbsencesResponse{
+		UserID:   userID,
+		Absences: make([]AbsenceDTO, 0, len(absences)),
+	}
+	for _, a := range absences {
+		resp.Absences = append(resp.Absences, ToAbsenceDTO(a))
+	}
+
+	return resp
+}
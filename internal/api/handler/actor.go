@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader — заголовок, в котором клиент передает инициатора изменений для журнала назначений
+const ActorHeader = "X-Actor-ID"
+
+// Actor кладет инициатора запроса в контекст; без заголовка в историю пишется domain.SystemActor
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if actor := c.GetHeader(ActorHeader); actor != "" {
+			c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), actor))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/api/dto"
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeServerError отвечает на ошибку, которую не разобрал обработчик: 409 CONCURRENT_UPDATE, если транзакция
+// проиграла параллельному запросу и повторы не помогли, иначе 500 INTERNAL_ERROR
+func writeServerError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "CONCURRENT_UPDATE",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+		Error: dto.ErrorDTO{
+			Code:    "INTERNAL_ERROR",
+			Message: err.Error(),
+		},
+	})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/api/dto"
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindPageParams читает sort, order, cursor и limit из query. При ошибке сам пишет ответ 400.
+func bindPageParams(c *gin.Context) (domain.PageParams, bool) {
+	p := domain.PageParams{
+		Sort:   c.Query("sort"),
+		Order:  domain.SortOrder(c.Query("order")),
+		Cursor: c.Query("cursor"),
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeValidationError(c, "limit must be a positive integer")
+			return domain.PageParams{}, false
+		}
+		p.Limit = limit
+	}
+
+	return p, true
+}
+
+// queryBool читает необязательный булев параметр; nil — параметр не задан. При ошибке сам пишет ответ 400.
+func queryBool(c *gin.Context, name string) (*bool, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		writeValidationError(c, fmt.Sprintf("%s must be true or false", name))
+		return nil, false
+	}
+
+	return &v, true
+}
+
+// queryTime читает необязательный параметр в формате RFC 3339. При ошибке сам пишет ответ 400.
+func queryTime(c *gin.Context, name string) (*time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+
+	ts, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		writeValidationError(c, fmt.Sprintf("%s must be RFC 3339 date-time", name))
+		return nil, false
+	}
+
+	return &ts, true
+}
+
+func writeValidationError(c *gin.Context, msg string) {
+	c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+		Error: dto.ErrorDTO{
+			Code:    "VALIDATION_ERROR",
+			Message: msg,
+		},
+	})
+}
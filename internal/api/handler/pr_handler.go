@@ -32,6 +32,7 @@ func (h *PRHandler) Create(c *gin.Context) {
 		Name:     req.PullRequestName,
 		AuthorID: req.AuthorID,
 		Status:   domain.StatusOpen,
+		IsDraft:  req.IsDraft,
 	}
 
 	createdPR, err := h.PRUsecase.CreateWithReviewers(ctx, pr)
@@ -54,17 +55,13 @@ func (h *PRHandler) Create(c *gin.Context) {
 			})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
-				Error: dto.ErrorDTO{
-					Code:    "INTERNAL_ERROR",
-					Message: err.Error(),
-				},
-			})
+			writeServerError(c, err)
 			return
 		}
 	}
 
 	resp := dto.ToPullRequestCreateResponse(createdPR)
+	expandReviewers(c, &resp.PR, createdPR)
 
 	c.JSON(http.StatusCreated, resp)
 }
@@ -83,9 +80,20 @@ func (h *PRHandler) Merge(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.PRUsecase.Merge(ctx, req.PullRequestID)
+	pr, err := h.PRUsecase.Merge(ctx, req.PullRequestID, req.Override)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
+		var blocked *domain.MergeBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "MERGE_BLOCKED",
+					Message: err.Error(),
+					Details: blocked.Missing,
+				},
+			})
+			return
+		case errors.Is(err, domain.ErrNotFound):
 			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
 				Error: dto.ErrorDTO{
 					Code:    "NOT_FOUND",
@@ -93,21 +101,200 @@ func (h *PRHandler) Merge(c *gin.Context) {
 				},
 			})
 			return
+		case errors.Is(err, domain.ErrPRClosed):
+			c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "PR_CLOSED",
+					Message: err.Error(),
+				},
+			})
+			return
+		case errors.Is(err, domain.ErrPRDraft):
+			c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "PR_DRAFT",
+					Message: err.Error(),
+				},
+			})
+			return
+		default:
+			writeServerError(c, err)
+			return
 		}
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+	}
+
+	resp := dto.ToPullRequestMergeResponse(pr)
+	expandReviewers(c, &resp.PR, pr)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PRHandler) Close(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.PullRequestCloseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
 			Error: dto.ErrorDTO{
-				Code:    "INTERNAL_ERROR",
+				Code:    "BAD_REQUEST",
 				Message: err.Error(),
 			},
 		})
 		return
 	}
 
-	resp := dto.ToPullRequestMergeResponse(pr)
+	pr, err := h.PRUsecase.Close(ctx, req.PullRequestID)
+	if err != nil {
+		writeStatusChangeError(c, err)
+		return
+	}
+
+	resp := dto.ToPullRequestCloseResponse(pr)
+	expandReviewers(c, &resp.PR, pr)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PRHandler) Reopen(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.PullRequestReopenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	pr, err := h.PRUsecase.Reopen(ctx, req.PullRequestID)
+	if err != nil {
+		writeStatusChangeError(c, err)
+		return
+	}
+
+	resp := dto.ToPullRequestReopenResponse(pr)
+	expandReviewers(c, &resp.PR, pr)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PRHandler) MarkReady(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req dto.PullRequestMarkReadyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	pr, err := h.PRUsecase.MarkReady(ctx, req.PullRequestID)
+	if err != nil {
+		writeStatusChangeError(c, err)
+		return
+	}
+
+	resp := dto.ToPullRequestMarkReadyResponse(pr)
+	expandReviewers(c, &resp.PR, pr)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PRHandler) Approve(c *gin.Context) {
+	h.submitReview(c, domain.ReviewApproved)
+}
+
+func (h *PRHandler) RequestChanges(c *gin.Context) {
+	h.submitReview(c, domain.ReviewChangesRequested)
+}
+
+func (h *PRHandler) DismissReview(c *gin.Context) {
+	h.submitReview(c, domain.ReviewDismissed)
+}
+
+func (h *PRHandler) submitReview(c *gin.Context, state domain.ReviewState) {
+	ctx := c.Request.Context()
+
+	var req dto.PullRequestReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	pr, err := h.PRUsecase.SubmitReview(ctx, req.PullRequestID, req.UserID, state)
+	if err != nil {
+		writeStatusChangeError(c, err)
+		return
+	}
+
+	resp := dto.ToPullRequestReviewResponse(pr)
+	expandReviewers(c, &resp.PR, pr)
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// expandReviewers разворачивает assigned_reviewers в объекты с решениями, если запрошено ?expand=reviewers
+func expandReviewers(c *gin.Context, d *dto.PullRequestDTO, pr *domain.PullRequest) {
+	if c.Query("expand") == "reviewers" {
+		d.ReviewerStates = dto.ToReviewerStateDTOs(pr.ReviewerStates)
+	}
+}
+
+// writeStatusChangeError отвечает на ошибки смены состояния PR (close, reopen, markReady) и решений ревьюверов
+func writeStatusChangeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrPRMerged):
+		c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "PR_MERGED",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrPRClosed):
+		c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "PR_CLOSED",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrNotAssigned):
+		c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "NOT_ASSIGNED",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: err.Error(),
+			},
+		})
+	default:
+		writeServerError(c, err)
+	}
+}
+
 func (h *PRHandler) Reassign(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -141,6 +328,14 @@ func (h *PRHandler) Reassign(c *gin.Context) {
 				},
 			})
 			return
+		case errors.Is(err, domain.ErrPRClosed):
+			c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "PR_CLOSED",
+					Message: err.Error(),
+				},
+			})
+			return
 		case errors.Is(err, domain.ErrNotAssigned):
 			c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
 				Error: dto.ErrorDTO{
@@ -158,17 +353,115 @@ func (h *PRHandler) Reassign(c *gin.Context) {
 			})
 			return
 		default:
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+			writeServerError(c, err)
+			return
+		}
+	}
+
+	resp := dto.ToPullRequestReassignResponse(pr, newRevID)
+	expandReviewers(c, &resp.PR, pr)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PRHandler) History(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+
+	events, err := h.PRUsecase.History(ctx, prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
 				Error: dto.ErrorDTO{
-					Code:    "INTERNAL_ERROR",
+					Code:    "NOT_FOUND",
 					Message: err.Error(),
 				},
 			})
 			return
 		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
 	}
 
-	resp := dto.ToPullRequestReassignResponse(pr, newRevID)
+	c.JSON(http.StatusOK, dto.ToPullRequestHistoryResponse(prID, events))
+}
 
-	c.JSON(http.StatusOK, resp)
+func (h *PRHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		writeValidationError(c, "pull_request_id is required")
+		return
+	}
+
+	details, err := h.PRUsecase.Get(ctx, prID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "NOT_FOUND",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPullRequestGetResponse(details))
+}
+
+func (h *PRHandler) List(c *gin.Context) {
+	page, ok := bindPageParams(c)
+	if !ok {
+		return
+	}
+	from, ok := queryTime(c, "created_from")
+	if !ok {
+		return
+	}
+	to, ok := queryTime(c, "created_to")
+	if !ok {
+		return
+	}
+
+	filter := domain.PRFilter{
+		Status:      domain.PRStatus(c.Query("status")),
+		AuthorID:    c.Query("author_id"),
+		ReviewerID:  c.Query("reviewer_id"),
+		TeamName:    c.Query("team_name"),
+		CreatedFrom: from,
+		CreatedTo:   to,
+		PageParams:  page,
+	}
+
+	res, err := h.PRUsecase.List(c.Request.Context(), filter)
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPullRequestListResponse(res))
 }
@@ -5,28 +5,65 @@ import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type mockPRUsecase struct {
 	createFn   func(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error)
-	mergeFn    func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	mergeFn    func(ctx context.Context, prID string, override bool) (*domain.PullRequest, error)
 	reassignFn func(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error)
+	historyFn  func(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error)
+	closeFn    func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	reopenFn   func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	readyFn    func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	reviewFn   func(ctx context.Context, prID, userID string, state domain.ReviewState) (*domain.PullRequest, error)
+	listFn     func(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error)
+	getFn      func(ctx context.Context, prID string) (*domain.PRDetails, error)
+}
+
+func (m *mockPRUsecase) Get(ctx context.Context, prID string) (*domain.PRDetails, error) {
+	return m.getFn(ctx, prID)
+}
+
+func (m *mockPRUsecase) List(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+	return m.listFn(ctx, filter)
 }
 
 func (m *mockPRUsecase) CreateWithReviewers(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
 	return m.createFn(ctx, pr)
 }
 
-func (m *mockPRUsecase) Merge(ctx context.Context, prID string) (*domain.PullRequest, error) {
-	return m.mergeFn(ctx, prID)
+func (m *mockPRUsecase) Merge(ctx context.Context, prID string, override bool) (*domain.PullRequest, error) {
+	return m.mergeFn(ctx, prID, override)
 }
 
 func (m *mockPRUsecase) Reassign(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error) {
 	return m.reassignFn(ctx, prID, oldReviewerID)
 }
 
+func (m *mockPRUsecase) Close(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return m.closeFn(ctx, prID)
+}
+
+func (m *mockPRUsecase) Reopen(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return m.reopenFn(ctx, prID)
+}
+
+func (m *mockPRUsecase) MarkReady(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return m.readyFn(ctx, prID)
+}
+
+func (m *mockPRUsecase) SubmitReview(ctx context.Context, prID, userID string, state domain.ReviewState) (*domain.PullRequest, error) {
+	return m.reviewFn(ctx, prID, userID, state)
+}
+
+func (m *mockPRUsecase) History(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+	return m.historyFn(ctx, prID)
+}
+
 func TestPRHandlerCreate_Success(t *testing.T) {
 	handler := &PRHandler{
 		PRUsecase: &mockPRUsecase{
@@ -97,7 +134,7 @@ func TestPRHandlerCreate_NotFound(t *testing.T) {
 func TestPRHandlerMerge_NotFound(t *testing.T) {
 	handler := &PRHandler{
 		PRUsecase: &mockPRUsecase{
-			mergeFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			mergeFn: func(ctx context.Context, prID string, override bool) (*domain.PullRequest, error) {
 				return nil, domain.ErrNotFound
 			},
 		},
@@ -137,3 +174,483 @@ func TestPRHandlerReassign_NoCandidate(t *testing.T) {
 		t.Fatalf("unexpected error code: %+v", resp)
 	}
 }
+
+func TestPRHandlerReassign_ConcurrentUpdate(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			reassignFn: func(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error) {
+				return nil, "", fmt.Errorf("%w: duplicate key", domain.ErrConcurrentUpdate)
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/reassign", dto.PullRequestReassignRequest{
+		PullRequestID: "pr1",
+		OldUserID:     "u2",
+	})
+
+	handler.Reassign(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "CONCURRENT_UPDATE" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestPRHandlerHistory_Success(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			historyFn: func(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+				return []*domain.AssignmentEvent{
+					{ID: 1, PRID: prID, Type: domain.EventAssigned, UserID: "u2", Actor: domain.SystemActor, Reason: domain.ReasonPRCreated},
+					{ID: 2, PRID: prID, Type: domain.EventReassigned, UserID: "u3", PreviousUserID: "u2", Actor: "admin", Reason: domain.ReasonManualReassign},
+				}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/pullRequest/history?pull_request_id=pr1", nil)
+
+	handler.History(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.PullRequestHistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.PullRequestID != "pr1" || len(resp.Events) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.Events[1].PreviousUserID != "u2" || resp.Events[1].Actor != "admin" {
+		t.Fatalf("unexpected reassignment event: %+v", resp.Events[1])
+	}
+}
+
+func TestPRHandlerHistory_MissingID(t *testing.T) {
+	handler := &PRHandler{PRUsecase: &mockPRUsecase{}}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/pullRequest/history", nil)
+
+	handler.History(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "VALIDATION_ERROR" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestPRHandlerGet_Success(t *testing.T) {
+	created := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			getFn: func(ctx context.Context, prID string) (*domain.PRDetails, error) {
+				return &domain.PRDetails{
+					PR: &domain.PullRequest{
+						ID: prID, AuthorID: "u1", Status: domain.StatusOpen, Reviewers: []string{"u2"},
+						ReviewerStates: []*domain.ReviewerState{{UserID: "u2", State: domain.ReviewApproved}},
+						CreatedAt:      &created,
+					},
+					Author:    &domain.User{ID: "u1", Name: "Alice", TeamName: "backend", IsActive: true},
+					Reviewers: []*domain.User{{ID: "u2", Name: "Bob", TeamName: "backend", IsActive: true}},
+					Timeline: []*domain.TimelineEvent{
+						{Type: domain.TimelineCreated, At: created, UserID: "u1"},
+						{Type: domain.TimelineAssigned, At: created, UserID: "u2", Actor: domain.SystemActor, Reason: domain.ReasonPRCreated},
+					},
+				}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/pullRequest/get?pull_request_id=pr1", nil)
+
+	handler.Get(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		PR        map[string]any           `json:"pr"`
+		Author    dto.UserDTO              `json:"author"`
+		Reviewers []dto.ReviewerProfileDTO `json:"reviewers"`
+		Timeline  []dto.TimelineEventDTO   `json:"timeline"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.PR["pull_request_id"] != "pr1" || resp.Author.Username != "Alice" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(resp.Reviewers) != 1 || resp.Reviewers[0].Username != "Bob" || resp.Reviewers[0].State != "APPROVED" {
+		t.Fatalf("unexpected reviewers: %+v", resp.Reviewers)
+	}
+	if len(resp.Timeline) != 2 || resp.Timeline[0].Type != "CREATED" || resp.Timeline[1].Reason != domain.ReasonPRCreated {
+		t.Fatalf("unexpected timeline: %+v", resp.Timeline)
+	}
+}
+
+func TestPRHandlerGet_NotFound(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			getFn: func(ctx context.Context, prID string) (*domain.PRDetails, error) {
+				return nil, domain.ErrNotFound
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/pullRequest/get?pull_request_id=missing", nil)
+
+	handler.Get(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestActorMiddleware_PutsHeaderIntoContext(t *testing.T) {
+	var got string
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			historyFn: func(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+				got = domain.ActorFromContext(ctx)
+				return nil, nil
+			},
+		},
+	}
+
+	_, c := newRecorderWithRequest(t, http.MethodGet, "/pullRequest/history?pull_request_id=pr1", nil)
+	c.Request.Header.Set(ActorHeader, "lead")
+
+	Actor()(c)
+	handler.History(c)
+
+	if got != "lead" {
+		t.Fatalf("expected actor from header, got %q", got)
+	}
+}
+
+func TestPRHandlerClose_Success(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			closeFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+				return &domain.PullRequest{ID: prID, AuthorID: "u1", Status: domain.StatusClosed, Reviewers: []string{"u2"}}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/close", dto.PullRequestCloseRequest{PullRequestID: "pr1"})
+
+	handler.Close(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.PullRequestCloseResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.PR.Status != string(domain.StatusClosed) {
+		t.Fatalf("unexpected status: %s", resp.PR.Status)
+	}
+}
+
+func TestPRHandlerClose_Merged(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			closeFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+				return nil, domain.ErrPRMerged
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/close", dto.PullRequestCloseRequest{PullRequestID: "pr1"})
+
+	handler.Close(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "PR_MERGED" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestPRHandlerReopen_NotFound(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			reopenFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+				return nil, domain.ErrNotFound
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/reopen", dto.PullRequestReopenRequest{PullRequestID: "missing"})
+
+	handler.Reopen(c)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestPRHandlerReassign_Closed(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			reassignFn: func(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error) {
+				return nil, "", domain.ErrPRClosed
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/reassign", dto.PullRequestReassignRequest{
+		PullRequestID: "pr1",
+		OldUserID:     "u2",
+	})
+
+	handler.Reassign(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "PR_CLOSED" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestPRHandlerCreate_Draft(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			createFn: func(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
+				if !pr.IsDraft {
+					t.Fatalf("expected draft flag to be passed to usecase")
+				}
+				return pr, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/create", dto.PullRequestCreateRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "wip",
+		AuthorID:        "u1",
+		IsDraft:         true,
+	})
+
+	handler.Create(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+
+	var resp dto.PullRequestCreateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.PR.IsDraft || len(resp.PR.AssignedReviewers) != 0 {
+		t.Fatalf("unexpected draft response: %+v", resp.PR)
+	}
+}
+
+func TestPRHandlerMarkReady_Closed(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			readyFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+				return nil, domain.ErrPRClosed
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/markReady", dto.PullRequestMarkReadyRequest{PullRequestID: "pr1"})
+
+	handler.MarkReady(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "PR_CLOSED" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestPRHandlerApprove_ExpandsReviewers(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			reviewFn: func(ctx context.Context, prID, userID string, state domain.ReviewState) (*domain.PullRequest, error) {
+				if state != domain.ReviewApproved {
+					t.Fatalf("unexpected state: %s", state)
+				}
+				return &domain.PullRequest{
+					ID:        prID,
+					Status:    domain.StatusOpen,
+					Reviewers: []string{"u2", "u3"},
+					ReviewerStates: []*domain.ReviewerState{
+						{UserID: "u2", State: domain.ReviewApproved},
+						{UserID: "u3", State: domain.ReviewPending},
+					},
+				}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/approve?expand=reviewers", dto.PullRequestReviewRequest{
+		PullRequestID: "pr1",
+		UserID:        "u2",
+	})
+
+	handler.Approve(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		PR struct {
+			AssignedReviewers []dto.ReviewerStateDTO `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.PR.AssignedReviewers) != 2 || resp.PR.AssignedReviewers[0].State != string(domain.ReviewApproved) {
+		t.Fatalf("unexpected expanded reviewers: %+v", resp.PR.AssignedReviewers)
+	}
+}
+
+func TestPRHandlerRequestChanges_NotAssigned(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			reviewFn: func(ctx context.Context, prID, userID string, state domain.ReviewState) (*domain.PullRequest, error) {
+				return nil, domain.ErrNotAssigned
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/requestChanges", dto.PullRequestReviewRequest{
+		PullRequestID: "pr1",
+		UserID:        "u9",
+	})
+
+	handler.RequestChanges(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "NOT_ASSIGNED" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestPRHandlerMerge_Blocked(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			mergeFn: func(ctx context.Context, prID string, override bool) (*domain.PullRequest, error) {
+				return nil, &domain.MergeBlockedError{Missing: []string{"1 of 2 required approvals"}}
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/merge", dto.PullRequestMergeRequest{PullRequestID: "pr1"})
+
+	handler.Merge(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "MERGE_BLOCKED" || len(resp.Error.Details) != 1 {
+		t.Fatalf("unexpected error: %+v", resp)
+	}
+}
+
+func TestPRHandlerMerge_PassesOverride(t *testing.T) {
+	var got bool
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			mergeFn: func(ctx context.Context, prID string, override bool) (*domain.PullRequest, error) {
+				got = override
+				return &domain.PullRequest{ID: prID, Status: domain.StatusMerged, MergeOverrideBy: "lead"}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/pullRequest/merge", dto.PullRequestMergeRequest{PullRequestID: "pr1", Override: true})
+
+	handler.Merge(c)
+
+	if w.Code != http.StatusOK || !got {
+		t.Fatalf("expected 200 with override passed, got %d (override=%v)", w.Code, got)
+	}
+}
+
+func TestPRHandlerList_ParsesFilters(t *testing.T) {
+	handler := &PRHandler{
+		PRUsecase: &mockPRUsecase{
+			listFn: func(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+				if filter.Status != domain.StatusOpen || filter.ReviewerID != "u2" || filter.TeamName != "backend" {
+					t.Fatalf("unexpected filter: %+v", filter)
+				}
+				if filter.CreatedFrom == nil || filter.CreatedTo != nil {
+					t.Fatalf("unexpected created range: %+v", filter)
+				}
+				if filter.Sort != "id" || filter.Order != domain.SortAsc || filter.Limit != 2 || filter.Cursor != "abc" {
+					t.Fatalf("unexpected page params: %+v", filter.PageParams)
+				}
+				return &domain.Page[*domain.PullRequest]{
+					Items:      []*domain.PullRequest{{ID: "pr1", Status: domain.StatusOpen, Reviewers: []string{"u2"}}},
+					NextCursor: "next",
+				}, nil
+			},
+		},
+	}
+
+	target := "/pullRequest/list?status=OPEN&reviewer_id=u2&team_name=backend&created_from=2025-01-01T00:00:00Z" +
+		"&sort=id&order=asc&limit=2&cursor=abc"
+	w, c := newRecorderWithRequest(t, http.MethodGet, target, nil)
+
+	handler.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.PullRequestListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.PullRequests) != 1 || resp.NextCursor != "next" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestPRHandlerList_InvalidLimit(t *testing.T) {
+	handler := &PRHandler{PRUsecase: &mockPRUsecase{}}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/pullRequest/list?limit=zero", nil)
+
+	handler.List(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/api/dto"
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	StatsUsecase domain.StatsUsecase
+}
+
+func (sh *StatsHandler) Users(c *gin.Context) {
+	filter, ok := bindStatsFilter(c)
+	if !ok {
+		return
+	}
+
+	stats, err := sh.StatsUsecase.UserAssignments(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "VALIDATION_ERROR",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatsUsersResponse(stats))
+}
+
+func (sh *StatsHandler) PullRequests(c *gin.Context) {
+	filter, ok := bindStatsFilter(c)
+	if !ok {
+		return
+	}
+
+	stats, err := sh.StatsUsecase.PRReassignments(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "VALIDATION_ERROR",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatsPullRequestsResponse(stats))
+}
+
+func (sh *StatsHandler) Teams(c *gin.Context) {
+	filter, ok := bindStatsFilter(c)
+	if !ok {
+		return
+	}
+
+	stats, err := sh.StatsUsecase.TeamTotals(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "VALIDATION_ERROR",
+					Message: err.Error(),
+				},
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "INTERNAL_ERROR",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStatsTeamsResponse(stats))
+}
+
+// bindStatsFilter читает team_name, from и to (RFC 3339) из query. При ошибке сам пишет ответ 400.
+func bindStatsFilter(c *gin.Context) (domain.StatsFilter, bool) {
+	filter := domain.StatsFilter{TeamName: c.Query("team_name")}
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "VALIDATION_ERROR",
+					Message: fmt.Sprintf("%s must be RFC 3339 date-time", p.name),
+				},
+			})
+			return domain.StatsFilter{}, false
+		}
+		*p.dst = &ts
+	}
+
+	return filter, true
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/api/dto"
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+type mockStatsUsecase struct {
+	usersFn func(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error)
+	prsFn   func(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error)
+	teamsFn func(ctx context.Context, filter domain.StatsFilter) ([]*domain.TeamStats, error)
+}
+
+func (m *mockStatsUsecase) UserAssignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error) {
+	return m.usersFn(ctx, filter)
+}
+
+func (m *mockStatsUsecase) PRReassignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error) {
+	return m.prsFn(ctx, filter)
+}
+
+func (m *mockStatsUsecase) TeamTotals(ctx context.Context, filter domain.StatsFilter) ([]*domain.TeamStats, error) {
+	return m.teamsFn(ctx, filter)
+}
+
+func TestStatsHandlerUsers_Success(t *testing.T) {
+	handler := &StatsHandler{
+		StatsUsecase: &mockStatsUsecase{
+			usersFn: func(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error) {
+				if filter.TeamName != "team" || filter.From == nil || filter.To != nil {
+					t.Fatalf("unexpected filter: %+v", filter)
+				}
+				return []*domain.UserAssignmentStats{{UserID: "u1", Total: 4, Open: 3, Merged: 1}}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/stats/users?team_name=team&from=2025-10-01T00:00:00Z", nil)
+
+	handler.Users(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.StatsUsersResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].Open != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestStatsHandlerTeams_InvalidDate(t *testing.T) {
+	handler := &StatsHandler{
+		StatsUsecase: &mockStatsUsecase{},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/stats/teams?from=yesterday", nil)
+
+	handler.Teams(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "VALIDATION_ERROR" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestStatsHandlerPullRequests_InvalidRange(t *testing.T) {
+	handler := &StatsHandler{
+		StatsUsecase: &mockStatsUsecase{
+			prsFn: func(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error) {
+				return nil, domain.ErrInvalidInput
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/stats/pullRequests?from=2025-10-02T00:00:00Z&to=2025-10-01T00:00:00Z", nil)
+
+	handler.PullRequests(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
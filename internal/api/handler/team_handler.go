@@ -40,49 +40,72 @@ func (th *TeamHandler) Add(c *gin.Context) {
 		})
 	}
 
-	createdTeam, err := th.TeamUsecase.Add(ctx, team)
+	res, err := th.TeamUsecase.Add(ctx, team, req.MoveExisting)
 	if err != nil {
-		if errors.Is(err, domain.ErrAlreadyExists) {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToTeamAddResponse(res))
+}
+
+func (th *TeamHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	team, err := th.TeamUsecase.ListByName(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
 				Error: dto.ErrorDTO{
-					Code:    "TEAM_EXISTS",
+					Code:    "NOT_FOUND",
 					Message: err.Error(),
 				},
 			})
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
-			Error: dto.ErrorDTO{
-				Code:    "INTERNAL_ERROR",
-				Message: err.Error(),
-			},
-		})
+		writeServerError(c, err)
 		return
 	}
 
-	resp := dto.TeamAddResponse{
-		Team: dto.ToTeamDTO(createdTeam),
-	}
-
-	c.JSON(http.StatusCreated, resp)
+	c.JSON(http.StatusOK, dto.ToTeamDTO(team))
 }
 
-func (th *TeamHandler) Get(c *gin.Context) {
+func (th *TeamHandler) DeactivateUsers(c *gin.Context) {
 	ctx := c.Request.Context()
+	var req dto.TeamDeactivateUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
 
-	teamName := c.Query("team_name")
-	if teamName == "" {
+	if req.TeamName == "" || (len(req.UserIDs) == 0 && !req.AllExcept) {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
 			Error: dto.ErrorDTO{
 				Code:    "VALIDATION_ERROR",
-				Message: "team_name is required",
+				Message: "team_name and user_ids (or all_except) are required",
 			},
 		})
 		return
 	}
 
-	team, err := th.TeamUsecase.ListByName(ctx, teamName)
+	res, err := th.TeamUsecase.DeactivateUsers(ctx, req.TeamName, req.UserIDs, req.AllExcept)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
@@ -94,14 +117,308 @@ func (th *TeamHandler) Get(c *gin.Context) {
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+		writeServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTeamDeactivateUsersResponse(res))
+}
+
+func (th *TeamHandler) AddMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TeamAddMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
 			Error: dto.ErrorDTO{
-				Code:    "INTERNAL_ERROR",
+				Code:    "BAD_REQUEST",
 				Message: err.Error(),
 			},
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ToTeamDTO(team))
+	if req.TeamName == "" || len(req.Members) == 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: "team_name and members are required",
+			},
+		})
+		return
+	}
+
+	members := make([]*domain.User, 0, len(req.Members))
+	for _, m := range req.Members {
+		members = append(members, &domain.User{
+			ID:       m.UserID,
+			Name:     m.Username,
+			TeamName: req.TeamName,
+			IsActive: m.IsActive,
+		})
+	}
+
+	res, err := th.TeamUsecase.AddMembers(ctx, req.TeamName, members, req.Move)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTeamAddMembersResponse(res))
+}
+
+func (th *TeamHandler) RemoveMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TeamRemoveMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" || len(req.UserIDs) == 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: "team_name and user_ids are required",
+			},
+		})
+		return
+	}
+
+	res, err := th.TeamUsecase.RemoveMembers(ctx, req.TeamName, req.UserIDs)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTeamRemoveMembersResponse(res))
+}
+
+func (th *TeamHandler) Rename(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TeamRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" || req.NewTeamName == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: "team_name and new_team_name are required",
+			},
+		})
+		return
+	}
+
+	team, err := th.TeamUsecase.Rename(ctx, req.TeamName, req.NewTeamName)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TeamResponse{Team: dto.ToTeamDTO(team)})
+}
+
+func (th *TeamHandler) GetSettings(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		writeValidationError(c, "team_name is required")
+		return
+	}
+
+	settings, err := th.TeamUsecase.GetSettings(c.Request.Context(), teamName)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TeamSettingsResponse{Settings: dto.ToTeamSettingsDTO(settings)})
+}
+
+func (th *TeamHandler) UpdateSettings(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TeamSettingsDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" {
+		writeValidationError(c, "team_name is required")
+		return
+	}
+
+	settings, err := th.TeamUsecase.UpdateSettings(ctx, &domain.TeamSettings{
+		TeamName:              req.TeamName,
+		MinReviewers:          req.MinReviewers,
+		MaxReviewers:          req.MaxReviewers,
+		FallbackTeams:         req.FallbackTeams,
+		DefaultMaxOpenReviews: req.DefaultMaxOpenReviews,
+	})
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TeamSettingsResponse{Settings: dto.ToTeamSettingsDTO(settings)})
+}
+
+func (th *TeamHandler) Archive(c *gin.Context) {
+	th.setArchived(c, true)
+}
+
+func (th *TeamHandler) Unarchive(c *gin.Context) {
+	th.setArchived(c, false)
+}
+
+func (th *TeamHandler) setArchived(c *gin.Context, archived bool) {
+	ctx := c.Request.Context()
+	var req dto.TeamArchiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	team, err := th.TeamUsecase.SetArchived(ctx, req.TeamName, archived)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TeamResponse{Team: dto.ToTeamDTO(team)})
+}
+
+func (th *TeamHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TeamDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	res, err := th.TeamUsecase.Delete(ctx, req.TeamName, req.Force)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTeamDeleteResponse(res))
+}
+
+func writeTeamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeValidationError(c, err.Error())
+	case errors.Is(err, domain.ErrAlreadyExists):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "TEAM_EXISTS",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrTeamHasOpenReviews):
+		c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "TEAM_HAS_OPEN_REVIEWS",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrOtherTeam):
+		var conflict *domain.OtherTeamError
+		var details []string
+		if errors.As(err, &conflict) {
+			details = conflict.Details()
+		}
+		c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "USER_IN_OTHER_TEAM",
+				Message: err.Error(),
+				Details: details,
+			},
+		})
+	default:
+		writeServerError(c, err)
+	}
+}
+
+func (th *TeamHandler) List(c *gin.Context) {
+	page, ok := bindPageParams(c)
+	if !ok {
+		return
+	}
+	archived, ok := queryBool(c, "archived")
+	if !ok {
+		return
+	}
+
+	res, err := th.TeamUsecase.List(c.Request.Context(), domain.TeamFilter{Archived: archived, PageParams: page})
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTeamListResponse(res))
+}
+
+// writeListError отвечает на ошибки списков: ErrInvalidInput (сортировка, курсор, лимит) — 400
+func writeListError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrInvalidInput) {
+		writeValidationError(c, err.Error())
+		return
+	}
+
+	writeServerError(c, err)
 }
@@ -4,27 +4,76 @@ import (
 	"avito-backend-trainee-autumn-2025/internal/api/dto"
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"reflect"
 	"testing"
+	"time"
 )
 
 type mockTeamUsecase struct {
-	addFn       func(ctx context.Context, team *domain.Team) (*domain.Team, error)
-	listByNameF func(ctx context.Context, name string) (*domain.Team, error)
+	addFn        func(ctx context.Context, team *domain.Team, moveExisting bool) (*domain.TeamMembersChange, error)
+	listByNameF  func(ctx context.Context, name string) (*domain.Team, error)
+	deactivateFn func(ctx context.Context, teamName string, userIDs []string, allExcept bool) (*domain.TeamDeactivation, error)
+	addMembersFn func(ctx context.Context, teamName string, members []*domain.User, move bool) (*domain.TeamMembersChange, error)
+	removeFn     func(ctx context.Context, teamName string, userIDs []string) (*domain.TeamMembersChange, error)
+	renameFn     func(ctx context.Context, teamName, newName string) (*domain.Team, error)
+	archiveFn    func(ctx context.Context, teamName string, archived bool) (*domain.Team, error)
+	deleteFn     func(ctx context.Context, teamName string, force bool) (*domain.TeamMembersChange, error)
+	listFn       func(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error)
+	getSettFn    func(ctx context.Context, teamName string) (*domain.TeamSettings, error)
+	updateSettFn func(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error)
 }
 
-func (m *mockTeamUsecase) Add(ctx context.Context, team *domain.Team) (*domain.Team, error) {
-	return m.addFn(ctx, team)
+func (m *mockTeamUsecase) GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	return m.getSettFn(ctx, teamName)
+}
+
+func (m *mockTeamUsecase) UpdateSettings(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+	return m.updateSettFn(ctx, settings)
+}
+
+func (m *mockTeamUsecase) List(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error) {
+	return m.listFn(ctx, filter)
+}
+
+func (m *mockTeamUsecase) Add(ctx context.Context, team *domain.Team, moveExisting bool) (*domain.TeamMembersChange, error) {
+	return m.addFn(ctx, team, moveExisting)
 }
 
 func (m *mockTeamUsecase) ListByName(ctx context.Context, name string) (*domain.Team, error) {
 	return m.listByNameF(ctx, name)
 }
 
+func (m *mockTeamUsecase) DeactivateUsers(ctx context.Context, teamName string, userIDs []string, allExcept bool) (*domain.TeamDeactivation, error) {
+	return m.deactivateFn(ctx, teamName, userIDs, allExcept)
+}
+
+func (m *mockTeamUsecase) AddMembers(ctx context.Context, teamName string, members []*domain.User, move bool) (*domain.TeamMembersChange, error) {
+	return m.addMembersFn(ctx, teamName, members, move)
+}
+
+func (m *mockTeamUsecase) RemoveMembers(ctx context.Context, teamName string, userIDs []string) (*domain.TeamMembersChange, error) {
+	return m.removeFn(ctx, teamName, userIDs)
+}
+
+func (m *mockTeamUsecase) Rename(ctx context.Context, teamName, newName string) (*domain.Team, error) {
+	return m.renameFn(ctx, teamName, newName)
+}
+
+func (m *mockTeamUsecase) SetArchived(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+	return m.archiveFn(ctx, teamName, archived)
+}
+
+func (m *mockTeamUsecase) Delete(ctx context.Context, teamName string, force bool) (*domain.TeamMembersChange, error) {
+	return m.deleteFn(ctx, teamName, force)
+}
+
 func TestTeamHandlerAdd_Duplicate(t *testing.T) {
 	handler := &TeamHandler{
 		TeamUsecase: &mockTeamUsecase{
-			addFn: func(ctx context.Context, team *domain.Team) (*domain.Team, error) {
+			addFn: func(ctx context.Context, team *domain.Team, moveExisting bool) (*domain.TeamMembersChange, error) {
 				return nil, domain.ErrAlreadyExists
 			},
 		},
@@ -49,6 +98,74 @@ func TestTeamHandlerAdd_Duplicate(t *testing.T) {
 	}
 }
 
+func TestTeamHandlerAdd_MembersOfOtherTeams(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			addFn: func(ctx context.Context, team *domain.Team, moveExisting bool) (*domain.TeamMembersChange, error) {
+				return nil, &domain.OtherTeamError{Conflicts: []domain.TeamConflict{
+					{UserID: "u1", TeamName: "payments"},
+					{UserID: "u2", TeamName: "backend"},
+				}}
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/add", dto.TeamAddRequest{
+		TeamName: "team",
+		Members:  []dto.TeamMemberDTO{{UserID: "u1"}, {UserID: "u2"}},
+	})
+
+	handler.Add(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "USER_IN_OTHER_TEAM" || len(resp.Error.Details) != 2 || resp.Error.Details[0] != "u1: payments" {
+		t.Fatalf("unexpected error: %+v", resp)
+	}
+}
+
+func TestTeamHandlerAdd_MoveExisting(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			addFn: func(ctx context.Context, team *domain.Team, moveExisting bool) (*domain.TeamMembersChange, error) {
+				if !moveExisting {
+					t.Fatalf("expected move_existing to be passed")
+				}
+				return &domain.TeamMembersChange{
+					Team:    team,
+					UserIDs: []string{"u1"},
+					Reassignments: []*domain.ReviewReassignment{
+						{PRID: "pr1", OldReviewerID: "u1", NewReviewerID: "u3"},
+					},
+				}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/add", dto.TeamAddRequest{
+		TeamName:     "team",
+		Members:      []dto.TeamMemberDTO{{UserID: "u1"}},
+		MoveExisting: true,
+	})
+
+	handler.Add(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", w.Code)
+	}
+
+	var resp dto.TeamAddResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Team.TeamName != "team" || len(resp.MovedUserIDs) != 1 || len(resp.Reassigned) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
 func TestTeamHandlerGet_Validation(t *testing.T) {
 	handler := &TeamHandler{
 		TeamUsecase: &mockTeamUsecase{},
@@ -62,3 +179,342 @@ func TestTeamHandlerGet_Validation(t *testing.T) {
 		t.Fatalf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestTeamHandlerDeactivateUsers_Validation(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/deactivateUsers", dto.TeamDeactivateUsersRequest{
+		TeamName: "team",
+	})
+
+	handler.DeactivateUsers(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "VALIDATION_ERROR" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestTeamHandlerDeactivateUsers_Success(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			deactivateFn: func(ctx context.Context, teamName string, userIDs []string, allExcept bool) (*domain.TeamDeactivation, error) {
+				if !allExcept || len(userIDs) != 1 || userIDs[0] != "u1" {
+					t.Fatalf("unexpected params: %v %v", userIDs, allExcept)
+				}
+				return &domain.TeamDeactivation{
+					TeamName:    teamName,
+					Deactivated: []string{"u2", "u3"},
+					Reassignments: []*domain.ReviewReassignment{
+						{PRID: "pr1", OldReviewerID: "u2", NewReviewerID: "u4"},
+						{PRID: "pr1", OldReviewerID: "u3"},
+					},
+				}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/deactivateUsers", dto.TeamDeactivateUsersRequest{
+		TeamName:  "team",
+		UserIDs:   []string{"u1"},
+		AllExcept: true,
+	})
+
+	handler.DeactivateUsers(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.TeamDeactivateUsersResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.DeactivatedUserIDs) != 2 || len(resp.Reassigned) != 1 || len(resp.NotReassigned) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTeamHandlerDeactivateUsers_ConcurrentUpdate(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			deactivateFn: func(ctx context.Context, teamName string, userIDs []string, allExcept bool) (*domain.TeamDeactivation, error) {
+				return nil, fmt.Errorf("%w: serialization failure", domain.ErrConcurrentUpdate)
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/deactivateUsers", dto.TeamDeactivateUsersRequest{
+		TeamName: "team",
+		UserIDs:  []string{"u1"},
+	})
+
+	handler.DeactivateUsers(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "CONCURRENT_UPDATE" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestTeamHandlerAddMembers_OtherTeam(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			addMembersFn: func(ctx context.Context, teamName string, members []*domain.User, move bool) (*domain.TeamMembersChange, error) {
+				if move {
+					t.Fatalf("move must be false by default")
+				}
+				return nil, domain.ErrOtherTeam
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/addMembers", dto.TeamAddMembersRequest{
+		TeamName: "team",
+		Members:  []dto.TeamMemberDTO{{UserID: "u1", Username: "One", IsActive: true}},
+	})
+
+	handler.AddMembers(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "USER_IN_OTHER_TEAM" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestTeamHandlerRemoveMembers_Validation(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/removeMembers", dto.TeamRemoveMembersRequest{
+		TeamName: "team",
+	})
+
+	handler.RemoveMembers(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestTeamHandlerRemoveMembers_Success(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			removeFn: func(ctx context.Context, teamName string, userIDs []string) (*domain.TeamMembersChange, error) {
+				return &domain.TeamMembersChange{
+					Team:    &domain.Team{Name: teamName, Members: []*domain.User{{ID: "u2"}}},
+					UserIDs: userIDs,
+					Reassignments: []*domain.ReviewReassignment{
+						{PRID: "pr1", OldReviewerID: "u1", NewReviewerID: "u2"},
+					},
+				}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/removeMembers", dto.TeamRemoveMembersRequest{
+		TeamName: "team",
+		UserIDs:  []string{"u1"},
+	})
+
+	handler.RemoveMembers(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.TeamRemoveMembersResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.RemovedUserIDs) != 1 || len(resp.Team.Members) != 1 || len(resp.Reassigned) != 1 || resp.NotReassigned == nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTeamHandlerRename_Duplicate(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			renameFn: func(ctx context.Context, teamName, newName string) (*domain.Team, error) {
+				return nil, domain.ErrAlreadyExists
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/rename", dto.TeamRenameRequest{
+		TeamName:    "team",
+		NewTeamName: "other",
+	})
+
+	handler.Rename(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "TEAM_EXISTS" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestTeamHandlerUpdateSettings_Success(t *testing.T) {
+	var got *domain.TeamSettings
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			updateSettFn: func(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+				got = settings
+				return settings, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/settings", dto.TeamSettingsDTO{
+		TeamName:      "team",
+		MinReviewers:  1,
+		MaxReviewers:  3,
+		FallbackTeams: []string{"platform", "infra"},
+	})
+
+	handler.UpdateSettings(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if got == nil || got.MinReviewers != 1 || got.MaxReviewers != 3 || !reflect.DeepEqual(got.FallbackTeams, []string{"platform", "infra"}) {
+		t.Fatalf("unexpected settings passed to usecase: %+v", got)
+	}
+
+	var resp dto.TeamSettingsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Settings.TeamName != "team" || resp.Settings.MaxReviewers != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestTeamHandlerUpdateSettings_Invalid(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			updateSettFn: func(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+				return nil, fmt.Errorf("min_reviewers must be between 0 and max_reviewers: %w", domain.ErrInvalidInput)
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/settings", dto.TeamSettingsDTO{
+		TeamName:     "team",
+		MinReviewers: 3,
+		MaxReviewers: 2,
+	})
+
+	handler.UpdateSettings(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "VALIDATION_ERROR" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestTeamHandlerArchive_Success(t *testing.T) {
+	now := time.Now()
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			archiveFn: func(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+				if !archived {
+					t.Fatalf("expected archive")
+				}
+				return &domain.Team{Name: teamName, ArchivedAt: &now}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/archive", dto.TeamArchiveRequest{TeamName: "team"})
+
+	handler.Archive(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.TeamResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.Team.IsArchived {
+		t.Fatalf("expected archived team, got %+v", resp)
+	}
+}
+
+func TestTeamHandlerDelete_OpenReviews(t *testing.T) {
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			deleteFn: func(ctx context.Context, teamName string, force bool) (*domain.TeamMembersChange, error) {
+				return nil, domain.ErrTeamHasOpenReviews
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/team/delete", dto.TeamDeleteRequest{TeamName: "team"})
+
+	handler.Delete(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "TEAM_HAS_OPEN_REVIEWS" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestTeamHandlerList_Success(t *testing.T) {
+	now := time.Now()
+	handler := &TeamHandler{
+		TeamUsecase: &mockTeamUsecase{
+			listFn: func(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error) {
+				if filter.Archived == nil || !*filter.Archived {
+					t.Fatalf("expected archived=true, got %+v", filter)
+				}
+				return &domain.Page[*domain.Team]{Items: []*domain.Team{{Name: "legacy", ArchivedAt: &now}}}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/team/list?archived=true", nil)
+
+	handler.List(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.TeamListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Teams) != 1 || !resp.Teams[0].IsArchived || resp.NextCursor != "" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
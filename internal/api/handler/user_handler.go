@@ -29,7 +29,7 @@ func (uh *UserHandler) SetIsActive(c *gin.Context) {
 
 	userID, active := req.UserID, req.IsActive
 
-	user, err := uh.UserUsecase.SetIsActive(ctx, userID, active)
+	user, reassignments, err := uh.UserUsecase.SetIsActive(ctx, userID, active)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
@@ -40,23 +40,52 @@ func (uh *UserHandler) SetIsActive(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponseDTO{
+		writeServerError(c, err)
+		return
+	}
+
+	resp := dto.ToUsersSetIsActiveResponse(user, reassignments)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (uh *UserHandler) SetMaxOpenReviews(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.UsersSetMaxOpenReviewsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
 			Error: dto.ErrorDTO{
-				Code:    "INTERNAL_ERROR",
+				Code:    "BAD_REQUEST",
 				Message: err.Error(),
 			},
 		})
 		return
 	}
 
-	resp := dto.UsersSetIsActiveResponse{User: dto.UserDTO{
-		UserID:   user.ID,
-		Username: user.Name,
-		TeamName: user.TeamName,
-		IsActive: user.IsActive,
-	}}
+	if req.UserID == "" {
+		writeValidationError(c, "user_id is required")
+		return
+	}
 
-	c.JSON(http.StatusOK, resp)
+	user, err := uh.UserUsecase.SetMaxOpenReviews(ctx, req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			writeValidationError(c, err.Error())
+		case errors.Is(err, domain.ErrNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
+				Error: dto.ErrorDTO{
+					Code:    "NOT_FOUND",
+					Message: err.Error(),
+				},
+			})
+		default:
+			writeServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UsersSetMaxOpenReviewsResponse{User: dto.ToUserDTO(user)})
 }
 
 func (uh *UserHandler) GetReview(c *gin.Context) {
@@ -73,7 +102,18 @@ func (uh *UserHandler) GetReview(c *gin.Context) {
 		return
 	}
 
-	prs, err := uh.UserUsecase.GetReview(ctx, userID)
+	state := domain.ReviewState(c.Query("state"))
+	if state != "" && !state.Valid() {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "VALIDATION_ERROR",
+				Message: fmt.Sprintf("unknown review state %q", state),
+			},
+		})
+		return
+	}
+
+	prs, err := uh.UserUsecase.GetReview(ctx, userID, state)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
@@ -103,3 +143,124 @@ func (uh *UserHandler) GetReview(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func (uh *UserHandler) List(c *gin.Context) {
+	page, ok := bindPageParams(c)
+	if !ok {
+		return
+	}
+	isActive, ok := queryBool(c, "is_active")
+	if !ok {
+		return
+	}
+
+	filter := domain.UserFilter{
+		TeamName:   c.Query("team_name"),
+		IsActive:   isActive,
+		PageParams: page,
+	}
+
+	res, err := uh.UserUsecase.List(c.Request.Context(), filter)
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToUsersListResponse(res))
+}
+
+func (uh *UserHandler) AddAbsence(c *gin.Context) {
+	var req dto.UsersAddAbsenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.UserID == "" {
+		writeValidationError(c, "user_id is required")
+		return
+	}
+
+	absence, reassignments, err := uh.UserUsecase.AddAbsence(c.Request.Context(), &domain.Absence{
+		UserID:     req.UserID,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		DelegateID: req.DelegateID,
+	})
+	if err != nil {
+		writeAbsenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToUsersAddAbsenceResponse(absence, reassignments))
+}
+
+func (uh *UserHandler) Absences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		writeValidationError(c, "user_id is required")
+		return
+	}
+
+	absences, err := uh.UserUsecase.ListAbsences(c.Request.Context(), userID)
+	if err != nil {
+		writeAbsenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToUsersAbsencesResponse(userID, absences))
+}
+
+func (uh *UserHandler) DeleteAbsence(c *gin.Context) {
+	var req dto.UsersDeleteAbsenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "BAD_REQUEST",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	if req.AbsenceID <= 0 {
+		writeValidationError(c, "absence_id is required")
+		return
+	}
+
+	absence, err := uh.UserUsecase.DeleteAbsence(c.Request.Context(), req.AbsenceID)
+	if err != nil {
+		writeAbsenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.UsersAbsenceResponse{Absence: dto.ToAbsenceDTO(absence)})
+}
+
+func writeAbsenceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		writeValidationError(c, err.Error())
+	case errors.Is(err, domain.ErrAbsenceOverlap):
+		c.JSON(http.StatusConflict, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "ABSENCE_OVERLAP",
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponseDTO{
+			Error: dto.ErrorDTO{
+				Code:    "NOT_FOUND",
+				Message: err.Error(),
+			},
+		})
+	default:
+		writeServerError(c, err)
+	}
+}
@@ -4,28 +4,60 @@ import (
 	"avito-backend-trainee-autumn-2025/internal/api/dto"
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type mockUserUsecase struct {
-	setActiveFn func(ctx context.Context, userID string, active bool) (*domain.User, error)
-	getReviewFn func(ctx context.Context, userID string) ([]*domain.PullRequest, error)
+	setActiveFn func(ctx context.Context, userID string, active bool) (*domain.User, []*domain.ReviewReassignment, error)
+	getReviewFn func(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error)
+	listFn      func(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error)
+	setMaxFn    func(ctx context.Context, userID string, limit *int) (*domain.User, error)
+	addAbsFn    func(ctx context.Context, absence *domain.Absence) (*domain.Absence, []*domain.ReviewReassignment, error)
+	listAbsFn   func(ctx context.Context, userID string) ([]*domain.Absence, error)
+	deleteAbsFn func(ctx context.Context, absenceID int64) (*domain.Absence, error)
 }
 
-func (m *mockUserUsecase) SetIsActive(ctx context.Context, userID string, active bool) (*domain.User, error) {
+func (m *mockUserUsecase) AddAbsence(ctx context.Context, absence *domain.Absence) (*domain.Absence, []*domain.ReviewReassignment, error) {
+	return m.addAbsFn(ctx, absence)
+}
+
+func (m *mockUserUsecase) ListAbsences(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	return m.listAbsFn(ctx, userID)
+}
+
+func (m *mockUserUsecase) DeleteAbsence(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	return m.deleteAbsFn(ctx, absenceID)
+}
+
+func (m *mockUserUsecase) HandOffAbsences(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *mockUserUsecase) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+	return m.setMaxFn(ctx, userID, limit)
+}
+
+func (m *mockUserUsecase) List(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+	return m.listFn(ctx, filter)
+}
+
+func (m *mockUserUsecase) SetIsActive(ctx context.Context, userID string, active bool) (*domain.User, []*domain.ReviewReassignment, error) {
 	return m.setActiveFn(ctx, userID, active)
 }
 
-func (m *mockUserUsecase) GetReview(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
-	return m.getReviewFn(ctx, userID)
+func (m *mockUserUsecase) GetReview(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error) {
+	return m.getReviewFn(ctx, userID, state)
 }
 
 func TestUserHandlerSetIsActive_NotFound(t *testing.T) {
 	handler := &UserHandler{
 		UserUsecase: &mockUserUsecase{
-			setActiveFn: func(ctx context.Context, userID string, active bool) (*domain.User, error) {
-				return nil, domain.ErrNotFound
+			setActiveFn: func(ctx context.Context, userID string, active bool) (*domain.User, []*domain.ReviewReassignment, error) {
+				return nil, nil, domain.ErrNotFound
 			},
 		},
 	}
@@ -42,6 +74,97 @@ func TestUserHandlerSetIsActive_NotFound(t *testing.T) {
 	}
 }
 
+func TestUserHandlerSetIsActive_ReportsReassignments(t *testing.T) {
+	handler := &UserHandler{
+		UserUsecase: &mockUserUsecase{
+			setActiveFn: func(ctx context.Context, userID string, active bool) (*domain.User, []*domain.ReviewReassignment, error) {
+				return &domain.User{ID: userID, Name: "Bob", TeamName: "team", IsActive: active},
+					[]*domain.ReviewReassignment{
+						{PRID: "pr1", OldReviewerID: userID, NewReviewerID: "u3"},
+						{PRID: "pr2", OldReviewerID: userID},
+					}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/users/setIsActive", dto.UsersSetIsActiveRequest{
+		UserID:   "u2",
+		IsActive: false,
+	})
+
+	handler.SetIsActive(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.UsersSetIsActiveResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Reassigned) != 1 || resp.Reassigned[0].ReplacedBy != "u3" {
+		t.Fatalf("unexpected reassigned: %+v", resp.Reassigned)
+	}
+	if len(resp.NotReassigned) != 1 || resp.NotReassigned[0].PullRequestID != "pr2" {
+		t.Fatalf("unexpected not reassigned: %+v", resp.NotReassigned)
+	}
+}
+
+func TestUserHandlerSetMaxOpenReviews_Success(t *testing.T) {
+	handler := &UserHandler{
+		UserUsecase: &mockUserUsecase{
+			setMaxFn: func(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+				if limit == nil || *limit != 3 {
+					t.Fatalf("unexpected limit: %v", limit)
+				}
+				return &domain.User{ID: userID, Name: "Alice", TeamName: "team", IsActive: true, MaxOpenReviews: limit}, nil
+			},
+		},
+	}
+
+	limit := 3
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/users/setMaxOpenReviews", dto.UsersSetMaxOpenReviewsRequest{
+		UserID:         "u1",
+		MaxOpenReviews: &limit,
+	})
+
+	handler.SetMaxOpenReviews(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp dto.UsersSetMaxOpenReviewsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.User.MaxOpenReviews == nil || *resp.User.MaxOpenReviews != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestUserHandlerSetMaxOpenReviews_Negative(t *testing.T) {
+	handler := &UserHandler{
+		UserUsecase: &mockUserUsecase{
+			setMaxFn: func(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+				return nil, fmt.Errorf("max_open_reviews must not be negative: %w", domain.ErrInvalidInput)
+			},
+		},
+	}
+
+	limit := -1
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/users/setMaxOpenReviews", dto.UsersSetMaxOpenReviewsRequest{
+		UserID:         "u1",
+		MaxOpenReviews: &limit,
+	})
+
+	handler.SetMaxOpenReviews(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
 func TestUserHandlerGetReview_Validation(t *testing.T) {
 	handler := &UserHandler{
 		UserUsecase: &mockUserUsecase{},
@@ -55,3 +178,142 @@ func TestUserHandlerGetReview_Validation(t *testing.T) {
 		t.Fatalf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestUserHandlerGetReview_PendingFilter(t *testing.T) {
+	handler := &UserHandler{
+		UserUsecase: &mockUserUsecase{
+			getReviewFn: func(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error) {
+				if state != domain.ReviewPending {
+					t.Fatalf("unexpected state: %s", state)
+				}
+				return []*domain.PullRequest{{ID: "pr1", Status: domain.StatusOpen}}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/users/getReview?user_id=u2&state=PENDING", nil)
+
+	handler.GetReview(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+}
+
+func TestUserHandlerGetReview_UnknownState(t *testing.T) {
+	handler := &UserHandler{UserUsecase: &mockUserUsecase{}}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/users/getReview?user_id=u2&state=LGTM", nil)
+
+	handler.GetReview(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "VALIDATION_ERROR" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestUserHandlerList_InvalidIsActive(t *testing.T) {
+	handler := &UserHandler{UserUsecase: &mockUserUsecase{}}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/users/list?is_active=maybe", nil)
+
+	handler.List(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+
+	resp := decodeError(t, w.Body)
+	if resp.Error.Code != "VALIDATION_ERROR" {
+		t.Fatalf("unexpected error code: %+v", resp)
+	}
+}
+
+func TestUserHandlerList_UsecaseValidation(t *testing.T) {
+	handler := &UserHandler{
+		UserUsecase: &mockUserUsecase{
+			listFn: func(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+				if filter.IsActive == nil || !*filter.IsActive {
+					t.Fatalf("expected is_active=true, got %+v", filter)
+				}
+				return nil, fmt.Errorf("malformed cursor: %w", domain.ErrInvalidInput)
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodGet, "/users/list?is_active=true&cursor=broken", nil)
+
+	handler.List(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestUserHandlerAddAbsence_Created(t *testing.T) {
+	start := time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC)
+	handler := &UserHandler{
+		UserUsecase: &mockUserUsecase{
+			addAbsFn: func(ctx context.Context, absence *domain.Absence) (*domain.Absence, []*domain.ReviewReassignment, error) {
+				if absence.UserID != "u1" || absence.DelegateID != "u2" || !absence.StartsAt.Equal(start) {
+					t.Fatalf("unexpected absence: %+v", absence)
+				}
+				created := *absence
+				created.ID = 3
+				return &created, []*domain.ReviewReassignment{
+					{PRID: "pr1", OldReviewerID: "u1", NewReviewerID: "u2"},
+					{PRID: "pr2", OldReviewerID: "u1"},
+				}, nil
+			},
+		},
+	}
+
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/users/addAbsence", dto.UsersAddAbsenceRequest{
+		UserID:     "u1",
+		StartsAt:   start,
+		EndsAt:     start.Add(48 * time.Hour),
+		DelegateID: "u2",
+	})
+
+	handler.AddAbsence(c)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp dto.UsersAddAbsenceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Absence.AbsenceID != 3 || len(resp.Reassigned) != 1 || len(resp.NotReassigned) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestUserHandlerAddAbsence_Overlap(t *testing.T) {
+	handler := &UserHandler{
+		UserUsecase: &mockUserUsecase{
+			addAbsFn: func(ctx context.Context, absence *domain.Absence) (*domain.Absence, []*domain.ReviewReassignment, error) {
+				return nil, nil, domain.ErrAbsenceOverlap
+			},
+		},
+	}
+
+	start := time.Date(2025, 11, 10, 9, 0, 0, 0, time.UTC)
+	w, c := newRecorderWithRequest(t, http.MethodPost, "/users/addAbsence", dto.UsersAddAbsenceRequest{
+		UserID:   "u1",
+		StartsAt: start,
+		EndsAt:   start.Add(time.Hour),
+	})
+
+	handler.AddAbsence(c)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+}
@@ -8,24 +8,57 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func Register(r *gin.Engine, prHandler *handler.PRHandler, teamHandler *handler.TeamHandler, userHandler *handler.UserHandler) {
+func Register(r *gin.Engine, prHandler *handler.PRHandler, teamHandler *handler.TeamHandler, userHandler *handler.UserHandler, statsHandler *handler.StatsHandler) {
+	r.Use(handler.Actor())
+
 	team := r.Group("/team")
 	{
 		team.POST("/add", teamHandler.Add)
 		team.GET("/get", teamHandler.Get)
+		team.GET("/list", teamHandler.List)
+		team.POST("/addMembers", teamHandler.AddMembers)
+		team.POST("/removeMembers", teamHandler.RemoveMembers)
+		team.POST("/deactivateUsers", teamHandler.DeactivateUsers)
+		team.POST("/rename", teamHandler.Rename)
+		team.GET("/settings", teamHandler.GetSettings)
+		team.POST("/settings", teamHandler.UpdateSettings)
+		team.POST("/archive", teamHandler.Archive)
+		team.POST("/unarchive", teamHandler.Unarchive)
+		team.POST("/delete", teamHandler.Delete)
 	}
 
 	pr := r.Group("/pullRequest")
 	{
 		pr.POST("/create", prHandler.Create)
 		pr.POST("/merge", prHandler.Merge)
+		pr.POST("/close", prHandler.Close)
+		pr.POST("/reopen", prHandler.Reopen)
+		pr.POST("/markReady", prHandler.MarkReady)
+		pr.POST("/approve", prHandler.Approve)
+		pr.POST("/requestChanges", prHandler.RequestChanges)
+		pr.POST("/dismissReview", prHandler.DismissReview)
 		pr.POST("/reassign", prHandler.Reassign)
+		pr.GET("/get", prHandler.Get)
+		pr.GET("/history", prHandler.History)
+		pr.GET("/list", prHandler.List)
 	}
 
 	users := r.Group("/users")
 	{
 		users.POST("/setIsActive", userHandler.SetIsActive)
+		users.POST("/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
 		users.GET("/getReview", userHandler.GetReview)
+		users.GET("/list", userHandler.List)
+		users.POST("/addAbsence", userHandler.AddAbsence)
+		users.GET("/absences", userHandler.Absences)
+		users.POST("/deleteAbsence", userHandler.DeleteAbsence)
+	}
+
+	stats := r.Group("/stats")
+	{
+		stats.GET("/users", statsHandler.Users)
+		stats.GET("/pullRequests", statsHandler.PullRequests)
+		stats.GET("/teams", statsHandler.Teams)
 	}
 
 	r.GET("/health", func(c *gin.Context) {
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultReviewerStrategy     = "least_loaded"
+	defaultAbsenceCheckInterval = time.Minute
+	defaultSQLitePath           = "reviewer.db"
+
+	// StoragePostgres, StorageSQLite и StorageMemory — допустимые значения STORAGE
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+type Config struct {
+	// Storage — где хранить данные: postgres, sqlite или memory (STORAGE, по умолчанию postgres).
+	// В памяти данные живут до перезапуска процесса.
+	Storage string
+	// SQLitePath — файл базы для STORAGE=sqlite (SQLITE_PATH, по умолчанию reviewer.db).
+	SQLitePath string
+	// ReviewerStrategy — глобальная стратегия выбора ревьюверов (REVIEWER_STRATEGY).
+	ReviewerStrategy string
+	// TeamStrategies — переопределения стратегии по командам (REVIEWER_STRATEGY_TEAMS=team:strategy,...).
+	TeamStrategies map[string]string
+	// ReviewerWeights — веса пользователей для стратегии weighted (REVIEWER_WEIGHTS=user_id:weight,...).
+	ReviewerWeights map[string]int
+	// MergeMinApprovals — сколько одобрений нужно для merge (MERGE_MIN_APPROVALS, по умолчанию 0).
+	MergeMinApprovals int
+	// MergeBlockOnChangesRequested запрещает merge при запрошенных изменениях (MERGE_BLOCK_ON_CHANGES_REQUESTED, по умолчанию true).
+	MergeBlockOnChangesRequested bool
+	// MergeAllowOverride разрешает merge в обход политики (MERGE_ALLOW_OVERRIDE, по умолчанию false).
+	MergeAllowOverride bool
+	// AbsenceCheckInterval — как часто передавать заместителям ревью начавшихся отсутствий (ABSENCE_CHECK_INTERVAL, по умолчанию 1m).
+	AbsenceCheckInterval time.Duration
+}
+
+// Load читает конфигурацию сервиса из переменных окружения
+func Load() (*Config, error) {
+	cfg := &Config{
+		Storage:          os.Getenv("STORAGE"),
+		SQLitePath:       os.Getenv("SQLITE_PATH"),
+		ReviewerStrategy: os.Getenv("REVIEWER_STRATEGY"),
+	}
+	switch cfg.Storage {
+	case "":
+		cfg.Storage = StoragePostgres
+	case StoragePostgres, StorageSQLite, StorageMemory:
+	default:
+		return nil, fmt.Errorf("STORAGE: expected %s, %s or %s, got %q", StoragePostgres, StorageSQLite, StorageMemory, cfg.Storage)
+	}
+	if cfg.SQLitePath == "" {
+		cfg.SQLitePath = defaultSQLitePath
+	}
+
+	if cfg.ReviewerStrategy == "" {
+		cfg.ReviewerStrategy = defaultReviewerStrategy
+	}
+
+	teamStrategies, err := parsePairs(os.Getenv("REVIEWER_STRATEGY_TEAMS"))
+	if err != nil {
+		return nil, fmt.Errorf("REVIEWER_STRATEGY_TEAMS: %w", err)
+	}
+	cfg.TeamStrategies = teamStrategies
+
+	rawWeights, err := parsePairs(os.Getenv("REVIEWER_WEIGHTS"))
+	if err != nil {
+		return nil, fmt.Errorf("REVIEWER_WEIGHTS: %w", err)
+	}
+	cfg.ReviewerWeights = make(map[string]int, len(rawWeights))
+	for userID, raw := range rawWeights {
+		w, err := strconv.Atoi(raw)
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("REVIEWER_WEIGHTS: weight for %s must be a positive integer, got %q", userID, raw)
+		}
+		cfg.ReviewerWeights[userID] = w
+	}
+
+	if raw := os.Getenv("MERGE_MIN_APPROVALS"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("MERGE_MIN_APPROVALS: must be a non-negative integer, got %q", raw)
+		}
+		cfg.MergeMinApprovals = n
+	}
+
+	if cfg.MergeBlockOnChangesRequested, err = parseBool("MERGE_BLOCK_ON_CHANGES_REQUESTED", true); err != nil {
+		return nil, err
+	}
+	if cfg.MergeAllowOverride, err = parseBool("MERGE_ALLOW_OVERRIDE", false); err != nil {
+		return nil, err
+	}
+
+	cfg.AbsenceCheckInterval = defaultAbsenceCheckInterval
+	if raw := os.Getenv("ABSENCE_CHECK_INTERVAL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("ABSENCE_CHECK_INTERVAL: must be a positive duration, got %q", raw)
+		}
+		cfg.AbsenceCheckInterval = d
+	}
+
+	return cfg, nil
+}
+
+// parseBool читает булеву переменную окружения, для пустого значения возвращает def
+func parseBool(name string, def bool) (bool, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s: expected boolean, got %q", name, raw)
+	}
+	return v, nil
+}
+
+// parsePairs разбирает строку вида "key1:value1,key2:value2"
+func parsePairs(s string) (map[string]string, error) {
+	res := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return res, nil
+	}
+
+	for _, item := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(item), ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid pair %q, expected key:value", item)
+		}
+		res[key] = value
+	}
+
+	return res, nil
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("STORAGE", "")
+	t.Setenv("SQLITE_PATH", "")
+	t.Setenv("REVIEWER_STRATEGY", "")
+	t.Setenv("REVIEWER_STRATEGY_TEAMS", "")
+	t.Setenv("REVIEWER_WEIGHTS", "")
+	t.Setenv("MERGE_MIN_APPROVALS", "")
+	t.Setenv("MERGE_BLOCK_ON_CHANGES_REQUESTED", "")
+	t.Setenv("MERGE_ALLOW_OVERRIDE", "")
+	t.Setenv("ABSENCE_CHECK_INTERVAL", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Storage != StoragePostgres {
+		t.Fatalf("unexpected default storage: %s", cfg.Storage)
+	}
+	if cfg.SQLitePath != defaultSQLitePath {
+		t.Fatalf("unexpected default sqlite path: %s", cfg.SQLitePath)
+	}
+	if cfg.ReviewerStrategy != defaultReviewerStrategy {
+		t.Fatalf("unexpected default strategy: %s", cfg.ReviewerStrategy)
+	}
+	if cfg.MergeMinApprovals != 0 || !cfg.MergeBlockOnChangesRequested || cfg.MergeAllowOverride {
+		t.Fatalf("unexpected default merge policy: %+v", cfg)
+	}
+	if len(cfg.TeamStrategies) != 0 || len(cfg.ReviewerWeights) != 0 {
+		t.Fatalf("expected empty overrides: %+v", cfg)
+	}
+	if cfg.AbsenceCheckInterval != defaultAbsenceCheckInterval {
+		t.Fatalf("unexpected default absence check interval: %s", cfg.AbsenceCheckInterval)
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	t.Setenv("REVIEWER_STRATEGY", "weighted")
+	t.Setenv("REVIEWER_STRATEGY_TEAMS", "backend:round_robin, payments:random")
+	t.Setenv("REVIEWER_WEIGHTS", "u1:3,u2:1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.TeamStrategies, map[string]string{"backend": "round_robin", "payments": "random"}) {
+		t.Fatalf("unexpected team strategies: %v", cfg.TeamStrategies)
+	}
+	if !reflect.DeepEqual(cfg.ReviewerWeights, map[string]int{"u1": 3, "u2": 1}) {
+		t.Fatalf("unexpected weights: %v", cfg.ReviewerWeights)
+	}
+}
+
+func TestLoad_InvalidWeight(t *testing.T) {
+	t.Setenv("REVIEWER_WEIGHTS", "u1:zero")
+
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for invalid weight")
+	}
+}
+
+func TestLoad_MergePolicy(t *testing.T) {
+	t.Setenv("MERGE_MIN_APPROVALS", "2")
+	t.Setenv("MERGE_BLOCK_ON_CHANGES_REQUESTED", "false")
+	t.Setenv("MERGE_ALLOW_OVERRIDE", "0")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.MergeMinApprovals != 2 || cfg.MergeBlockOnChangesRequested || cfg.MergeAllowOverride {
+		t.Fatalf("unexpected merge policy: %+v", cfg)
+	}
+
+	t.Setenv("MERGE_MIN_APPROVALS", "-1")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for negative approvals")
+	}
+}
+
+func TestLoad_InvalidAbsenceCheckInterval(t *testing.T) {
+	t.Setenv("ABSENCE_CHECK_INTERVAL", "0s")
+
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for non-positive interval")
+	}
+}
+
+func TestLoad_Storage(t *testing.T) {
+	t.Setenv("STORAGE", "memory")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Storage != StorageMemory {
+		t.Fatalf("unexpected storage: %s", cfg.Storage)
+	}
+
+	t.Setenv("STORAGE", "sqlite")
+	t.Setenv("SQLITE_PATH", "/var/lib/reviewer/data.db")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Storage != StorageSQLite || cfg.SQLitePath != "/var/lib/reviewer/data.db" {
+		t.Fatalf("unexpected sqlite storage: %s %s", cfg.Storage, cfg.SQLitePath)
+	}
+
+	t.Setenv("STORAGE", "mysql")
+	if _, err := Load(); err == nil {
+		t.Fatalf("expected error for unknown storage")
+	}
+}
@@ -1,11 +1,64 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
-	ErrAlreadyExists = errors.New("already exists")
-	ErrPRMerged      = errors.New("cannot reassign on merged PR")
-	ErrNotAssigned   = errors.New("reviewer is not assigned to this PR")
-	ErrNoCandidate   = errors.New("no active replacement candidate in team")
-	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrPRMerged           = errors.New("pull request is merged")
+	ErrPRClosed           = errors.New("PR is closed")
+	ErrPRDraft            = errors.New("PR is a draft")
+	ErrNotAssigned        = errors.New("reviewer is not assigned to this PR")
+	ErrNoCandidate        = errors.New("no active replacement candidate in team")
+	ErrNotFound           = errors.New("not found")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrMergeBlocked       = errors.New("merge blocked by policy")
+	ErrOtherTeam          = errors.New("user belongs to another team")
+	ErrTeamHasOpenReviews = errors.New("team members have open reviews")
+	ErrAbsenceOverlap     = errors.New("absence overlaps an existing one")
+	ErrConcurrentUpdate   = errors.New("concurrent update conflict")
 )
+
+// MergeBlockedError перечисляет невыполненные условия политики merge; errors.Is(err, ErrMergeBlocked) == true
+type MergeBlockedError struct {
+	Missing []string
+}
+
+func (e *MergeBlockedError) Error() string {
+	return ErrMergeBlocked.Error() + ": " + strings.Join(e.Missing, "; ")
+}
+
+func (e *MergeBlockedError) Unwrap() error {
+	return ErrMergeBlocked
+}
+
+// TeamConflict — пользователь, который уже состоит в другой команде
+type TeamConflict struct {
+	UserID   string
+	TeamName string
+}
+
+// OtherTeamError перечисляет участников других команд, которых нельзя перенести без явного согласия;
+// errors.Is(err, ErrOtherTeam) == true
+type OtherTeamError struct {
+	Conflicts []TeamConflict
+}
+
+func (e *OtherTeamError) Error() string {
+	return ErrOtherTeam.Error() + ": " + strings.Join(e.Details(), "; ")
+}
+
+func (e *OtherTeamError) Unwrap() error {
+	return ErrOtherTeam
+}
+
+// Details возвращает конфликты в виде "user_id: team_name"
+func (e *OtherTeamError) Details() []string {
+	res := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		res = append(res, c.UserID+": "+c.TeamName)
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type AssignmentEventType string
+
+const (
+	EventAssigned   AssignmentEventType = "ASSIGNED"
+	EventReassigned AssignmentEventType = "REASSIGNED"
+	EventUnassigned AssignmentEventType = "UNASSIGNED"
+)
+
+// Причины изменения состава ревьюверов, записываемые в историю
+const (
+	ReasonPRCreated        = "pr_created"
+	ReasonManualReassign   = "manual_reassign"
+	ReasonUserDeactivated  = "user_deactivated"
+	ReasonTeamDeactivation = "team_deactivation"
+	ReasonPRReopened       = "pr_reopened"
+	ReasonPRReady          = "pr_ready"
+	ReasonMemberRemoved    = "team_member_removed"
+	ReasonMemberMoved      = "team_member_moved"
+	ReasonTeamDeleted      = "team_deleted"
+	ReasonUserAbsent       = "user_absent"
+)
+
+// SystemActor записывается в историю, когда инициатор изменения неизвестен
+const SystemActor = "system"
+
+// AssignmentEvent — запись журнала назначений. Для REASSIGNED UserID — новый ревьювер,
+// PreviousUserID — замененный; для UNASSIGNED UserID — снятый ревьювер.
+type AssignmentEvent struct {
+	ID             int64
+	PRID           string
+	Type           AssignmentEventType
+	UserID         string
+	PreviousUserID string
+	Actor          string
+	Reason         string
+	CreatedAt      time.Time
+}
+
+type actorKey struct{}
+
+// WithActor сохраняет инициатора запроса в контексте
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает инициатора запроса или SystemActor, если он не задан
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Поля сортировки списков
+const (
+	SortByID        = "id"
+	SortByName      = "name"
+	SortByCreatedAt = "created_at"
+)
+
+// PageParams — сортировка и курсорная пагинация. Cursor — непрозрачное значение NextCursor предыдущей страницы,
+// он действителен только с теми же Sort и Order.
+type PageParams struct {
+	Sort   string
+	Order  SortOrder
+	Cursor string
+	Limit  int
+}
+
+// Page — страница списка; пустой NextCursor означает, что страница последняя
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// TeamFilter ограничивает список команд; nil Archived не фильтрует по архиву
+type TeamFilter struct {
+	Archived *bool
+	PageParams
+}
+
+// UserFilter ограничивает список пользователей; пустые поля не ограничивают выборку
+type UserFilter struct {
+	TeamName string
+	IsActive *bool
+	PageParams
+}
+
+// PRFilter ограничивает список PR. TeamName — команда автора, CreatedFrom/CreatedTo задают полуинтервал
+// [CreatedFrom, CreatedTo) по created_at; пустые поля не ограничивают выборку.
+type PRFilter struct {
+	Status      PRStatus
+	AuthorID    string
+	ReviewerID  string
+	TeamName    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	PageParams
+}
@@ -10,31 +10,168 @@ type PRStatus string
 const (
 	StatusOpen   PRStatus = "OPEN"
 	StatusMerged PRStatus = "MERGED"
+	StatusClosed PRStatus = "CLOSED"
 )
 
+func (s PRStatus) Valid() bool {
+	switch s {
+	case StatusOpen, StatusMerged, StatusClosed:
+		return true
+	}
+	return false
+}
+
+// ReviewState — решение ревьювера по PR
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewDismissed        ReviewState = "DISMISSED"
+)
+
+// Valid сообщает, является ли s известным состоянием ревью
+func (s ReviewState) Valid() bool {
+	switch s {
+	case ReviewPending, ReviewApproved, ReviewChangesRequested, ReviewDismissed:
+		return true
+	default:
+		return false
+	}
+}
+
+type ReviewerState struct {
+	UserID    string
+	State     ReviewState
+	UpdatedAt *time.Time
+}
+
 type PullRequest struct {
-	ID        string
-	Name      string
-	AuthorID  string
-	Status    PRStatus
-	Reviewers []string
-	CreatedAt *time.Time
-	MergedAt  *time.Time
+	ID              string
+	Name            string
+	AuthorID        string
+	Status          PRStatus
+	IsDraft         bool
+	Reviewers       []string
+	ReviewerStates  []*ReviewerState
+	CreatedAt       *time.Time
+	MergedAt        *time.Time
+	ClosedAt        *time.Time
+	MergeOverrideBy string
+	// MissingReviewers — скольких ревьюверов не хватило до min_reviewers команды автора; заполняется только при назначении
+	MissingReviewers int
+	// FallbackReviewers — ревьюверы, назначенные этой операцией из резервных команд
+	FallbackReviewers []*FallbackReviewer
+	// SkippedAtCapacity — кандидаты, пропущенные этой операцией из-за исчерпанного лимита открытых ревью
+	SkippedAtCapacity []string
+}
+
+// FallbackReviewer — ревьювер из резервной команды, назначенный из-за нехватки кандидатов в основной
+type FallbackReviewer struct {
+	UserID   string
+	TeamName string
+}
+
+// ReviewReassignment описывает замену ревьювера на PR. NewReviewerID пуст, если замену найти не удалось.
+type ReviewReassignment struct {
+	PRID          string
+	OldReviewerID string
+	NewReviewerID string
+}
+
+// ReassignStrategy — стратегия выбора ревьюверов, которую хранилище воспроизводит при массовом переназначении
+type ReassignStrategy string
+
+const (
+	ReassignRandom      ReassignStrategy = "random"
+	ReassignLeastLoaded ReassignStrategy = "least_loaded"
+	ReassignRoundRobin  ReassignStrategy = "round_robin"
+	ReassignWeighted    ReassignStrategy = "weighted"
+)
+
+// BulkReassign — параметры массового переназначения открытых ревью пользователей UserIDs.
+// Cursor — последний назначенный по кругу ревьювер (для ReassignRoundRobin), Weights — веса кандидатов
+// для ReassignWeighted (по умолчанию 1). Actor и Reason записываются в журнал назначений.
+type BulkReassign struct {
+	UserIDs  []string
+	Strategy ReassignStrategy
+	Cursor   string
+	Weights  map[string]int
+	Actor    string
+	Reason   string
+}
+
+// TimelineEventType — этап жизненного цикла PR
+type TimelineEventType string
+
+const (
+	TimelineCreated    TimelineEventType = "CREATED"
+	TimelineAssigned   TimelineEventType = "ASSIGNED"
+	TimelineReassigned TimelineEventType = "REASSIGNED"
+	TimelineUnassigned TimelineEventType = "UNASSIGNED"
+	TimelineMerged     TimelineEventType = "MERGED"
+	TimelineClosed     TimelineEventType = "CLOSED"
+)
+
+// TimelineEvent — запись ленты PR. Для событий назначения заполнены ревьюверы, инициатор и причина из журнала.
+type TimelineEvent struct {
+	Type           TimelineEventType
+	At             time.Time
+	UserID         string
+	PreviousUserID string
+	Actor          string
+	Reason         string
+}
+
+// PRDetails — PR с профилями автора и текущих ревьюверов (в порядке ReviewerStates) и лентой событий по времени
+type PRDetails struct {
+	PR        *PullRequest
+	Author    *User
+	Reviewers []*User
+	Timeline  []*TimelineEvent
 }
 
 type PRRepository interface {
 	Create(ctx context.Context, pr *PullRequest) (*PullRequest, error)
 	FetchByID(ctx context.Context, prID string) (*PullRequest, error)
-	UpdateStatusMerged(ctx context.Context, prID string) (*PullRequest, error)
+	// FetchByIDForUpdate читает PR и блокирует его строку до конца транзакции
+	FetchByIDForUpdate(ctx context.Context, prID string) (*PullRequest, error)
+	// List возвращает PR с id назначенных ревьюверов
+	List(ctx context.Context, filter PRFilter) (*Page[*PullRequest], error)
+	// UpdateStatusMerged помечает PR смерженным; непустой overrideBy сохраняется как инициатор обхода политики merge
+	UpdateStatusMerged(ctx context.Context, prID, overrideBy string) (*PullRequest, error)
+	UpdateStatusClosed(ctx context.Context, prID string) (*PullRequest, error)
+	UpdateStatusOpen(ctx context.Context, prID string) (*PullRequest, error)
+	UpdateReady(ctx context.Context, prID string) (*PullRequest, error)
 	ListReviewableByUserID(ctx context.Context, userID string) ([]*PullRequest, error)
+	ListReviewableByUserIDAndState(ctx context.Context, userID string, state ReviewState) ([]*PullRequest, error)
 	ListReviewers(ctx context.Context, prID string) ([]string, error)
+	ListReviewerStates(ctx context.Context, prID string) ([]*ReviewerState, error)
+	UpdateReviewState(ctx context.Context, prID, userID string, state ReviewState) error
 	InsertReviewer(ctx context.Context, prID, userID string) error
 	ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error
+	RemoveReviewer(ctx context.Context, prID, userID string) error
 	ReviewerAssigned(ctx context.Context, prID, userID string) (bool, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	// ReassignOpenReviews заменяет пользователей UserIDs на всех OPEN PR участниками их команд, подбирая замены
+	// сразу для всех PR по стратегии Strategy, и пишет замены в журнал назначений
+	ReassignOpenReviews(ctx context.Context, req BulkReassign) ([]*ReviewReassignment, error)
+	AddAssignmentEvents(ctx context.Context, events ...*AssignmentEvent) error
+	ListAssignmentEvents(ctx context.Context, prID string) ([]*AssignmentEvent, error)
 }
 
 type PRUsecase interface {
 	CreateWithReviewers(ctx context.Context, newPR *PullRequest) (*PullRequest, error)
-	Merge(ctx context.Context, prID string) (*PullRequest, error)
+	// Merge проверяет политику merge; override разрешает смержить PR с невыполненными условиями, если это допускает политика
+	Merge(ctx context.Context, prID string, override bool) (*PullRequest, error)
+	Close(ctx context.Context, prID string) (*PullRequest, error)
+	Reopen(ctx context.Context, prID string) (*PullRequest, error)
+	MarkReady(ctx context.Context, prID string) (*PullRequest, error)
+	SubmitReview(ctx context.Context, prID, userID string, state ReviewState) (*PullRequest, error)
 	Reassign(ctx context.Context, prID, oldReviewerID string) (*PullRequest, string, error)
+	History(ctx context.Context, prID string) ([]*AssignmentEvent, error)
+	// Get возвращает PR с ревьюверами, автором и лентой событий
+	Get(ctx context.Context, prID string) (*PRDetails, error)
+	List(ctx context.Context, filter PRFilter) (*Page[*PullRequest], error)
 }
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// StatsFilter ограничивает выборку статистики. From/To задают полуинтервал [From, To) по created_at PR
+// (для merged-счетчиков — по merged_at); пустые границы и пустой TeamName не ограничивают выборку.
+type StatsFilter struct {
+	TeamName string
+	From     *time.Time
+	To       *time.Time
+}
+
+type UserAssignmentStats struct {
+	UserID   string
+	Username string
+	TeamName string
+	IsActive bool
+	Total    int
+	Open     int
+	Merged   int
+}
+
+type PRReassignmentStats struct {
+	PRID          string
+	Name          string
+	AuthorID      string
+	Status        PRStatus
+	Reviewers     int
+	Reassignments int
+}
+
+type TeamStats struct {
+	TeamName      string
+	Members       int
+	ActiveMembers int
+	OpenPRs       int
+	MergedPRs     int
+	Assignments   int
+	Reassignments int
+}
+
+type StatsRepository interface {
+	UserAssignments(ctx context.Context, filter StatsFilter) ([]*UserAssignmentStats, error)
+	PRReassignments(ctx context.Context, filter StatsFilter) ([]*PRReassignmentStats, error)
+	TeamTotals(ctx context.Context, filter StatsFilter) ([]*TeamStats, error)
+}
+
+type StatsUsecase interface {
+	UserAssignments(ctx context.Context, filter StatsFilter) ([]*UserAssignmentStats, error)
+	PRReassignments(ctx context.Context, filter StatsFilter) ([]*PRReassignmentStats, error)
+	TeamTotals(ctx context.Context, filter StatsFilter) ([]*TeamStats, error)
+}
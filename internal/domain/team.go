@@ -1,18 +1,94 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// Team — команда с участниками. Архивная команда (ArchivedAt != nil) не участвует в назначении ревьюверов.
 type Team struct {
-	Name    string
-	Members []*User
+	Name       string
+	Members    []*User
+	ArchivedAt *time.Time
+}
+
+// TeamDeactivation — результат массовой деактивации участников команды
+type TeamDeactivation struct {
+	TeamName      string
+	Deactivated   []string
+	Reassignments []*ReviewReassignment
+}
+
+// TeamMembersChange — результат добавления или удаления участников команды (в том числе при удалении самой команды).
+// UserIDs — перенесенные из других команд (при создании и добавлении) или удаленные участники,
+// Reassignments — переданные другим участникам их открытые ревью.
+type TeamMembersChange struct {
+	Team          *Team
+	UserIDs       []string
+	Reassignments []*ReviewReassignment
+}
+
+// Значения настроек для команд, у которых они не заданы
+const (
+	DefaultMinReviewers = 0
+	DefaultMaxReviewers = 2
+	// MaxReviewersLimit — верхняя граница max_reviewers
+	MaxReviewersLimit = 10
+)
+
+// TeamSettings — правила назначения ревьюверов на PR авторов команды. Ревьюверы набираются до MaxReviewers сначала
+// из команды автора, затем по очереди из FallbackTeams; меньше MinReviewers — назначение неполное.
+type TeamSettings struct {
+	TeamName      string
+	MinReviewers  int
+	MaxReviewers  int
+	FallbackTeams []string
+	// DefaultMaxOpenReviews — лимит открытых ревью для участников без личного лимита; nil — без ограничения
+	DefaultMaxOpenReviews *int
+}
+
+// DefaultTeamSettings возвращает настройки по умолчанию: до двух ревьюверов только из своей команды
+func DefaultTeamSettings(teamName string) *TeamSettings {
+	return &TeamSettings{
+		TeamName:     teamName,
+		MinReviewers: DefaultMinReviewers,
+		MaxReviewers: DefaultMaxReviewers,
+	}
 }
 
 type TeamRepository interface {
+	// Create создает команду; если команда с таким именем уже есть, возвращает ErrAlreadyExists
 	Create(ctx context.Context, teamName string) error
 	Exists(ctx context.Context, teamName string) (bool, error)
+	FetchByName(ctx context.Context, teamName string) (*Team, error)
+	// Rename переименовывает команду; team_name участников обновляется каскадно
+	Rename(ctx context.Context, teamName, newName string) error
+	SetArchived(ctx context.Context, teamName string, archived bool) (*Team, error)
+	Delete(ctx context.Context, teamName string) error
+	// List возвращает команды без участников
+	List(ctx context.Context, filter TeamFilter) (*Page[*Team], error)
+	// FetchSettings возвращает настройки команды или настройки по умолчанию, если они не сохранялись
+	FetchSettings(ctx context.Context, teamName string) (*TeamSettings, error)
+	UpsertSettings(ctx context.Context, settings *TeamSettings) (*TeamSettings, error)
+	LockReviewCursor(ctx context.Context, teamName string) (string, error)
+	UpdateReviewCursor(ctx context.Context, teamName, lastUserID string) error
 }
 
 type TeamUsecase interface {
-	Add(ctx context.Context, team *Team) (*Team, error)
+	// Add создает команду; участников других команд переносит только при moveExisting
+	Add(ctx context.Context, team *Team, moveExisting bool) (*TeamMembersChange, error)
 	ListByName(ctx context.Context, name string) (*Team, error)
+	List(ctx context.Context, filter TeamFilter) (*Page[*Team], error)
+	DeactivateUsers(ctx context.Context, teamName string, userIDs []string, allExcept bool) (*TeamDeactivation, error)
+	// AddMembers добавляет участников в существующую команду; участников других команд переносит только при move
+	AddMembers(ctx context.Context, teamName string, members []*User, move bool) (*TeamMembersChange, error)
+	RemoveMembers(ctx context.Context, teamName string, userIDs []string) (*TeamMembersChange, error)
+	Rename(ctx context.Context, teamName, newName string) (*Team, error)
+	GetSettings(ctx context.Context, teamName string) (*TeamSettings, error)
+	UpdateSettings(ctx context.Context, settings *TeamSettings) (*TeamSettings, error)
+	SetArchived(ctx context.Context, teamName string, archived bool) (*Team, error)
+	// Delete удаляет команду. Пока у участников есть открытые ревью, удаление отклоняется с ErrTeamHasOpenReviews;
+	// при force их ревью передаются коллегам авторов PR из других команд (для авторов из удаляемой команды —
+	// участникам ее резервных команд), а участники остаются без команды.
+	Delete(ctx context.Context, teamName string, force bool) (*TeamMembersChange, error)
 }
@@ -1,9 +1,57 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// TxManager выполняет fn в транзакции. При ошибке сериализации или deadlock транзакция откатывается и fn
+// вызывается заново, поэтому fn не должна оставлять побочных эффектов вне транзакции до своего успешного завершения.
+// WithinTx с контекстом колбэка другого WithinTx выполняется во вложенной транзакции: ошибка откатывает только
+// вложенные изменения, а фиксируются они вместе с внешней транзакцией; опции вложенного вызова не применяются.
 type TxManager interface {
-	WithinTx(ctx context.Context, fn func(ctx context.Context, repos *Repos) error) error
+	WithinTx(ctx context.Context, fn func(ctx context.Context, repos *Repos) error, opts ...TxOption) error
+}
+
+// IsolationLevel — требуемый уровень изоляции; реализация TxManager сопоставляет его с уровнем своего хранилища
+// и может выполнить транзакцию на более строгом уровне, но не на более слабом
+type IsolationLevel int
+
+const (
+	ReadCommitted IsolationLevel = iota
+	RepeatableRead
+	Serializable
+)
+
+// TxOptions — параметры транзакции; нулевое значение — READ COMMITTED на чтение и запись без таймаута
+type TxOptions struct {
+	Isolation IsolationLevel
+	ReadOnly  bool
+	// StatementTimeout ограничивает каждый запрос транзакции; 0 — действует настройка сервера
+	StatementTimeout time.Duration
+}
+
+type TxOption func(*TxOptions)
+
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *TxOptions) { o.Isolation = level }
+}
+
+func ReadOnly() TxOption {
+	return func(o *TxOptions) { o.ReadOnly = true }
+}
+
+func WithStatementTimeout(d time.Duration) TxOption {
+	return func(o *TxOptions) { o.StatementTimeout = d }
+}
+
+// ApplyTxOptions собирает параметры транзакции из опций
+func ApplyTxOptions(opts ...TxOption) TxOptions {
+	var o TxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
 type Repos struct {
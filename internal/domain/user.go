@@ -1,24 +1,74 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type User struct {
 	ID       string
 	Name     string
 	TeamName string
 	IsActive bool
+	// MaxOpenReviews — личный лимит одновременно открытых ревью; nil — действует лимит команды по умолчанию
+	MaxOpenReviews *int
+}
+
+// Absence — период отсутствия пользователя [StartsAt, EndsAt). Пока период действует, пользователь не попадает
+// в кандидаты в ревьюверы; если задан DelegateID, с началом периода его открытые ревью передаются заместителю.
+type Absence struct {
+	ID         int64
+	UserID     string
+	StartsAt   time.Time
+	EndsAt     time.Time
+	DelegateID string
+	// HandedOffAt — когда открытые ревью переданы заместителю; nil — еще не передавались
+	HandedOffAt *time.Time
+	CreatedAt   time.Time
 }
 
 type UserRepository interface {
 	Upsert(ctx context.Context, user *User) error
 	FetchByID(ctx context.Context, id string) (*User, error)
+	// FetchByIDs возвращает найденных пользователей; отсутствующие id пропускаются
+	FetchByIDs(ctx context.Context, ids []string) ([]*User, error)
 	FetchByTeam(ctx context.Context, teamName string) ([]*User, error)
 	FetchActiveByTeam(ctx context.Context, teamName string, excludeIDs ...string) ([]*User, error)
 	Exists(ctx context.Context, userID string) (bool, error)
 	UpdateIsActive(ctx context.Context, userID string, active bool) (*User, error)
+	// SetMaxOpenReviews задает личный лимит открытых ревью; nil сбрасывает его к лимиту команды
+	SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*User, error)
+	// FetchReviewLimits возвращает действующие лимиты открытых ревью (личный или команды по умолчанию);
+	// пользователи без лимита в результат не попадают
+	FetchReviewLimits(ctx context.Context, userIDs []string) (map[string]int, error)
+	DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error)
+	RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) ([]string, error)
+	List(ctx context.Context, filter UserFilter) (*Page[*User], error)
+	AddAbsence(ctx context.Context, absence *Absence) (*Absence, error)
+	// HasOverlappingAbsence проверяет, пересекается ли [startsAt, endsAt) с другим периодом отсутствия пользователя
+	HasOverlappingAbsence(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error)
+	ListAbsences(ctx context.Context, userID string) ([]*Absence, error)
+	// FetchCurrentAbsence возвращает идущий сейчас период отсутствия пользователя или ErrNotFound
+	FetchCurrentAbsence(ctx context.Context, userID string) (*Absence, error)
+	DeleteAbsence(ctx context.Context, absenceID int64) (*Absence, error)
+	// ListDueHandoffs блокирует и возвращает начавшиеся периоды с заместителем, по которым ревью еще не передавались;
+	// пустой userID — по всем пользователям
+	ListDueHandoffs(ctx context.Context, userID string) ([]*Absence, error)
+	MarkHandedOff(ctx context.Context, absenceID int64) (*Absence, error)
 }
 
 type UserUsecase interface {
-	SetIsActive(ctx context.Context, userID string, active bool) (*User, error)
-	GetReview(ctx context.Context, userID string) ([]*PullRequest, error)
+	SetIsActive(ctx context.Context, userID string, active bool) (*User, []*ReviewReassignment, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*User, error)
+	// GetReview возвращает PR, где пользователь ревьювер; непустой state оставляет только ревью в этом состоянии
+	GetReview(ctx context.Context, userID string, state ReviewState) ([]*PullRequest, error)
+	List(ctx context.Context, filter UserFilter) (*Page[*User], error)
+	// AddAbsence регистрирует период отсутствия; если он уже начался и задан заместитель, открытые ревью
+	// передаются ему сразу
+	AddAbsence(ctx context.Context, absence *Absence) (*Absence, []*ReviewReassignment, error)
+	ListAbsences(ctx context.Context, userID string) ([]*Absence, error)
+	DeleteAbsence(ctx context.Context, absenceID int64) (*Absence, error)
+	// HandOffAbsences передает заместителям открытые ревью пользователей, чье отсутствие началось,
+	// и возвращает число обработанных периодов
+	HandOffAbsences(ctx context.Context) (int, error)
 }
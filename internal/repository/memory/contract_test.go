@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/repository/repotest"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Stores {
+		store := NewStore()
+		require.NoError(t, testutils.LoadFixtures(context.Background(), newRepos(store)))
+
+		return &repotest.Stores{
+			User:  NewUserRepository(store),
+			Team:  NewTeamRepository(store),
+			PR:    NewPRRepository(store),
+			Stats: NewStatsRepository(store),
+			Tx:    NewTxManager(store),
+		}
+	})
+}
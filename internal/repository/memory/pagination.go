@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// sortKey возвращает значение сортировки строки; строки упорядочиваются по (значение, id)
+type sortKey[T any] func(T) string
+
+// pageCursor — содержимое непрозрачного курсора: сортировка и порядок, для которых он выдан, значение сортировки
+// и id последней строки страницы
+type pageCursor struct {
+	Sort  string           `json:"s"`
+	Order domain.SortOrder `json:"o"`
+	Key   string           `json:"k"`
+	ID    string           `json:"id"`
+}
+
+func encodeCursor(sort string, order domain.SortOrder, key, id string) string {
+	raw, _ := json.Marshal(pageCursor{Sort: sort, Order: order, Key: key, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor разбирает курсор и проверяет, что он выдан для сортировки sort в порядке order
+func decodeCursor(s, sort string, order domain.SortOrder) (*pageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", domain.ErrInvalidInput)
+	}
+
+	var cur pageCursor
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", domain.ErrInvalidInput)
+	}
+	if cur.Sort != sort || cur.Order != order {
+		return nil, fmt.Errorf("cursor was issued for sort %q %s: %w", cur.Sort, cur.Order, domain.ErrInvalidInput)
+	}
+
+	return &cur, nil
+}
+
+// lookupSort возвращает значение сортировки из допустимых для списка
+func lookupSort[T any](keys map[string]sortKey[T], sort string) (sortKey[T], error) {
+	key, ok := keys[sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort %q: %w", sort, domain.ErrInvalidInput)
+	}
+	return key, nil
+}
+
+// paginate сортирует отфильтрованные строки по (key, id), пропускает строки до курсора и обрезает выборку до limit.
+// Если строк больше, курсор строится по последней строке страницы.
+func paginate[T any](items []T, p domain.PageParams, key sortKey[T], id func(T) string) (*domain.Page[T], error) {
+	cur, err := decodeCursor(p.Cursor, p.Sort, p.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	compare := func(aKey, aID, bKey, bID string) int {
+		c := cmp.Or(cmp.Compare(aKey, bKey), cmp.Compare(aID, bID))
+		if p.Order == domain.SortDesc {
+			return -c
+		}
+		return c
+	}
+
+	slices.SortFunc(items, func(a, b T) int {
+		return compare(key(a), id(a), key(b), id(b))
+	})
+
+	if cur != nil {
+		items = slices.DeleteFunc(items, func(item T) bool {
+			return compare(key(item), id(item), cur.Key, cur.ID) <= 0
+		})
+	}
+
+	page := &domain.Page[T]{Items: items}
+	if len(items) > p.Limit {
+		page.Items = items[:p.Limit]
+		last := page.Items[p.Limit-1]
+		page.NextCursor = encodeCursor(p.Sort, p.Order, key(last), id(last))
+	}
+
+	return page, nil
+}
+
+// timeKey форматирует время так, что строки сравниваются в том же порядке, что и моменты времени
+func timeKey(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
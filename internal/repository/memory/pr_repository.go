@@ -0,0 +1,614 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+var prSortKeys = map[string]sortKey[*domain.PullRequest]{
+	domain.SortByCreatedAt: func(pr *domain.PullRequest) string { return timeKey(pr.CreatedAt) },
+	domain.SortByID:        func(pr *domain.PullRequest) string { return pr.ID },
+}
+
+type prRepository struct {
+	c conn
+}
+
+func NewPRRepository(store *Store) domain.PRRepository {
+	return &prRepository{c: store}
+}
+
+func (p *prRepository) Create(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
+	var created *domain.PullRequest
+	err := p.c.update(ctx, func(st *state) error {
+		if _, ok := st.prs[pr.ID]; ok {
+			return domain.ErrAlreadyExists
+		}
+		if _, ok := st.users[pr.AuthorID]; !ok {
+			return domain.ErrNotFound
+		}
+
+		at := now()
+		row := domain.PullRequest{
+			ID:        pr.ID,
+			Name:      pr.Name,
+			AuthorID:  pr.AuthorID,
+			Status:    pr.Status,
+			IsDraft:   pr.IsDraft,
+			CreatedAt: &at,
+		}
+		st.prs[pr.ID] = prRow{pr: row}
+		created = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (p *prRepository) FetchByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	var pr *domain.PullRequest
+	err := p.c.view(ctx, func(st *state) error {
+		row, ok := st.prs[prID]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		pr = &row.pr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// FetchByIDForUpdate не отличается от FetchByID: пишущие транзакции и так выполняются по очереди
+func (p *prRepository) FetchByIDForUpdate(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return p.FetchByID(ctx, prID)
+}
+
+func (p *prRepository) List(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+	key, err := lookupSort(prSortKeys, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := []*domain.PullRequest{}
+	err = p.c.view(ctx, func(st *state) error {
+		for _, row := range st.prs {
+			pr := row.pr
+			if filter.Status != "" && pr.Status != filter.Status ||
+				filter.AuthorID != "" && pr.AuthorID != filter.AuthorID ||
+				filter.ReviewerID != "" && !st.assigned(pr.ID, filter.ReviewerID) ||
+				filter.TeamName != "" && (st.users[pr.AuthorID].TeamName != filter.TeamName) ||
+				!inRange(pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo) {
+				continue
+			}
+
+			pr.Reviewers = st.reviewerIDs(pr.ID)
+			slices.Sort(pr.Reviewers)
+			prs = append(prs, &pr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(prs, filter.PageParams, key, func(pr *domain.PullRequest) string { return pr.ID })
+}
+
+func (p *prRepository) UpdateStatusMerged(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
+	return p.updatePR(ctx, prID, func(pr *domain.PullRequest) {
+		pr.Status = domain.StatusMerged
+		if pr.MergedAt == nil {
+			at := now()
+			pr.MergedAt = &at
+		}
+		if pr.MergeOverrideBy == "" {
+			pr.MergeOverrideBy = overrideBy
+		}
+	})
+}
+
+// UpdateStatusClosed закрывает PR без merge; повторное закрытие не меняет closed_at
+func (p *prRepository) UpdateStatusClosed(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return p.updatePR(ctx, prID, func(pr *domain.PullRequest) {
+		pr.Status = domain.StatusClosed
+		if pr.ClosedAt == nil {
+			at := now()
+			pr.ClosedAt = &at
+		}
+	})
+}
+
+// UpdateStatusOpen переоткрывает закрытый PR
+func (p *prRepository) UpdateStatusOpen(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return p.updatePR(ctx, prID, func(pr *domain.PullRequest) {
+		pr.Status = domain.StatusOpen
+		pr.ClosedAt = nil
+	})
+}
+
+// UpdateReady снимает с PR признак черновика
+func (p *prRepository) UpdateReady(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return p.updatePR(ctx, prID, func(pr *domain.PullRequest) {
+		pr.IsDraft = false
+	})
+}
+
+func (p *prRepository) updatePR(ctx context.Context, prID string, change func(pr *domain.PullRequest)) (*domain.PullRequest, error) {
+	var pr *domain.PullRequest
+	err := p.c.update(ctx, func(st *state) error {
+		row, ok := st.prs[prID]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		change(&row.pr)
+		st.prs[prID] = row
+		pr = &row.pr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (p *prRepository) ListReviewableByUserID(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+	return p.listReviewable(ctx, userID, func(domain.ReviewerState, *domain.PullRequest) bool { return true })
+}
+
+// ListReviewableByUserIDAndState возвращает открытые PR, где решение пользователя находится в состоянии state
+func (p *prRepository) ListReviewableByUserIDAndState(ctx context.Context, userID string, reviewState domain.ReviewState) ([]*domain.PullRequest, error) {
+	prs, err := p.listReviewable(ctx, userID, func(r domain.ReviewerState, pr *domain.PullRequest) bool {
+		return r.State == reviewState && pr.Status == domain.StatusOpen
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(prs, func(a, b *domain.PullRequest) int {
+		return cmp.Or(compareTime(a.CreatedAt, b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return prs, nil
+}
+
+func (p *prRepository) listReviewable(ctx context.Context, userID string, match func(r domain.ReviewerState, pr *domain.PullRequest) bool) ([]*domain.PullRequest, error) {
+	prs := []*domain.PullRequest{}
+	err := p.c.view(ctx, func(st *state) error {
+		for prID, revs := range st.reviewers {
+			i := slices.IndexFunc(revs, func(r domain.ReviewerState) bool { return r.UserID == userID })
+			if i < 0 {
+				continue
+			}
+			if row := st.prs[prID]; row.pr.Status != domain.StatusClosed && match(revs[i], &row.pr) {
+				prs = append(prs, &row.pr)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(prs, func(a, b *domain.PullRequest) int { return cmp.Compare(a.ID, b.ID) })
+	return prs, nil
+}
+
+func (p *prRepository) ListReviewers(ctx context.Context, prID string) ([]string, error) {
+	var revIDs []string
+	err := p.c.view(ctx, func(st *state) error {
+		revIDs = st.reviewerIDs(prID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revIDs, nil
+}
+
+func (p *prRepository) ListReviewerStates(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+	states := []*domain.ReviewerState{}
+	err := p.c.view(ctx, func(st *state) error {
+		for _, r := range st.reviewers[prID] {
+			states = append(states, &r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(states, func(a, b *domain.ReviewerState) int {
+		return cmp.Or(compareTime(a.UpdatedAt, b.UpdatedAt), cmp.Compare(a.UserID, b.UserID))
+	})
+	return states, nil
+}
+
+func (p *prRepository) UpdateReviewState(ctx context.Context, prID, userID string, reviewState domain.ReviewState) error {
+	return p.c.update(ctx, func(st *state) error {
+		revs := st.reviewers[prID]
+		i := slices.IndexFunc(revs, func(r domain.ReviewerState) bool { return r.UserID == userID })
+		if i < 0 {
+			return domain.ErrNotAssigned
+		}
+
+		at := now()
+		revs = slices.Clone(revs)
+		revs[i] = domain.ReviewerState{UserID: userID, State: reviewState, UpdatedAt: &at}
+		st.reviewers[prID] = revs
+		return nil
+	})
+}
+
+// InsertReviewer назначает ревьювера; повторное назначение того же пользователя — конфликт параллельных изменений,
+// как нарушение уникальности в PostgreSQL
+func (p *prRepository) InsertReviewer(ctx context.Context, prID, userID string) error {
+	return p.c.update(ctx, func(st *state) error {
+		if err := st.checkReviewerRefs(prID, userID); err != nil {
+			return err
+		}
+		if st.assigned(prID, userID) {
+			return fmt.Errorf("%w: pr_reviewers (%s, %s): %w", domain.ErrConcurrentUpdate, prID, userID, errDuplicate)
+		}
+
+		st.reviewers[prID] = append(slices.Clip(st.reviewers[prID]), domain.ReviewerState{UserID: userID, State: domain.ReviewPending})
+		return nil
+	})
+}
+
+func (p *prRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+	return p.c.update(ctx, func(st *state) error {
+		revs := st.reviewers[prID]
+		i := slices.IndexFunc(revs, func(r domain.ReviewerState) bool { return r.UserID == oldReviewerID })
+		if i < 0 {
+			return nil
+		}
+		if err := st.checkReviewerRefs(prID, newReviewerID); err != nil {
+			return err
+		}
+		if newReviewerID != oldReviewerID && st.assigned(prID, newReviewerID) {
+			return fmt.Errorf("%w: pr_reviewers (%s, %s): %w", domain.ErrConcurrentUpdate, prID, newReviewerID, errDuplicate)
+		}
+
+		st.replaceReviewer(prID, i, newReviewerID)
+		return nil
+	})
+}
+
+func (p *prRepository) RemoveReviewer(ctx context.Context, prID, userID string) error {
+	return p.c.update(ctx, func(st *state) error {
+		revs := st.reviewers[prID]
+		if i := slices.IndexFunc(revs, func(r domain.ReviewerState) bool { return r.UserID == userID }); i >= 0 {
+			st.reviewers[prID] = slices.Delete(slices.Clone(revs), i, i+1)
+		}
+		return nil
+	})
+}
+
+func (p *prRepository) ReviewerAssigned(ctx context.Context, prID, userID string) (bool, error) {
+	var exists bool
+	err := p.c.view(ctx, func(st *state) error {
+		exists = st.assigned(prID, userID)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (p *prRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	err := p.c.view(ctx, func(st *state) error {
+		for userID, n := range st.openReviews() {
+			if slices.Contains(userIDs, userID) {
+				counts[userID] = n
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ReassignOpenReviews заменяет перечисленных пользователей на всех OPEN PR по тем же правилам, что и PostgreSQL-реализация:
+// участники команды один раз упорядочиваются по стратегии, и замены раздаются по кругу в этом порядке; для weighted
+// каждая замена выбирается случайно пропорционально весу. Кандидат не получает замен сверх лимита открытых ревью;
+// если кандидатов не хватило, назначение остается прежним, а в результате NewReviewerID пуст.
+func (p *prRepository) ReassignOpenReviews(ctx context.Context, req domain.BulkReassign) ([]*domain.ReviewReassignment, error) {
+	if len(req.UserIDs) == 0 {
+		return nil, nil
+	}
+
+	type affected struct {
+		prID, oldUserID, teamName string
+		seq, slot                 int
+	}
+	type pool struct {
+		ids   []string
+		start int
+	}
+
+	var res []*domain.ReviewReassignment
+	err := p.c.update(ctx, func(st *state) error {
+		var rows []affected
+		for prID, revs := range st.reviewers {
+			if st.prs[prID].pr.Status != domain.StatusOpen {
+				continue
+			}
+			for _, r := range revs {
+				if u, ok := st.users[r.UserID]; ok && slices.Contains(req.UserIDs, r.UserID) {
+					rows = append(rows, affected{prID: prID, oldUserID: r.UserID, teamName: u.TeamName})
+				}
+			}
+		}
+		slices.SortFunc(rows, func(a, b affected) int {
+			return cmp.Or(cmp.Compare(a.prID, b.prID), cmp.Compare(a.oldUserID, b.oldUserID))
+		})
+
+		// seq нумерует заменяемых внутри команды, slot — внутри пары (PR, команда); base — заменяемые команды до этого PR
+		seqs := make(map[string]int)
+		slots := make(map[[2]string]int)
+		base := make(map[[2]string]int)
+		for i := range rows {
+			k := [2]string{rows[i].prID, rows[i].teamName}
+			seqs[rows[i].teamName]++
+			slots[k]++
+			rows[i].seq, rows[i].slot = seqs[rows[i].teamName], slots[k]
+			if rows[i].slot == 1 {
+				base[k] = rows[i].seq - 1
+			}
+		}
+
+		load := st.openReviews()
+		at := now()
+		pools := make(map[string]pool)
+		for teamName := range seqs {
+			if team, ok := st.teams[teamName]; !ok || team.ArchivedAt != nil {
+				continue
+			}
+
+			var ids []string
+			for _, c := range st.users {
+				limit := st.reviewLimit(c.ID)
+				if c.TeamName != teamName ||
+					!c.IsActive ||
+					slices.Contains(req.UserIDs, c.ID) ||
+					st.absentAt(c.ID, at) ||
+					limit != nil && load[c.ID] >= *limit {
+					continue
+				}
+				ids = append(ids, c.ID)
+			}
+			rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+
+			start := 0
+			switch req.Strategy {
+			case domain.ReassignLeastLoaded:
+				slices.SortStableFunc(ids, func(a, b string) int { return cmp.Compare(load[a], load[b]) })
+			case domain.ReassignRoundRobin:
+				slices.Sort(ids)
+				for _, id := range ids {
+					if id <= req.Cursor {
+						start++
+					}
+				}
+			}
+			pools[teamName] = pool{ids: ids, start: start}
+		}
+
+		picks := make(map[[2]string][]string)
+		for k := range slots {
+			prID, teamName := k[0], k[1]
+			pl := pools[teamName]
+			n := len(pl.ids)
+
+			type keyed struct {
+				id  string
+				key float64
+			}
+			var cands []keyed
+			for pos, id := range pl.ids {
+				if id == st.prs[prID].pr.AuthorID || st.assigned(prID, id) {
+					continue
+				}
+				key := float64(((pos-pl.start-base[k])%n + n) % n)
+				if req.Strategy == domain.ReassignWeighted {
+					w := req.Weights[id]
+					if w <= 0 {
+						w = 1
+					}
+					key = -math.Log(1-rand.Float64()) / float64(w)
+				}
+				cands = append(cands, keyed{id: id, key: key})
+			}
+			slices.SortStableFunc(cands, func(a, b keyed) int { return cmp.Compare(a.key, b.key) })
+			for _, c := range cands {
+				picks[k] = append(picks[k], c.id)
+			}
+		}
+
+		// один кандидат может выпасть на несколько PR: сверх лимита замены не назначаются
+		taken := make(map[string]int)
+		res = make([]*domain.ReviewReassignment, 0, len(rows))
+		for _, a := range rows {
+			ra := &domain.ReviewReassignment{PRID: a.prID, OldReviewerID: a.oldUserID}
+			if ids := picks[[2]string{a.prID, a.teamName}]; a.slot <= len(ids) {
+				newUserID := ids[a.slot-1]
+				taken[newUserID]++
+				if limit := st.reviewLimit(newUserID); limit == nil || load[newUserID]+taken[newUserID] <= *limit {
+					ra.NewReviewerID = newUserID
+				}
+			}
+			res = append(res, ra)
+		}
+
+		for _, ra := range res {
+			if ra.NewReviewerID == "" {
+				continue
+			}
+			i := slices.IndexFunc(st.reviewers[ra.PRID], func(r domain.ReviewerState) bool { return r.UserID == ra.OldReviewerID })
+			st.replaceReviewer(ra.PRID, i, ra.NewReviewerID)
+			st.appendEvent(domain.AssignmentEvent{
+				PRID:           ra.PRID,
+				Type:           domain.EventReassigned,
+				UserID:         ra.NewReviewerID,
+				PreviousUserID: ra.OldReviewerID,
+				Actor:          req.Actor,
+				Reason:         req.Reason,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddAssignmentEvents добавляет записи в журнал назначений
+func (p *prRepository) AddAssignmentEvents(ctx context.Context, events ...*domain.AssignmentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return p.c.update(ctx, func(st *state) error {
+		for _, e := range events {
+			if _, ok := st.prs[e.PRID]; !ok {
+				return fmt.Errorf("review_assignment_events: pr %q: %w", e.PRID, errForeignKey)
+			}
+			for _, userID := range []string{e.UserID, e.PreviousUserID} {
+				if _, ok := st.users[userID]; userID != "" && !ok {
+					return fmt.Errorf("review_assignment_events: user %q: %w", userID, errForeignKey)
+				}
+			}
+		}
+
+		for _, e := range events {
+			st.appendEvent(domain.AssignmentEvent{
+				PRID:           e.PRID,
+				Type:           e.Type,
+				UserID:         e.UserID,
+				PreviousUserID: e.PreviousUserID,
+				Actor:          e.Actor,
+				Reason:         e.Reason,
+			})
+		}
+		return nil
+	})
+}
+
+func (p *prRepository) ListAssignmentEvents(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+	events := []*domain.AssignmentEvent{}
+	err := p.c.view(ctx, func(st *state) error {
+		for _, e := range st.events {
+			if e.PRID == prID {
+				events = append(events, &e)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (st *state) assigned(prID, userID string) bool {
+	return slices.ContainsFunc(st.reviewers[prID], func(r domain.ReviewerState) bool { return r.UserID == userID })
+}
+
+func (st *state) reviewerIDs(prID string) []string {
+	ids := []string{}
+	for _, r := range st.reviewers[prID] {
+		ids = append(ids, r.UserID)
+	}
+	return ids
+}
+
+func (st *state) checkReviewerRefs(prID, userID string) error {
+	if _, ok := st.prs[prID]; !ok {
+		return fmt.Errorf("pr_reviewers: pr %q: %w", prID, errForeignKey)
+	}
+	if _, ok := st.users[userID]; !ok {
+		return fmt.Errorf("pr_reviewers: user %q: %w", userID, errForeignKey)
+	}
+	return nil
+}
+
+// replaceReviewer ставит newUserID на место i-го ревьювера PR со сбросом решения и увеличивает счетчик переназначений
+func (st *state) replaceReviewer(prID string, i int, newUserID string) {
+	revs := slices.Clone(st.reviewers[prID])
+	revs[i] = domain.ReviewerState{UserID: newUserID, State: domain.ReviewPending}
+	st.reviewers[prID] = revs
+
+	row := st.prs[prID]
+	row.reassignCount++
+	st.prs[prID] = row
+}
+
+// openReviews считает назначения пользователей на OPEN PR
+func (st *state) openReviews() map[string]int {
+	load := make(map[string]int)
+	for prID, revs := range st.reviewers {
+		if st.prs[prID].pr.Status != domain.StatusOpen {
+			continue
+		}
+		for _, r := range revs {
+			load[r.UserID]++
+		}
+	}
+	return load
+}
+
+func (st *state) appendEvent(e domain.AssignmentEvent) {
+	st.lastEventID++
+	e.ID = st.lastEventID
+	e.CreatedAt = now()
+	st.events = append(st.events, e)
+}
+
+// inRange сообщает, что момент ts попадает в полуинтервал [from, to); границы nil не ограничивают,
+// а отсутствующий ts попадает только в неограниченный интервал
+func inRange(ts, from, to *time.Time) bool {
+	if from != nil && (ts == nil || ts.Before(*from)) {
+		return false
+	}
+	if to != nil && (ts == nil || !ts.Before(*to)) {
+		return false
+	}
+	return true
+}
+
+// compareTime сравнивает моменты времени как ORDER BY в Postgres: отсутствующее время идет последним
+func compareTime(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Compare(*b)
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"cmp"
+	"context"
+	"slices"
+)
+
+type statsRepository struct {
+	c conn
+}
+
+func NewStatsRepository(store *Store) domain.StatsRepository {
+	return &statsRepository{c: store}
+}
+
+func (s *statsRepository) UserAssignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error) {
+	res := []*domain.UserAssignmentStats{}
+	err := s.c.view(ctx, func(st *state) error {
+		byUser := make(map[string]*domain.UserAssignmentStats)
+		for _, u := range st.users {
+			if filter.TeamName != "" && u.TeamName != filter.TeamName {
+				continue
+			}
+			stats := &domain.UserAssignmentStats{UserID: u.ID, Username: u.Name, TeamName: u.TeamName, IsActive: u.IsActive}
+			byUser[u.ID] = stats
+			res = append(res, stats)
+		}
+
+		for prID, revs := range st.reviewers {
+			pr := st.prs[prID].pr
+			created := inRange(pr.CreatedAt, filter.From, filter.To)
+			for _, r := range revs {
+				stats, ok := byUser[r.UserID]
+				if !ok {
+					continue
+				}
+				if created && pr.Status != domain.StatusClosed {
+					stats.Total++
+					if pr.Status == domain.StatusOpen {
+						stats.Open++
+					}
+				}
+				if pr.Status == domain.StatusMerged && inRange(pr.MergedAt, filter.From, filter.To) {
+					stats.Merged++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(res, func(a, b *domain.UserAssignmentStats) int {
+		return cmp.Or(cmp.Compare(b.Open, a.Open), cmp.Compare(b.Total, a.Total), cmp.Compare(a.UserID, b.UserID))
+	})
+	return res, nil
+}
+
+func (s *statsRepository) PRReassignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error) {
+	res := []*domain.PRReassignmentStats{}
+	err := s.c.view(ctx, func(st *state) error {
+		for _, row := range st.prs {
+			pr := row.pr
+			if filter.TeamName != "" && st.users[pr.AuthorID].TeamName != filter.TeamName ||
+				!inRange(pr.CreatedAt, filter.From, filter.To) {
+				continue
+			}
+			res = append(res, &domain.PRReassignmentStats{
+				PRID:          pr.ID,
+				Name:          pr.Name,
+				AuthorID:      pr.AuthorID,
+				Status:        pr.Status,
+				Reviewers:     len(st.reviewers[pr.ID]),
+				Reassignments: row.reassignCount,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(res, func(a, b *domain.PRReassignmentStats) int {
+		return cmp.Or(cmp.Compare(b.Reassignments, a.Reassignments), cmp.Compare(a.PRID, b.PRID))
+	})
+	return res, nil
+}
+
+func (s *statsRepository) TeamTotals(ctx context.Context, filter domain.StatsFilter) ([]*domain.TeamStats, error) {
+	res := []*domain.TeamStats{}
+	err := s.c.view(ctx, func(st *state) error {
+		byTeam := make(map[string]*domain.TeamStats)
+		for name := range st.teams {
+			if filter.TeamName != "" && name != filter.TeamName {
+				continue
+			}
+			stats := &domain.TeamStats{TeamName: name}
+			byTeam[name] = stats
+			res = append(res, stats)
+		}
+
+		for _, u := range st.users {
+			if stats, ok := byTeam[u.TeamName]; ok {
+				stats.Members++
+				if u.IsActive {
+					stats.ActiveMembers++
+				}
+			}
+		}
+
+		for _, row := range st.prs {
+			pr := row.pr
+			created := inRange(pr.CreatedAt, filter.From, filter.To)
+
+			if stats, ok := byTeam[st.users[pr.AuthorID].TeamName]; ok {
+				if pr.Status == domain.StatusOpen && created {
+					stats.OpenPRs++
+				}
+				if pr.Status == domain.StatusMerged && inRange(pr.MergedAt, filter.From, filter.To) {
+					stats.MergedPRs++
+				}
+				if created {
+					stats.Reassignments += row.reassignCount
+				}
+			}
+
+			if !created || pr.Status == domain.StatusClosed {
+				continue
+			}
+			for _, r := range st.reviewers[pr.ID] {
+				if stats, ok := byTeam[st.users[r.UserID].TeamName]; ok {
+					stats.Assignments++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(res, func(a, b *domain.TeamStats) int { return cmp.Compare(a.TeamName, b.TeamName) })
+	return res, nil
+}
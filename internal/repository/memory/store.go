@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	errDuplicate  = errors.New("duplicate key")
+	errForeignKey = errors.New("foreign key violation")
+	errCheck      = errors.New("check constraint violation")
+	errReadOnly   = errors.New("cannot execute write in a read-only transaction")
+)
+
+// prRow — строка pull_requests; ревьюверы хранятся отдельно, как в pr_reviewers
+type prRow struct {
+	pr            domain.PullRequest
+	reassignCount int
+}
+
+// state — все таблицы хранилища. Строки хранятся по значению и не изменяются на месте: запись кладет в карту
+// измененную копию, а срезы заменяет целиком. Поэтому clone достаточно скопировать карты, а не сами строки.
+type state struct {
+	teams     map[string]domain.Team
+	users     map[string]domain.User
+	prs       map[string]prRow
+	reviewers map[string][]domain.ReviewerState
+	events    []domain.AssignmentEvent
+	settings  map[string]domain.TeamSettings
+	cursors   map[string]string
+	absences  map[int64]domain.Absence
+
+	lastEventID   int64
+	lastAbsenceID int64
+}
+
+func newState() *state {
+	return &state{
+		teams:     make(map[string]domain.Team),
+		users:     make(map[string]domain.User),
+		prs:       make(map[string]prRow),
+		reviewers: make(map[string][]domain.ReviewerState),
+		settings:  make(map[string]domain.TeamSettings),
+		cursors:   make(map[string]string),
+		absences:  make(map[int64]domain.Absence),
+	}
+}
+
+func (s *state) clone() *state {
+	return &state{
+		teams:     maps.Clone(s.teams),
+		users:     maps.Clone(s.users),
+		prs:       maps.Clone(s.prs),
+		reviewers: maps.Clone(s.reviewers),
+		// Clip заставляет append копию перевыделить массив и не задеть события исходного состояния
+		events:        slices.Clip(s.events),
+		settings:      maps.Clone(s.settings),
+		cursors:       maps.Clone(s.cursors),
+		absences:      maps.Clone(s.absences),
+		lastEventID:   s.lastEventID,
+		lastAbsenceID: s.lastAbsenceID,
+	}
+}
+
+// conn — источник данных репозитория: хранилище целиком (каждый вызов — отдельная транзакция) или открытая транзакция
+type conn interface {
+	view(ctx context.Context, fn func(st *state) error) error
+	update(ctx context.Context, fn func(st *state) error) error
+}
+
+// Store хранит данные сервиса в памяти процесса. Зафиксированное состояние неизменяемо: запись работает с его копией
+// и при commit подменяет целиком, поэтому читатели получают согласованный снимок без блокировок.
+// Пишущие транзакции выполняются строго по очереди.
+type Store struct {
+	// writeMu удерживается пишущей транзакцией от начала до commit или rollback
+	writeMu sync.Mutex
+	mu      sync.RWMutex
+	state   *state
+}
+
+func NewStore() *Store {
+	return &Store{state: newState()}
+}
+
+func (s *Store) snapshot() *state {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+func (s *Store) commit(st *state) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = st
+}
+
+// view читает последний зафиксированный снимок. Вызов с контекстом колбэка WithinTx этого хранилища
+// выполняется в той транзакции, иначе ожидание очереди пишущих транзакций стало бы взаимной блокировкой.
+func (s *Store) view(ctx context.Context, fn func(st *state) error) error {
+	if t := s.txFromContext(ctx); t != nil {
+		return t.view(ctx, fn)
+	}
+	return fn(s.snapshot())
+}
+
+// update выполняет запись как отдельную транзакцию
+func (s *Store) update(ctx context.Context, fn func(st *state) error) error {
+	if t := s.txFromContext(ctx); t != nil {
+		return t.update(ctx, fn)
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	st := s.snapshot().clone()
+	if err := fn(st); err != nil {
+		return err
+	}
+	s.commit(st)
+
+	return nil
+}
+
+func (s *Store) txFromContext(ctx context.Context) *tx {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok && t.store == s {
+		return t
+	}
+	return nil
+}
+
+// tx — открытая транзакция: собственная копия состояния, которая станет зафиксированной при commit
+type tx struct {
+	store    *Store
+	state    *state
+	readOnly bool
+}
+
+func (t *tx) view(_ context.Context, fn func(st *state) error) error {
+	return fn(t.state)
+}
+
+// update меняет состояние транзакции на месте. Репозитории проверяют ограничения до первого изменения,
+// поэтому ошибка записи не оставляет в транзакции частичных изменений.
+func (t *tx) update(_ context.Context, fn func(st *state) error) error {
+	if t.readOnly {
+		return errReadOnly
+	}
+	return fn(t.state)
+}
+
+// now возвращает текущее время с точностью PostgreSQL timestamptz
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
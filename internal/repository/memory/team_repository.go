@@ -0,0 +1,267 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"fmt"
+	"slices"
+)
+
+var teamSortKeys = map[string]sortKey[*domain.Team]{
+	domain.SortByName: func(t *domain.Team) string { return t.Name },
+}
+
+type teamRepository struct {
+	c conn
+}
+
+func NewTeamRepository(store *Store) domain.TeamRepository {
+	return &teamRepository{c: store}
+}
+
+func (tr *teamRepository) Create(ctx context.Context, teamName string) error {
+	return tr.c.update(ctx, func(st *state) error {
+		if _, ok := st.teams[teamName]; ok {
+			return domain.ErrAlreadyExists
+		}
+		st.teams[teamName] = domain.Team{Name: teamName}
+		return nil
+	})
+}
+
+func (tr *teamRepository) Exists(ctx context.Context, teamName string) (bool, error) {
+	var exists bool
+	err := tr.c.view(ctx, func(st *state) error {
+		_, exists = st.teams[teamName]
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (tr *teamRepository) FetchByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	var team *domain.Team
+	err := tr.c.view(ctx, func(st *state) error {
+		row, ok := st.teams[teamName]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		team = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// Rename переименовывает команду; участники, настройки и курсор round_robin переезжают на новое имя,
+// как при ON UPDATE CASCADE
+func (tr *teamRepository) Rename(ctx context.Context, teamName, newName string) error {
+	return tr.c.update(ctx, func(st *state) error {
+		team, ok := st.teams[teamName]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		if _, ok := st.teams[newName]; ok && newName != teamName {
+			return domain.ErrAlreadyExists
+		}
+
+		delete(st.teams, teamName)
+		team.Name = newName
+		st.teams[newName] = team
+
+		for id, u := range st.users {
+			if u.TeamName == teamName {
+				u.TeamName = newName
+				st.users[id] = u
+			}
+		}
+		if last, ok := st.cursors[teamName]; ok {
+			delete(st.cursors, teamName)
+			st.cursors[newName] = last
+		}
+		if settings, ok := st.settings[teamName]; ok {
+			delete(st.settings, teamName)
+			settings.TeamName = newName
+			st.settings[newName] = settings
+		}
+		for name, settings := range st.settings {
+			if i := slices.Index(settings.FallbackTeams, teamName); i >= 0 {
+				settings.FallbackTeams = slices.Clone(settings.FallbackTeams)
+				settings.FallbackTeams[i] = newName
+				st.settings[name] = settings
+			}
+		}
+
+		return nil
+	})
+}
+
+// SetArchived архивирует команду или возвращает ее из архива; повторный вызов сохраняет исходное время архивации
+func (tr *teamRepository) SetArchived(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+	var team *domain.Team
+	err := tr.c.update(ctx, func(st *state) error {
+		row, ok := st.teams[teamName]
+		if !ok {
+			return domain.ErrNotFound
+		}
+
+		switch {
+		case !archived:
+			row.ArchivedAt = nil
+		case row.ArchivedAt == nil:
+			at := now()
+			row.ArchivedAt = &at
+		}
+		st.teams[teamName] = row
+		team = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// Delete удаляет команду вместе с ее настройками и курсором round_robin и убирает ее из резервных у других команд.
+// Участники к этому моменту должны быть отвязаны.
+func (tr *teamRepository) Delete(ctx context.Context, teamName string) error {
+	return tr.c.update(ctx, func(st *state) error {
+		if _, ok := st.teams[teamName]; !ok {
+			return domain.ErrNotFound
+		}
+		for _, u := range st.users {
+			if u.TeamName == teamName {
+				return fmt.Errorf("users: team %q still has members: %w", teamName, errForeignKey)
+			}
+		}
+
+		delete(st.teams, teamName)
+		delete(st.cursors, teamName)
+		delete(st.settings, teamName)
+		for name, settings := range st.settings {
+			if slices.Contains(settings.FallbackTeams, teamName) {
+				settings.FallbackTeams = slices.DeleteFunc(slices.Clone(settings.FallbackTeams), func(f string) bool { return f == teamName })
+				st.settings[name] = settings
+			}
+		}
+
+		return nil
+	})
+}
+
+func (tr *teamRepository) List(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error) {
+	key, err := lookupSort(teamSortKeys, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := []*domain.Team{}
+	err = tr.c.view(ctx, func(st *state) error {
+		for _, t := range st.teams {
+			if filter.Archived == nil || (t.ArchivedAt != nil) == *filter.Archived {
+				teams = append(teams, &t)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(teams, filter.PageParams, key, func(t *domain.Team) string { return t.Name })
+}
+
+func (tr *teamRepository) FetchSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	var settings *domain.TeamSettings
+	err := tr.c.view(ctx, func(st *state) error {
+		if _, ok := st.teams[teamName]; !ok {
+			return domain.ErrNotFound
+		}
+
+		row, ok := st.settings[teamName]
+		if !ok {
+			row = *domain.DefaultTeamSettings(teamName)
+		}
+		row.FallbackTeams = append([]string{}, row.FallbackTeams...)
+		settings = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// UpsertSettings сохраняет настройки команды; список резервных команд заменяется целиком с сохранением порядка
+func (tr *teamRepository) UpsertSettings(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+	err := tr.c.update(ctx, func(st *state) error {
+		if _, ok := st.teams[settings.TeamName]; !ok {
+			return fmt.Errorf("team_settings: team %q: %w", settings.TeamName, errForeignKey)
+		}
+		if settings.MinReviewers < 0 || settings.MinReviewers > settings.MaxReviewers ||
+			(settings.DefaultMaxOpenReviews != nil && *settings.DefaultMaxOpenReviews < 0) {
+			return fmt.Errorf("team_settings: invalid limits: %w", errCheck)
+		}
+		for i, f := range settings.FallbackTeams {
+			if _, ok := st.teams[f]; !ok {
+				return fmt.Errorf("team_fallback_teams: team %q: %w", f, errForeignKey)
+			}
+			if f == settings.TeamName || slices.Contains(settings.FallbackTeams[:i], f) {
+				return fmt.Errorf("team_fallback_teams: team %q: %w", f, errCheck)
+			}
+		}
+
+		st.settings[settings.TeamName] = domain.TeamSettings{
+			TeamName:              settings.TeamName,
+			MinReviewers:          settings.MinReviewers,
+			MaxReviewers:          settings.MaxReviewers,
+			FallbackTeams:         slices.Clone(settings.FallbackTeams),
+			DefaultMaxOpenReviews: settings.DefaultMaxOpenReviews,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.FetchSettings(ctx, settings.TeamName)
+}
+
+// LockReviewCursor возвращает последнего назначенного по кругу ревьювера команды. Отдельная блокировка не нужна:
+// пишущая транзакция и так выполняется в одиночку до своего завершения.
+func (tr *teamRepository) LockReviewCursor(ctx context.Context, teamName string) (string, error) {
+	var lastUserID string
+	err := tr.c.update(ctx, func(st *state) error {
+		if _, ok := st.teams[teamName]; !ok {
+			return fmt.Errorf("team_review_cursors: team %q: %w", teamName, errForeignKey)
+		}
+		last, ok := st.cursors[teamName]
+		if !ok {
+			st.cursors[teamName] = ""
+		}
+		lastUserID = last
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return lastUserID, nil
+}
+
+func (tr *teamRepository) UpdateReviewCursor(ctx context.Context, teamName, lastUserID string) error {
+	return tr.c.update(ctx, func(st *state) error {
+		if _, ok := st.cursors[teamName]; ok {
+			st.cursors[teamName] = lastUserID
+		}
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+)
+
+// txKey — ключ контекста, под которым лежит открытая WithinTx транзакция
+type txKey struct{}
+
+type txManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) domain.TxManager {
+	return &txManager{store: store}
+}
+
+// WithinTx выполняет fn на копии зафиксированного состояния и при успехе подменяет им состояние хранилища.
+// Пишущие транзакции выполняются по очереди, поэтому любая изоляция фактически SERIALIZABLE, а ошибок
+// сериализации и повторов не бывает; только читающая транзакция работает со снимком без ожидания очереди.
+// StatementTimeout не применяется: запросы к памяти не выполняются долго.
+// Вызов из колбэка другого WithinTx выполняется во вложенной транзакции на копии ее состояния.
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context, repos *domain.Repos) error, opts ...domain.TxOption) error {
+	if outer := m.store.txFromContext(ctx); outer != nil {
+		return withinSavepoint(ctx, outer, fn)
+	}
+
+	o := domain.ApplyTxOptions(opts...)
+	if o.ReadOnly {
+		t := &tx{store: m.store, state: m.store.snapshot(), readOnly: true}
+		return fn(context.WithValue(ctx, txKey{}, t), newRepos(t))
+	}
+
+	m.store.writeMu.Lock()
+	defer m.store.writeMu.Unlock()
+
+	t := &tx{store: m.store, state: m.store.snapshot().clone()}
+	if err := fn(context.WithValue(ctx, txKey{}, t), newRepos(t)); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.store.commit(t.state)
+
+	return nil
+}
+
+// withinSavepoint выполняет fn на копии состояния outer; при успехе копия становится состоянием outer,
+// при ошибке отбрасывается, а судьбу внешней транзакции решает вызывающий
+func withinSavepoint(ctx context.Context, outer *tx, fn func(ctx context.Context, repos *domain.Repos) error) error {
+	sp := &tx{store: outer.store, state: outer.state.clone(), readOnly: outer.readOnly}
+	if err := fn(context.WithValue(ctx, txKey{}, sp), newRepos(sp)); err != nil {
+		return err
+	}
+	outer.state = sp.state
+
+	return nil
+}
+
+func newRepos(c conn) *domain.Repos {
+	return &domain.Repos{
+		PR:   &prRepository{c: c},
+		User: &userRepository{c: c},
+		Team: &teamRepository{c: c},
+	}
+}
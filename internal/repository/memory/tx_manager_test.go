@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager_UncommittedChangesAreIsolated(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	teams := NewTeamRepository(store)
+
+	err := NewTxManager(store).WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		require.NoError(t, repos.Team.Create(ctx, "tx_team"))
+
+		// запрос вне транзакции видит только зафиксированное состояние
+		exists, err := teams.Exists(context.Background(), "tx_team")
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		// репозиторий хранилища, вызванный с контекстом транзакции, участвует в ней
+		exists, err = teams.Exists(ctx, "tx_team")
+		require.NoError(t, err)
+		require.True(t, exists)
+		return nil
+	})
+	require.NoError(t, err)
+
+	exists, err := teams.Exists(ctx, "tx_team")
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestTxManager_ReadOnlyRejectsWrites(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+
+	err := NewTxManager(store).WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		return repos.Team.Create(ctx, "readonly_team")
+	}, domain.ReadOnly())
+	require.ErrorIs(t, err, errReadOnly)
+}
@@ -0,0 +1,442 @@
+package memory
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var userSortKeys = map[string]sortKey[*domain.User]{
+	domain.SortByID:   func(u *domain.User) string { return u.ID },
+	domain.SortByName: func(u *domain.User) string { return u.Name },
+}
+
+type userRepository struct {
+	c conn
+}
+
+func NewUserRepository(store *Store) domain.UserRepository {
+	return &userRepository{c: store}
+}
+
+// Upsert создает пользователя или обновляет имя, команду и активность существующего; лимит ревью сохраняется
+func (ur *userRepository) Upsert(ctx context.Context, user *domain.User) error {
+	return ur.c.update(ctx, func(st *state) error {
+		if _, ok := st.teams[user.TeamName]; !ok {
+			return fmt.Errorf("users: team %q: %w", user.TeamName, errForeignKey)
+		}
+
+		row := st.users[user.ID]
+		row.ID = user.ID
+		row.Name = user.Name
+		row.TeamName = user.TeamName
+		row.IsActive = user.IsActive
+		st.users[user.ID] = row
+
+		return nil
+	})
+}
+
+func (ur *userRepository) FetchByID(ctx context.Context, userID string) (*domain.User, error) {
+	var user *domain.User
+	err := ur.c.view(ctx, func(st *state) error {
+		row, ok := st.users[userID]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		user = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// FetchByIDs возвращает найденных пользователей из userIDs; отсутствующие id пропускаются
+func (ur *userRepository) FetchByIDs(ctx context.Context, userIDs []string) ([]*domain.User, error) {
+	var users []*domain.User
+	err := ur.c.view(ctx, func(st *state) error {
+		users = filterUsers(st, func(u domain.User) bool { return slices.Contains(userIDs, u.ID) })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (ur *userRepository) FetchByTeam(ctx context.Context, teamName string) ([]*domain.User, error) {
+	var users []*domain.User
+	err := ur.c.view(ctx, func(st *state) error {
+		users = filterUsers(st, func(u domain.User) bool { return u.TeamName == teamName })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// FetchActiveByTeam возвращает активных участников команды — кандидатов в ревьюверы, кроме отсутствующих в текущий момент.
+// Для архивной команды список пуст.
+func (ur *userRepository) FetchActiveByTeam(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+	var users []*domain.User
+	err := ur.c.view(ctx, func(st *state) error {
+		team, ok := st.teams[teamName]
+		if !ok || team.ArchivedAt != nil {
+			users = []*domain.User{}
+			return nil
+		}
+
+		at := now()
+		users = filterUsers(st, func(u domain.User) bool {
+			return u.TeamName == teamName &&
+				u.IsActive &&
+				!st.absentAt(u.ID, at) &&
+				!slices.Contains(excludeIDs, u.ID)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (ur *userRepository) Exists(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	err := ur.c.view(ctx, func(st *state) error {
+		_, exists = st.users[userID]
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (ur *userRepository) UpdateIsActive(ctx context.Context, userID string, active bool) (*domain.User, error) {
+	return ur.updateUser(ctx, userID, func(u *domain.User) error {
+		u.IsActive = active
+		return nil
+	})
+}
+
+func (ur *userRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+	return ur.updateUser(ctx, userID, func(u *domain.User) error {
+		if limit != nil && *limit < 0 {
+			return fmt.Errorf("users: max_open_reviews must be non-negative: %w", errCheck)
+		}
+		u.MaxOpenReviews = limit
+		return nil
+	})
+}
+
+func (ur *userRepository) updateUser(ctx context.Context, userID string, change func(u *domain.User) error) (*domain.User, error) {
+	var user *domain.User
+	err := ur.c.update(ctx, func(st *state) error {
+		row, ok := st.users[userID]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		if err := change(&row); err != nil {
+			return err
+		}
+		st.users[userID] = row
+		user = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (ur *userRepository) FetchReviewLimits(ctx context.Context, userIDs []string) (map[string]int, error) {
+	limits := make(map[string]int, len(userIDs))
+	err := ur.c.view(ctx, func(st *state) error {
+		for _, id := range userIDs {
+			if limit := st.reviewLimit(id); limit != nil {
+				limits[id] = *limit
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// DeactivateTeamMembers деактивирует перечисленных участников команды (или всех, кроме перечисленных, при allExcept)
+// и возвращает их id. Пользователи из других команд не затрагиваются.
+func (ur *userRepository) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error) {
+	ids := []string{}
+	err := ur.c.update(ctx, func(st *state) error {
+		for _, u := range filterUsers(st, func(u domain.User) bool {
+			return u.TeamName == teamName && slices.Contains(userIDs, u.ID) != allExcept
+		}) {
+			u.IsActive = false
+			st.users[u.ID] = *u
+			ids = append(ids, u.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// RemoveFromTeam отвязывает перечисленных участников от команды и возвращает их id.
+// Пользователи из других команд не затрагиваются.
+func (ur *userRepository) RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+	ids := []string{}
+	err := ur.c.update(ctx, func(st *state) error {
+		for _, u := range filterUsers(st, func(u domain.User) bool {
+			return u.TeamName == teamName && slices.Contains(userIDs, u.ID)
+		}) {
+			u.TeamName = ""
+			st.users[u.ID] = *u
+			ids = append(ids, u.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (ur *userRepository) List(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+	key, err := lookupSort(userSortKeys, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*domain.User
+	err = ur.c.view(ctx, func(st *state) error {
+		users = filterUsers(st, func(u domain.User) bool {
+			return (filter.TeamName == "" || u.TeamName == filter.TeamName) &&
+				(filter.IsActive == nil || u.IsActive == *filter.IsActive)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(users, filter.PageParams, key, func(u *domain.User) string { return u.ID })
+}
+
+func (ur *userRepository) AddAbsence(ctx context.Context, absence *domain.Absence) (*domain.Absence, error) {
+	var created *domain.Absence
+	err := ur.c.update(ctx, func(st *state) error {
+		if _, ok := st.users[absence.UserID]; !ok {
+			return fmt.Errorf("user_absences: user %q: %w", absence.UserID, errForeignKey)
+		}
+		if absence.DelegateID != "" {
+			if _, ok := st.users[absence.DelegateID]; !ok {
+				return fmt.Errorf("user_absences: delegate %q: %w", absence.DelegateID, errForeignKey)
+			}
+		}
+		if !absence.EndsAt.After(absence.StartsAt) || absence.DelegateID == absence.UserID {
+			return fmt.Errorf("user_absences: invalid period or delegate: %w", errCheck)
+		}
+
+		st.lastAbsenceID++
+		row := domain.Absence{
+			ID:         st.lastAbsenceID,
+			UserID:     absence.UserID,
+			StartsAt:   absence.StartsAt,
+			EndsAt:     absence.EndsAt,
+			DelegateID: absence.DelegateID,
+			CreatedAt:  now(),
+		}
+		st.absences[row.ID] = row
+		created = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (ur *userRepository) HasOverlappingAbsence(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error) {
+	var exists bool
+	err := ur.c.view(ctx, func(st *state) error {
+		for _, a := range st.absences {
+			if a.UserID == userID && a.StartsAt.Before(endsAt) && a.EndsAt.After(startsAt) {
+				exists = true
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// ListAbsences возвращает периоды отсутствия пользователя по времени начала
+func (ur *userRepository) ListAbsences(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	var absences []*domain.Absence
+	err := ur.c.view(ctx, func(st *state) error {
+		absences = filterAbsences(st, func(a domain.Absence) bool { return a.UserID == userID })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+func (ur *userRepository) FetchCurrentAbsence(ctx context.Context, userID string) (*domain.Absence, error) {
+	var current *domain.Absence
+	err := ur.c.view(ctx, func(st *state) error {
+		at := now()
+		absences := filterAbsences(st, func(a domain.Absence) bool {
+			return a.UserID == userID && !a.StartsAt.After(at) && a.EndsAt.After(at)
+		})
+		if len(absences) == 0 {
+			return domain.ErrNotFound
+		}
+		current = absences[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+func (ur *userRepository) DeleteAbsence(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	var deleted *domain.Absence
+	err := ur.c.update(ctx, func(st *state) error {
+		row, ok := st.absences[absenceID]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		delete(st.absences, absenceID)
+		deleted = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil
+}
+
+// ListDueHandoffs возвращает начавшиеся отсутствия с заместителем, ревью по которым еще не переданы.
+// Блокировка строк не нужна: пишущие транзакции и так выполняются по очереди.
+func (ur *userRepository) ListDueHandoffs(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	var absences []*domain.Absence
+	err := ur.c.view(ctx, func(st *state) error {
+		at := now()
+		absences = filterAbsences(st, func(a domain.Absence) bool {
+			return a.DelegateID != "" &&
+				a.HandedOffAt == nil &&
+				!a.StartsAt.After(at) &&
+				a.EndsAt.After(at) &&
+				(userID == "" || a.UserID == userID)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+func (ur *userRepository) MarkHandedOff(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	var absence *domain.Absence
+	err := ur.c.update(ctx, func(st *state) error {
+		row, ok := st.absences[absenceID]
+		if !ok {
+			return domain.ErrNotFound
+		}
+		at := now()
+		row.HandedOffAt = &at
+		st.absences[absenceID] = row
+		absence = &row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+// filterUsers возвращает копии подходящих пользователей, упорядоченные по id
+func filterUsers(st *state, match func(u domain.User) bool) []*domain.User {
+	users := []*domain.User{}
+	for _, u := range st.users {
+		if match(u) {
+			users = append(users, &u)
+		}
+	}
+	sortUsers(users)
+	return users
+}
+
+func sortUsers(users []*domain.User) {
+	slices.SortFunc(users, func(a, b *domain.User) int { return cmp.Compare(a.ID, b.ID) })
+}
+
+// filterAbsences возвращает копии подходящих периодов отсутствия, упорядоченные по началу и id
+func filterAbsences(st *state, match func(a domain.Absence) bool) []*domain.Absence {
+	absences := []*domain.Absence{}
+	for _, a := range st.absences {
+		if match(a) {
+			absences = append(absences, &a)
+		}
+	}
+	slices.SortFunc(absences, func(a, b *domain.Absence) int {
+		return cmp.Or(a.StartsAt.Compare(b.StartsAt), cmp.Compare(a.ID, b.ID))
+	})
+	return absences
+}
+
+// absentAt сообщает, что у пользователя есть период отсутствия, идущий в момент at
+func (st *state) absentAt(userID string, at time.Time) bool {
+	for _, a := range st.absences {
+		if a.UserID == userID && !a.StartsAt.After(at) && a.EndsAt.After(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewLimit возвращает лимит открытых ревью пользователя: личный или команды по умолчанию; nil — без лимита
+func (st *state) reviewLimit(userID string) *int {
+	u, ok := st.users[userID]
+	if !ok {
+		return nil
+	}
+	if u.MaxOpenReviews != nil {
+		return u.MaxOpenReviews
+	}
+	return st.settings[u.TeamName].DefaultMaxOpenReviews
+}
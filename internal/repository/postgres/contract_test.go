@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/repository/repotest"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Stores {
+		require.NoError(t, testutils.PrepareTestTablesWithFixtures(context.Background(), testPool))
+
+		return &repotest.Stores{
+			User:  NewUserRepository(testPool),
+			Team:  NewTeamRepository(testPool),
+			PR:    NewPRRepository(testPool),
+			Stats: NewStatsRepository(testPool),
+			Tx:    NewTxManager(testPool),
+		}
+	})
+}
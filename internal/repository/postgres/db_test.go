@@ -21,6 +21,20 @@ func TestMain(m *testing.M) {
 		"postgres:16",
 		postgres.WithInitScripts(
 			"../../../migrations/0001_init_schema.up.sql",
+			"../../../migrations/0002_team_review_cursors.up.sql",
+			"../../../migrations/0003_pr_reviewers_user_idx.up.sql",
+			"../../../migrations/0004_pr_reassign_count.up.sql",
+			"../../../migrations/0005_review_assignment_events.up.sql",
+			"../../../migrations/0006_pr_closed_status.up.sql",
+			"../../../migrations/0007_pr_is_draft.up.sql",
+			"../../../migrations/0008_pr_reviewer_state.up.sql",
+			"../../../migrations/0009_pr_merge_override.up.sql",
+			"../../../migrations/0010_users_team_nullable.up.sql",
+			"../../../migrations/0011_team_lifecycle.up.sql",
+			"../../../migrations/0012_list_indexes.up.sql",
+			"../../../migrations/0013_team_settings.up.sql",
+			"../../../migrations/0014_review_capacity.up.sql",
+			"../../../migrations/0015_user_absences.up.sql",
 		),
 		postgres.WithDatabase("app_test"),
 		postgres.WithUsername("postgres"),
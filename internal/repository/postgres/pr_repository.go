@@ -3,12 +3,20 @@ package postgres
 import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+var prSortColumns = map[string]sortColumn{
+	domain.SortByCreatedAt: {expr: "p.created_at", cast: "timestamptz"},
+	domain.SortByID:        {expr: "p.id", cast: "text", unique: true},
+}
+
 type prRepository struct {
 	q Querier
 }
@@ -19,16 +27,16 @@ func NewPRRepository(q Querier) domain.PRRepository {
 
 func (p *prRepository) Create(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
 	const q = `
-        INSERT INTO pull_requests (id, name, author_id, status)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id, name, author_id, status, created_at;
+        INSERT INTO pull_requests (id, name, author_id, status, is_draft)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, name, author_id, status, is_draft, created_at;
     `
 
 	var created domain.PullRequest
-	err := p.q.QueryRow(ctx, q, pr.ID, pr.Name, pr.AuthorID, pr.Status).Scan(
+	err := p.q.QueryRow(ctx, q, pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.IsDraft).Scan(
 		&created.ID, &created.Name,
 		&created.AuthorID, &created.Status,
-		&created.CreatedAt)
+		&created.IsDraft, &created.CreatedAt)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -47,13 +55,13 @@ func (p *prRepository) Create(ctx context.Context, pr *domain.PullRequest) (*dom
 
 func (p *prRepository) FetchByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	const q = `
-		SELECT id, name, author_id, status, created_at, merged_at
+		SELECT id, name, author_id, status, is_draft, created_at, merged_at, closed_at, COALESCE(merge_override_by, '')
 		FROM pull_requests
 		WHERE id = $1;
 	`
 
 	var pr domain.PullRequest
-	err := p.q.QueryRow(ctx, q, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+	err := p.q.QueryRow(ctx, q, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeOverrideBy)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
@@ -64,17 +72,142 @@ func (p *prRepository) FetchByID(ctx context.Context, prID string) (*domain.Pull
 	return &pr, nil
 }
 
-func (p *prRepository) UpdateStatusMerged(ctx context.Context, prID string) (*domain.PullRequest, error) {
+func (p *prRepository) FetchByIDForUpdate(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		SELECT id, name, author_id, status, is_draft, created_at, merged_at, closed_at, COALESCE(merge_override_by, '')
+		FROM pull_requests
+		WHERE id = $1
+		FOR UPDATE;
+	`
+
+	var pr domain.PullRequest
+	err := p.q.QueryRow(ctx, q, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeOverrideBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (p *prRepository) List(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+	col, err := lookupSort(prSortColumns, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decodeCursor(filter.Cursor, filter.Sort, filter.Order, col)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, orderBy, keyArgs := keyset(col, "p.id", filter.Order, cur, 8)
+	q := fmt.Sprintf(`
+		SELECT p.id, p.name, p.author_id, p.status, p.is_draft, p.created_at, p.merged_at, p.closed_at,
+		       COALESCE(p.merge_override_by, ''),
+		       ARRAY(SELECT r.user_id FROM pr_reviewers r WHERE r.pr_id = p.id ORDER BY r.user_id)
+		FROM pull_requests p
+		LEFT JOIN users a ON a.id = p.author_id
+		WHERE ($1::text = '' OR p.status = $1)
+		  AND ($2::text = '' OR p.author_id = $2)
+		  AND ($3::text = '' OR EXISTS (
+		          SELECT 1
+		          FROM pr_reviewers x
+		          WHERE x.pr_id = p.id
+		            AND x.user_id = $3
+		      ))
+		  AND ($4::text = '' OR a.team_name = $4)
+		  AND ($5::timestamptz IS NULL OR p.created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR p.created_at < $6)
+		  AND %s
+		ORDER BY %s
+		LIMIT $7;
+	`, cond, orderBy)
+
+	args := append([]any{
+		string(filter.Status), filter.AuthorID, filter.ReviewerID, filter.TeamName,
+		filter.CreatedFrom, filter.CreatedTo, filter.Limit + 1,
+	}, keyArgs...)
+	rows, err := p.q.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.PullRequest, error) {
+		var pr domain.PullRequest
+		if err := r.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeOverrideBy, &pr.Reviewers); err != nil {
+			return nil, err
+		}
+		return &pr, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(prs, filter.Limit, filter.Sort, filter.Order, func(pr *domain.PullRequest) (string, string) {
+		if filter.Sort == domain.SortByCreatedAt {
+			return pr.CreatedAt.Format(time.RFC3339Nano), pr.ID
+		}
+		return pr.ID, pr.ID
+	}), nil
+}
+
+func (p *prRepository) UpdateStatusMerged(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
 	const q = `
 		UPDATE pull_requests
 			SET status = 'MERGED',
-			    merged_at = COALESCE(merged_at, now())
+			    merged_at = COALESCE(merged_at, now()),
+			    merge_override_by = COALESCE(merge_override_by, NULLIF($2, ''))
+		WHERE id = $1
+		RETURNING id, name, author_id, status, is_draft, created_at, merged_at, closed_at, COALESCE(merge_override_by, '');
+	`
+
+	return p.updatePR(ctx, q, prID, overrideBy)
+}
+
+// UpdateStatusClosed закрывает PR без merge; повторное закрытие не меняет closed_at
+func (p *prRepository) UpdateStatusClosed(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		UPDATE pull_requests
+			SET status = 'CLOSED',
+			    closed_at = COALESCE(closed_at, now())
 		WHERE id = $1
-		RETURNING id, name, author_id, status, created_at, merged_at;
+		RETURNING id, name, author_id, status, is_draft, created_at, merged_at, closed_at, COALESCE(merge_override_by, '');
 	`
 
+	return p.updatePR(ctx, q, prID)
+}
+
+// UpdateStatusOpen переоткрывает закрытый PR
+func (p *prRepository) UpdateStatusOpen(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		UPDATE pull_requests
+			SET status = 'OPEN',
+			    closed_at = NULL
+		WHERE id = $1
+		RETURNING id, name, author_id, status, is_draft, created_at, merged_at, closed_at, COALESCE(merge_override_by, '');
+	`
+
+	return p.updatePR(ctx, q, prID)
+}
+
+// UpdateReady снимает с PR признак черновика
+func (p *prRepository) UpdateReady(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		UPDATE pull_requests
+			SET is_draft = FALSE
+		WHERE id = $1
+		RETURNING id, name, author_id, status, is_draft, created_at, merged_at, closed_at, COALESCE(merge_override_by, '');
+	`
+
+	return p.updatePR(ctx, q, prID)
+}
+
+func (p *prRepository) updatePR(ctx context.Context, q string, args ...any) (*domain.PullRequest, error) {
 	var pr domain.PullRequest
-	err := p.q.QueryRow(ctx, q, prID).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
+	err := p.q.QueryRow(ctx, q, args...).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeOverrideBy)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
@@ -87,10 +220,11 @@ func (p *prRepository) UpdateStatusMerged(ctx context.Context, prID string) (*do
 
 func (p *prRepository) ListReviewableByUserID(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
 	const q = `
-		SELECT p.id, p.name, p.author_id, p.status, p.created_at, p.merged_at
+		SELECT p.id, p.name, p.author_id, p.status, p.is_draft, p.created_at, p.merged_at, p.closed_at, COALESCE(p.merge_override_by, '')
 		FROM pr_reviewers r
 		JOIN pull_requests p ON p.id = r.pr_id
-		WHERE r.user_id = $1;
+		WHERE r.user_id = $1
+			AND p.status <> 'CLOSED';
 	`
 
 	rows, err := p.q.Query(ctx, q, userID)
@@ -101,7 +235,39 @@ func (p *prRepository) ListReviewableByUserID(ctx context.Context, userID string
 
 	prs, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.PullRequest, error) {
 		var pr domain.PullRequest
-		if err := r.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
+		if err := r.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeOverrideBy); err != nil {
+			return nil, err
+		}
+		return &pr, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// ListReviewableByUserIDAndState возвращает незакрытые PR, где решение пользователя находится в состоянии state
+func (p *prRepository) ListReviewableByUserIDAndState(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error) {
+	const q = `
+		SELECT p.id, p.name, p.author_id, p.status, p.is_draft, p.created_at, p.merged_at, p.closed_at, COALESCE(p.merge_override_by, '')
+		FROM pr_reviewers r
+		JOIN pull_requests p ON p.id = r.pr_id
+		WHERE r.user_id = $1
+			AND r.state = $2
+			AND p.status = 'OPEN'
+		ORDER BY p.created_at, p.id;
+	`
+
+	rows, err := p.q.Query(ctx, q, userID, state)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.PullRequest, error) {
+		var pr domain.PullRequest
+		if err := r.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeOverrideBy); err != nil {
 			return nil, err
 		}
 		return &pr, nil
@@ -140,6 +306,53 @@ func (p *prRepository) ListReviewers(ctx context.Context, prID string) ([]string
 	return revIDs, nil
 }
 
+func (p *prRepository) ListReviewerStates(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+	const q = `
+		SELECT user_id, state, state_updated_at
+		FROM pr_reviewers
+		WHERE pr_id = $1
+		ORDER BY state_updated_at, user_id;
+	`
+
+	rows, err := p.q.Query(ctx, q, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.ReviewerState, error) {
+		var st domain.ReviewerState
+		if err := r.Scan(&st.UserID, &st.State, &st.UpdatedAt); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+func (p *prRepository) UpdateReviewState(ctx context.Context, prID, userID string, state domain.ReviewState) error {
+	const q = `
+		UPDATE pr_reviewers
+			SET state = $3,
+			    state_updated_at = now()
+		WHERE pr_id = $1 AND user_id = $2;
+	`
+
+	tag, err := p.q.Exec(ctx, q, prID, userID, state)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotAssigned
+	}
+
+	return nil
+}
+
 func (p *prRepository) InsertReviewer(ctx context.Context, prID, userID string) error {
 	const q = `
 		INSERT INTO pr_reviewers (pr_id, user_id)
@@ -147,17 +360,35 @@ func (p *prRepository) InsertReviewer(ctx context.Context, prID, userID string)
 	`
 
 	_, err := p.q.Exec(ctx, q, prID, userID)
-	return err
+	return conflictError(err)
 }
 
 func (p *prRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
 	const q = `
-        UPDATE pr_reviewers
-        SET user_id = $1
-        WHERE pr_id = $2 AND user_id = $3
+        WITH replaced AS (
+            UPDATE pr_reviewers
+            SET user_id = $1,
+                state = 'PENDING',
+                state_updated_at = NULL
+            WHERE pr_id = $2 AND user_id = $3
+            RETURNING pr_id
+        )
+        UPDATE pull_requests
+        SET reassign_count = reassign_count + 1
+        WHERE id IN (SELECT pr_id FROM replaced)
     `
 
 	_, err := p.q.Exec(ctx, q, newReviewerID, prID, oldReviewerID)
+	return conflictError(err)
+}
+
+func (p *prRepository) RemoveReviewer(ctx context.Context, prID, userID string) error {
+	const q = `
+		DELETE FROM pr_reviewers
+		WHERE pr_id = $1 AND user_id = $2;
+	`
+
+	_, err := p.q.Exec(ctx, q, prID, userID)
 	return err
 }
 
@@ -179,3 +410,298 @@ func (p *prRepository) ReviewerAssigned(ctx context.Context, prID, userID string
 
 	return exists, nil
 }
+
+func (p *prRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const q = `
+		SELECT r.user_id, COUNT(*)
+		FROM pr_reviewers r
+		JOIN pull_requests p ON p.id = r.pr_id
+		WHERE r.user_id = ANY($1)
+			AND p.status = 'OPEN'
+		GROUP BY r.user_id;
+	`
+
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := p.q.Query(ctx, q, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			count  int
+		)
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ReassignOpenReviews одним запросом заменяет перечисленных пользователей на всех OPEN PR.
+// Кандидаты — активные и не отсутствующие сейчас участники той же неархивной команды, кроме автора, уже назначенных
+// ревьюверов и самих заменяемых, у которых не исчерпан лимит открытых ревью. Участники команды один раз упорядочиваются
+// по стратегии (least_loaded — по нагрузке, round_robin — по user_id после курсора, random — случайно), и замены
+// раздаются по кругу в этом порядке; для weighted каждая замена выбирается случайно пропорционально весу.
+// Кандидат, выпавший на несколько PR, не получает замен сверх лимита. Если кандидатов не хватило, назначение остается
+// прежним, а в результате NewReviewerID пуст. Каждая замена записывается в review_assignment_events в том же запросе.
+func (p *prRepository) ReassignOpenReviews(ctx context.Context, req domain.BulkReassign) ([]*domain.ReviewReassignment, error) {
+	const q = `
+		WITH affected AS (
+			SELECT r.pr_id,
+			       r.user_id AS old_user_id,
+			       u.team_name,
+			       p.author_id,
+			       row_number() OVER (PARTITION BY u.team_name ORDER BY r.pr_id, r.user_id) AS seq,
+			       row_number() OVER (PARTITION BY r.pr_id, u.team_name ORDER BY r.user_id) AS slot
+			FROM pr_reviewers r
+			JOIN pull_requests p ON p.id = r.pr_id
+			JOIN users u ON u.id = r.user_id
+			WHERE r.user_id = ANY($1)
+			  AND p.status = 'OPEN'
+		),
+		load AS (
+			SELECT r.user_id, COUNT(*) AS open_reviews
+			FROM pr_reviewers r
+			JOIN pull_requests p ON p.id = r.pr_id
+			WHERE p.status = 'OPEN'
+			GROUP BY r.user_id
+		),
+		pool AS (
+			SELECT c.team_name,
+			       c.id AS user_id,
+			       COALESCE(l.open_reviews, 0) AS open_reviews,
+			       COALESCE(c.max_open_reviews, cs.default_max_open_reviews) AS capacity,
+			       GREATEST(COALESCE(w.value::int, 1), 1) AS weight,
+			       row_number() OVER (
+			           PARTITION BY c.team_name
+			           ORDER BY CASE WHEN $2::text = 'least_loaded' THEN COALESCE(l.open_reviews, 0) ELSE 0 END,
+			                    (CASE WHEN $2::text = 'round_robin' THEN c.id ELSE '' END) COLLATE "C",
+			                    random()
+			       ) - 1 AS pos,
+			       COUNT(*) OVER (PARTITION BY c.team_name) AS size,
+			       CASE WHEN $2::text = 'round_robin'
+			            THEN SUM(CASE WHEN c.id COLLATE "C" <= $4::text THEN 1 ELSE 0 END) OVER (PARTITION BY c.team_name)
+			            ELSE 0
+			       END AS start
+			FROM users c
+			JOIN teams ct ON ct.name = c.team_name
+			             AND ct.archived_at IS NULL
+			LEFT JOIN team_settings cs ON cs.team_name = c.team_name
+			LEFT JOIN load l ON l.user_id = c.id
+			LEFT JOIN jsonb_each_text($3::jsonb) w ON w.key = c.id
+			WHERE c.team_name IN (SELECT team_name FROM affected)
+			  AND c.is_active
+			  AND c.id <> ALL($1)
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM user_absences ab
+				WHERE ab.user_id = c.id
+				  AND ab.starts_at <= now()
+				  AND ab.ends_at > now()
+			)
+			  AND (COALESCE(c.max_open_reviews, cs.default_max_open_reviews) IS NULL
+			       OR COALESCE(l.open_reviews, 0) < COALESCE(c.max_open_reviews, cs.default_max_open_reviews))
+		),
+		candidates AS (
+			-- PR с base заменяемыми раньше него в команде начинает обход пула с позиции base
+			SELECT a.pr_id,
+			       a.team_name,
+			       c.user_id,
+			       c.open_reviews,
+			       c.capacity,
+			       row_number() OVER (
+			           PARTITION BY a.pr_id, a.team_name
+			           ORDER BY CASE WHEN $2::text = 'weighted' THEN -ln(1 - random()) / c.weight
+			                         ELSE ((c.pos - c.start - a.base) % c.size + c.size) % c.size
+			                    END
+			       ) AS slot
+			FROM (
+				SELECT pr_id, team_name, author_id, MIN(seq) - 1 AS base
+				FROM affected
+				GROUP BY pr_id, team_name, author_id
+			) a
+			JOIN pool c ON c.team_name = a.team_name
+			           AND c.user_id <> a.author_id
+			WHERE NOT EXISTS (
+				SELECT 1
+				FROM pr_reviewers x
+				WHERE x.pr_id = a.pr_id
+				  AND x.user_id = c.user_id
+			)
+		),
+		proposed AS (
+			SELECT a.pr_id,
+			       a.old_user_id,
+			       c.user_id AS new_user_id,
+			       c.open_reviews,
+			       c.capacity,
+			       row_number() OVER (PARTITION BY c.user_id ORDER BY a.team_name, a.seq) AS taken
+			FROM affected a
+			LEFT JOIN candidates c ON c.pr_id = a.pr_id
+			                      AND c.team_name = a.team_name
+			                      AND c.slot = a.slot
+		),
+		matched AS (
+			SELECT pr_id,
+			       old_user_id,
+			       CASE WHEN capacity IS NULL OR open_reviews + taken <= capacity THEN new_user_id END AS new_user_id
+			FROM proposed
+		),
+		replaced AS (
+			UPDATE pr_reviewers r
+			SET user_id = m.new_user_id,
+			    state = 'PENDING',
+			    state_updated_at = NULL
+			FROM matched m
+			WHERE r.pr_id = m.pr_id
+			  AND r.user_id = m.old_user_id
+			  AND m.new_user_id IS NOT NULL
+			RETURNING r.pr_id
+		),
+		counted AS (
+			UPDATE pull_requests p
+			SET reassign_count = p.reassign_count + c.replaced
+			FROM (SELECT pr_id, COUNT(*) AS replaced FROM replaced GROUP BY pr_id) c
+			WHERE p.id = c.pr_id
+			RETURNING p.id
+		),
+		logged AS (
+			INSERT INTO review_assignment_events (pr_id, event_type, user_id, previous_user_id, actor, reason)
+			SELECT m.pr_id, 'REASSIGNED', m.new_user_id, m.old_user_id, $5, $6
+			FROM matched m
+			WHERE m.new_user_id IS NOT NULL
+			RETURNING id
+		)
+		SELECT pr_id, old_user_id, COALESCE(new_user_id, '')
+		FROM matched
+		ORDER BY pr_id, old_user_id;
+	`
+
+	if len(req.UserIDs) == 0 {
+		return nil, nil
+	}
+
+	weights, err := weightsJSON(req.Weights)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.q.Query(ctx, q, req.UserIDs, string(req.Strategy), weights, req.Cursor, req.Actor, req.Reason)
+	if err != nil {
+		return nil, conflictError(err)
+	}
+	defer rows.Close()
+
+	res, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.ReviewReassignment, error) {
+		var ra domain.ReviewReassignment
+		if err := r.Scan(&ra.PRID, &ra.OldReviewerID, &ra.NewReviewerID); err != nil {
+			return nil, err
+		}
+		return &ra, nil
+	})
+	if err != nil {
+		return nil, conflictError(err)
+	}
+
+	return res, nil
+}
+
+// weightsJSON кодирует веса кандидатов в JSON-объект; отсутствие весов — пустой объект
+func weightsJSON(weights map[string]int) (string, error) {
+	if weights == nil {
+		weights = map[string]int{}
+	}
+	raw, err := json.Marshal(weights)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// AddAssignmentEvents добавляет записи в журнал назначений одним запросом
+func (p *prRepository) AddAssignmentEvents(ctx context.Context, events ...*domain.AssignmentEvent) error {
+	const q = `
+		INSERT INTO review_assignment_events (pr_id, event_type, user_id, previous_user_id, actor, reason)
+		SELECT e.pr_id, e.event_type, NULLIF(e.user_id, ''), NULLIF(e.previous_user_id, ''), e.actor, e.reason
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[])
+		     AS e(pr_id, event_type, user_id, previous_user_id, actor, reason);
+	`
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	var (
+		prIDs       = make([]string, 0, len(events))
+		types       = make([]string, 0, len(events))
+		userIDs     = make([]string, 0, len(events))
+		previousIDs = make([]string, 0, len(events))
+		actors      = make([]string, 0, len(events))
+		reasons     = make([]string, 0, len(events))
+	)
+	for _, e := range events {
+		prIDs = append(prIDs, e.PRID)
+		types = append(types, string(e.Type))
+		userIDs = append(userIDs, e.UserID)
+		previousIDs = append(previousIDs, e.PreviousUserID)
+		actors = append(actors, e.Actor)
+		reasons = append(reasons, e.Reason)
+	}
+
+	_, err := p.q.Exec(ctx, q, prIDs, types, userIDs, previousIDs, actors, reasons)
+	return err
+}
+
+func (p *prRepository) ListAssignmentEvents(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+	const q = `
+		SELECT id, pr_id, event_type, COALESCE(user_id, ''), COALESCE(previous_user_id, ''), actor, reason, created_at
+		FROM review_assignment_events
+		WHERE pr_id = $1
+		ORDER BY id;
+	`
+
+	rows, err := p.q.Query(ctx, q, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.AssignmentEvent, error) {
+		var e domain.AssignmentEvent
+		if err := r.Scan(&e.ID, &e.PRID, &e.Type, &e.UserID, &e.PreviousUserID, &e.Actor, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// conflictError переводит нарушение уникальности назначения и ошибки сериализации, возникающие при гонке
+// параллельных транзакций, в domain.ErrConcurrentUpdate. Исходная ошибка остается в цепочке, чтобы TxManager
+// мог повторить транзакцию.
+func conflictError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505", "40001", "40P01":
+			return fmt.Errorf("%w: %w", domain.ErrConcurrentUpdate, err)
+		}
+	}
+	return err
+}
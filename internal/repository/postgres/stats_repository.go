@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type statsRepository struct {
+	q Querier
+}
+
+func NewStatsRepository(q Querier) domain.StatsRepository {
+	return &statsRepository{q: q}
+}
+
+func (s *statsRepository) UserAssignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error) {
+	const q = `
+		SELECT u.id,
+		       u.name,
+		       COALESCE(u.team_name, ''),
+		       u.is_active,
+		       COUNT(p.id) FILTER (
+		           WHERE p.status <> 'CLOSED'
+		             AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+		             AND ($3::timestamptz IS NULL OR p.created_at < $3)
+		       ) AS total,
+		       COUNT(p.id) FILTER (
+		           WHERE p.status = 'OPEN'
+		             AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+		             AND ($3::timestamptz IS NULL OR p.created_at < $3)
+		       ) AS open,
+		       COUNT(p.id) FILTER (
+		           WHERE p.status = 'MERGED'
+		             AND ($2::timestamptz IS NULL OR p.merged_at >= $2)
+		             AND ($3::timestamptz IS NULL OR p.merged_at < $3)
+		       ) AS merged
+		FROM users u
+		LEFT JOIN pr_reviewers r ON r.user_id = u.id
+		LEFT JOIN pull_requests p ON p.id = r.pr_id
+		WHERE ($1::text = '' OR u.team_name = $1)
+		GROUP BY u.id, u.name, u.team_name, u.is_active
+		ORDER BY open DESC, total DESC, u.id;
+	`
+
+	rows, err := s.q.Query(ctx, q, filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.UserAssignmentStats, error) {
+		var st domain.UserAssignmentStats
+		if err := r.Scan(&st.UserID, &st.Username, &st.TeamName, &st.IsActive, &st.Total, &st.Open, &st.Merged); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *statsRepository) PRReassignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error) {
+	const q = `
+		SELECT p.id,
+		       p.name,
+		       p.author_id,
+		       p.status,
+		       COUNT(r.user_id) AS reviewers,
+		       p.reassign_count
+		FROM pull_requests p
+		LEFT JOIN users a ON a.id = p.author_id
+		LEFT JOIN pr_reviewers r ON r.pr_id = p.id
+		WHERE ($1::text = '' OR a.team_name = $1)
+		  AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR p.created_at < $3)
+		GROUP BY p.id, p.name, p.author_id, p.status, p.reassign_count
+		ORDER BY p.reassign_count DESC, p.id;
+	`
+
+	rows, err := s.q.Query(ctx, q, filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.PRReassignmentStats, error) {
+		var st domain.PRReassignmentStats
+		if err := r.Scan(&st.PRID, &st.Name, &st.AuthorID, &st.Status, &st.Reviewers, &st.Reassignments); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *statsRepository) TeamTotals(ctx context.Context, filter domain.StatsFilter) ([]*domain.TeamStats, error) {
+	const q = `
+		WITH members AS (
+			SELECT team_name,
+			       COUNT(*) AS members,
+			       COUNT(*) FILTER (WHERE is_active) AS active_members
+			FROM users
+			GROUP BY team_name
+		),
+		authored AS (
+			SELECT a.team_name,
+			       COUNT(*) FILTER (
+			           WHERE p.status = 'OPEN'
+			             AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+			             AND ($3::timestamptz IS NULL OR p.created_at < $3)
+			       ) AS open_prs,
+			       COUNT(*) FILTER (
+			           WHERE p.status = 'MERGED'
+			             AND ($2::timestamptz IS NULL OR p.merged_at >= $2)
+			             AND ($3::timestamptz IS NULL OR p.merged_at < $3)
+			       ) AS merged_prs,
+			       COALESCE(SUM(p.reassign_count) FILTER (
+			           WHERE ($2::timestamptz IS NULL OR p.created_at >= $2)
+			             AND ($3::timestamptz IS NULL OR p.created_at < $3)
+			       ), 0) AS reassignments
+			FROM pull_requests p
+			JOIN users a ON a.id = p.author_id
+			GROUP BY a.team_name
+		),
+		assigned AS (
+			SELECT u.team_name, COUNT(*) AS assignments
+			FROM pr_reviewers r
+			JOIN users u ON u.id = r.user_id
+			JOIN pull_requests p ON p.id = r.pr_id
+			WHERE p.status <> 'CLOSED'
+			  AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+			  AND ($3::timestamptz IS NULL OR p.created_at < $3)
+			GROUP BY u.team_name
+		)
+		SELECT t.name,
+		       COALESCE(m.members, 0),
+		       COALESCE(m.active_members, 0),
+		       COALESCE(a.open_prs, 0),
+		       COALESCE(a.merged_prs, 0),
+		       COALESCE(s.assignments, 0),
+		       COALESCE(a.reassignments, 0)
+		FROM teams t
+		LEFT JOIN members m ON m.team_name = t.name
+		LEFT JOIN authored a ON a.team_name = t.name
+		LEFT JOIN assigned s ON s.team_name = t.name
+		WHERE ($1::text = '' OR t.name = $1)
+		ORDER BY t.name;
+	`
+
+	rows, err := s.q.Query(ctx, q, filter.TeamName, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.TeamStats, error) {
+		var st domain.TeamStats
+		if err := r.Scan(
+			&st.TeamName,
+			&st.Members,
+			&st.ActiveMembers,
+			&st.OpenPRs,
+			&st.MergedPRs,
+			&st.Assignments,
+			&st.Reassignments,
+		); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -3,8 +3,17 @@ package postgres
 import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+var teamSortColumns = map[string]sortColumn{
+	domain.SortByName: {expr: "name", cast: "text", unique: true},
+}
+
 type teamRepository struct {
 	q Querier
 }
@@ -21,6 +30,10 @@ func (tr *teamRepository) Create(ctx context.Context, teamName string) error {
 
 	_, err := tr.q.Exec(ctx, q, teamName)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrAlreadyExists
+		}
 		return err
 	}
 
@@ -44,3 +57,222 @@ func (tr *teamRepository) Exists(ctx context.Context, teamName string) (bool, er
 
 	return exists, nil
 }
+
+func (tr *teamRepository) FetchByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	const q = `
+		SELECT name, archived_at
+		FROM teams
+		WHERE name = $1;
+	`
+
+	var team domain.Team
+	err := tr.q.QueryRow(ctx, q, teamName).Scan(&team.Name, &team.ArchivedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+func (tr *teamRepository) Rename(ctx context.Context, teamName, newName string) error {
+	const q = `
+		UPDATE teams
+		SET name = $2
+		WHERE name = $1;
+	`
+
+	tag, err := tr.q.Exec(ctx, q, teamName, newName)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrAlreadyExists
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetArchived архивирует команду или возвращает ее из архива; повторный вызов сохраняет исходное archived_at
+func (tr *teamRepository) SetArchived(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+	const q = `
+		UPDATE teams
+		SET archived_at = CASE WHEN $2 THEN COALESCE(archived_at, now()) END
+		WHERE name = $1
+		RETURNING name, archived_at;
+	`
+
+	var team domain.Team
+	err := tr.q.QueryRow(ctx, q, teamName, archived).Scan(&team.Name, &team.ArchivedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// Delete удаляет команду вместе с ее курсором round_robin. Участники к этому моменту должны быть отвязаны.
+func (tr *teamRepository) Delete(ctx context.Context, teamName string) error {
+	const q = `
+		DELETE FROM teams
+		WHERE name = $1;
+	`
+
+	tag, err := tr.q.Exec(ctx, q, teamName)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr *teamRepository) List(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error) {
+	col, err := lookupSort(teamSortColumns, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decodeCursor(filter.Cursor, filter.Sort, filter.Order, col)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, orderBy, keyArgs := keyset(col, "name", filter.Order, cur, 3)
+	q := fmt.Sprintf(`
+		SELECT name, archived_at
+		FROM teams
+		WHERE ($1::boolean IS NULL OR (archived_at IS NOT NULL) = $1)
+		  AND %s
+		ORDER BY %s
+		LIMIT $2;
+	`, cond, orderBy)
+
+	args := append([]any{filter.Archived, filter.Limit + 1}, keyArgs...)
+	rows, err := tr.q.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.Team, error) {
+		var team domain.Team
+		if err := r.Scan(&team.Name, &team.ArchivedAt); err != nil {
+			return nil, err
+		}
+		return &team, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(teams, filter.Limit, filter.Sort, filter.Order, func(t *domain.Team) (string, string) {
+		return t.Name, t.Name
+	}), nil
+}
+
+func (tr *teamRepository) FetchSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	const q = `
+		SELECT COALESCE(s.min_reviewers, $2),
+		       COALESCE(s.max_reviewers, $3),
+		       ARRAY(SELECT f.fallback_team_name FROM team_fallback_teams f WHERE f.team_name = t.name ORDER BY f.position),
+		       s.default_max_open_reviews
+		FROM teams t
+		LEFT JOIN team_settings s ON s.team_name = t.name
+		WHERE t.name = $1;
+	`
+
+	settings := domain.TeamSettings{TeamName: teamName}
+	err := tr.q.QueryRow(ctx, q, teamName, domain.DefaultMinReviewers, domain.DefaultMaxReviewers).
+		Scan(&settings.MinReviewers, &settings.MaxReviewers, &settings.FallbackTeams, &settings.DefaultMaxOpenReviews)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// UpsertSettings сохраняет настройки команды; список резервных команд заменяется целиком с сохранением порядка
+func (tr *teamRepository) UpsertSettings(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+	const upsertQ = `
+		INSERT INTO team_settings (team_name, min_reviewers, max_reviewers, default_max_open_reviews)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_name) DO UPDATE
+		SET min_reviewers            = EXCLUDED.min_reviewers,
+		    max_reviewers            = EXCLUDED.max_reviewers,
+		    default_max_open_reviews = EXCLUDED.default_max_open_reviews;
+	`
+	const deleteFallbacksQ = `
+		DELETE FROM team_fallback_teams
+		WHERE team_name = $1;
+	`
+	const insertFallbacksQ = `
+		INSERT INTO team_fallback_teams (team_name, fallback_team_name, position)
+		SELECT $1, f.name, f.position
+		FROM unnest($2::text[]) WITH ORDINALITY AS f(name, position);
+	`
+
+	if _, err := tr.q.Exec(ctx, upsertQ, settings.TeamName, settings.MinReviewers, settings.MaxReviewers, settings.DefaultMaxOpenReviews); err != nil {
+		return nil, err
+	}
+	if _, err := tr.q.Exec(ctx, deleteFallbacksQ, settings.TeamName); err != nil {
+		return nil, err
+	}
+	if len(settings.FallbackTeams) > 0 {
+		if _, err := tr.q.Exec(ctx, insertFallbacksQ, settings.TeamName, settings.FallbackTeams); err != nil {
+			return nil, err
+		}
+	}
+
+	return tr.FetchSettings(ctx, settings.TeamName)
+}
+
+// LockReviewCursor возвращает последнего назначенного по кругу ревьювера команды и блокирует курсор до конца транзакции
+func (tr *teamRepository) LockReviewCursor(ctx context.Context, teamName string) (string, error) {
+	const insertQ = `
+		INSERT INTO team_review_cursors (team_name)
+		VALUES ($1)
+		ON CONFLICT (team_name) DO NOTHING;
+	`
+	const selectQ = `
+		SELECT last_user_id
+		FROM team_review_cursors
+		WHERE team_name = $1
+		FOR UPDATE;
+	`
+
+	if _, err := tr.q.Exec(ctx, insertQ, teamName); err != nil {
+		return "", err
+	}
+
+	var lastUserID string
+	if err := tr.q.QueryRow(ctx, selectQ, teamName).Scan(&lastUserID); err != nil {
+		return "", err
+	}
+
+	return lastUserID, nil
+}
+
+func (tr *teamRepository) UpdateReviewCursor(ctx context.Context, teamName, lastUserID string) error {
+	const q = `
+		UPDATE team_review_cursors
+		SET last_user_id = $2
+		WHERE team_name = $1;
+	`
+
+	_, err := tr.q.Exec(ctx, q, teamName, lastUserID)
+	return err
+}
@@ -3,34 +3,143 @@ package postgres
 import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	defaultTxMaxAttempts = 5
+	defaultTxRetryDelay  = 10 * time.Millisecond
+)
+
+var isoLevels = map[domain.IsolationLevel]pgx.TxIsoLevel{
+	domain.ReadCommitted:  pgx.ReadCommitted,
+	domain.RepeatableRead: pgx.RepeatableRead,
+	domain.Serializable:   pgx.Serializable,
+}
+
+// txKey — ключ контекста, под которым лежит открытая WithinTx транзакция
+type txKey struct{}
+
 type txManager struct {
 	pool *pgxpool.Pool
+	// maxAttempts — сколько раз всего выполнить транзакцию при ошибках сериализации и deadlock
+	maxAttempts int
+	// retryDelay — базовая пауза перед повтором, удваивается с каждой попыткой
+	retryDelay time.Duration
 }
 
 func NewTxManager(pool *pgxpool.Pool) domain.TxManager {
-	return &txManager{pool: pool}
+	return &txManager{
+		pool:        pool,
+		maxAttempts: defaultTxMaxAttempts,
+		retryDelay:  defaultTxRetryDelay,
+	}
+}
+
+// WithinTx повторяет транзакцию с экспоненциальной паузой и случайным разбросом, пока ошибка повторяема
+// (40001, 40P01), попытки не исчерпаны и контекст не отменен. Если повторы не помогли, ошибка последней попытки
+// возвращается обернутой в domain.ErrConcurrentUpdate.
+// Вызов из колбэка другого WithinTx не открывает новую транзакцию, а выполняется в ней же через savepoint.
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context, repos *domain.Repos) error, opts ...domain.TxOption) error {
+	if outer, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return withinSavepoint(ctx, outer, fn)
+	}
+
+	o := domain.ApplyTxOptions(opts...)
+
+	var err error
+	for attempt := 0; attempt < m.maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := m.retryDelay << (attempt - 1)
+			delay += time.Duration(rand.Int63n(int64(delay) + 1))
+
+			select {
+			case <-ctx.Done():
+				return exhausted(err)
+			case <-time.After(delay):
+			}
+		}
+
+		err = m.runTx(ctx, o, fn)
+		if err == nil || !retryable(err) {
+			return err
+		}
+	}
+
+	return exhausted(err)
 }
 
-func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context, repos *domain.Repos) error) error {
-	tx, err := m.pool.Begin(ctx)
+func (m *txManager) runTx(ctx context.Context, o domain.TxOptions, fn func(ctx context.Context, repos *domain.Repos) error) error {
+	txOpts := pgx.TxOptions{IsoLevel: isoLevels[o.Isolation]}
+	if o.ReadOnly {
+		txOpts.AccessMode = pgx.ReadOnly
+	}
+
+	tx, err := m.pool.BeginTx(ctx, txOpts)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	repos := &domain.Repos{
-		PR:   NewPRRepository(tx),
-		User: NewUserRepository(tx),
-		Team: NewTeamRepository(tx),
+	if o.StatementTimeout > 0 {
+		timeout := fmt.Sprintf("%dms", max(1, o.StatementTimeout.Milliseconds()))
+		if _, err := tx.Exec(ctx, `SELECT set_config('statement_timeout', $1, true)`, timeout); err != nil {
+			return err
+		}
 	}
 
-	if err := fn(ctx, repos); err != nil {
+	if err := fn(context.WithValue(ctx, txKey{}, tx), newRepos(tx)); err != nil {
 		return err
 	}
 
 	return tx.Commit(ctx)
 }
+
+// withinSavepoint выполняет fn во вложенной транзакции outer (SAVEPOINT). Ошибка fn откатывает только ее изменения,
+// судьбу внешней транзакции решает вызывающий. Опции и повторы не применяются: изоляцию задает внешняя транзакция,
+// а после ошибки сериализации повторять нужно ее целиком, поэтому ошибка возвращается как есть.
+func withinSavepoint(ctx context.Context, outer pgx.Tx, fn func(ctx context.Context, repos *domain.Repos) error) error {
+	sp, err := outer.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer sp.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, sp), newRepos(sp)); err != nil {
+		return err
+	}
+
+	return sp.Commit(ctx)
+}
+
+func newRepos(q Querier) *domain.Repos {
+	return &domain.Repos{
+		PR:   NewPRRepository(q),
+		User: NewUserRepository(q),
+		Team: NewTeamRepository(q),
+	}
+}
+
+// retryable сообщает, что транзакцию можно безопасно повторить целиком: ошибка сериализации или deadlock
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// exhausted помечает повторяемую ошибку, которую не удалось преодолеть повторами, как конфликт параллельных изменений
+func exhausted(err error) error {
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", domain.ErrConcurrentUpdate, err)
+}
@@ -4,40 +4,57 @@ import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
 	"testing"
+	"time"
 
 	"avito-backend-trainee-autumn-2025/testutils"
 
-	"github.com/stretchr/testify/assert"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/require"
 )
 
-func TestTxManager_SuccessCommit(t *testing.T) {
+func TestTxManager_RetriesSerializationFailure(t *testing.T) {
 	ctx := context.Background()
 
 	require.NoError(t, testutils.PrepareTestTablesWithFixtures(ctx, testPool))
 
-	m := NewTxManager(testPool)
+	m := &txManager{pool: testPool, maxAttempts: 3, retryDelay: time.Millisecond}
 
+	calls := 0
 	err := m.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
-
-		err := repos.Team.Create(ctx, "tx_team")
-		require.NoError(t, err)
-
+		calls++
+		if err := repos.Team.Create(ctx, "retry_team"); err != nil {
+			return err
+		}
+		if calls < 3 {
+			return &pgconn.PgError{Code: "40001"}
+		}
 		return nil
-	})
+	}, domain.WithIsolation(domain.Serializable))
 
 	require.NoError(t, err)
+	require.Equal(t, 3, calls)
 
-	var exists bool
-	err = testPool.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM teams WHERE name = 'tx_team')`,
-	).Scan(&exists)
+	var count int
+	require.NoError(t, testPool.QueryRow(ctx, `SELECT COUNT(*) FROM teams WHERE name = 'retry_team'`).Scan(&count))
+	require.Equal(t, 1, count, "failed attempts must be rolled back")
+}
 
-	require.NoError(t, err)
-	require.True(t, exists)
+func TestTxManager_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+
+	m := &txManager{pool: testPool, maxAttempts: 2, retryDelay: time.Millisecond}
+
+	calls := 0
+	err := m.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		calls++
+		return &pgconn.PgError{Code: "40P01"}
+	})
+
+	require.ErrorIs(t, err, domain.ErrConcurrentUpdate)
+	require.Equal(t, 2, calls)
 }
 
-func TestTxManager_RollbackOnError(t *testing.T) {
+func TestTxManager_ReadOnly(t *testing.T) {
 	ctx := context.Background()
 
 	require.NoError(t, testutils.PrepareTestTablesWithFixtures(ctx, testPool))
@@ -45,19 +62,10 @@ func TestTxManager_RollbackOnError(t *testing.T) {
 	m := NewTxManager(testPool)
 
 	err := m.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
-		err := repos.Team.Create(ctx, "rollback_team")
-		require.NoError(t, err)
-
-		return assert.AnError
-	})
-
-	require.Error(t, err)
+		return repos.Team.Create(ctx, "readonly_team")
+	}, domain.ReadOnly(), domain.WithStatementTimeout(time.Second))
 
-	var exists bool
-	err = testPool.QueryRow(ctx,
-		`SELECT EXISTS(SELECT 1 FROM teams WHERE name = 'rollback_team')`,
-	).Scan(&exists)
-
-	require.NoError(t, err)
-	require.False(t, exists)
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "25006", pgErr.Code)
 }
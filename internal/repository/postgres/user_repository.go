@@ -4,10 +4,18 @@ import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+var userSortColumns = map[string]sortColumn{
+	domain.SortByID:   {expr: "id", cast: "text", unique: true},
+	domain.SortByName: {expr: "name", cast: "text"},
+}
+
 type userRepository struct {
 	q Querier
 }
@@ -32,13 +40,13 @@ func (ur *userRepository) Upsert(ctx context.Context, user *domain.User) error {
 
 func (ur *userRepository) FetchByID(ctx context.Context, userID string) (*domain.User, error) {
 	const q = `
-		SELECT id, name, team_name, is_active
+		SELECT id, name, COALESCE(team_name, ''), is_active, max_open_reviews
 		FROM users
 		WHERE id = $1
 	`
 
 	var user domain.User
-	err := ur.q.QueryRow(ctx, q, userID).Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive)
+	err := ur.q.QueryRow(ctx, q, userID).Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.MaxOpenReviews)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
@@ -49,9 +57,39 @@ func (ur *userRepository) FetchByID(ctx context.Context, userID string) (*domain
 	return &user, nil
 }
 
+// FetchByIDs возвращает найденных пользователей из userIDs; отсутствующие id пропускаются
+func (ur *userRepository) FetchByIDs(ctx context.Context, userIDs []string) ([]*domain.User, error) {
+	const q = `
+		SELECT id, name, COALESCE(team_name, ''), is_active, max_open_reviews
+		FROM users
+		WHERE id = ANY($1)
+		ORDER BY id
+	`
+
+	rows, err := ur.q.Query(ctx, q, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.User, error) {
+		var user domain.User
+		if err := r.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.MaxOpenReviews); err != nil {
+			return nil, err
+		}
+
+		return &user, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (ur *userRepository) FetchByTeam(ctx context.Context, teamName string) ([]*domain.User, error) {
 	const q = `
-		SELECT id, name, team_name, is_active
+		SELECT id, name, COALESCE(team_name, ''), is_active, max_open_reviews
 		FROM users
 		WHERE team_name = $1
 	`
@@ -64,7 +102,7 @@ func (ur *userRepository) FetchByTeam(ctx context.Context, teamName string) ([]*
 
 	users, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.User, error) {
 		var user domain.User
-		if err := r.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive); err != nil {
+		if err := r.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.MaxOpenReviews); err != nil {
 			return nil, err
 		}
 
@@ -77,6 +115,8 @@ func (ur *userRepository) FetchByTeam(ctx context.Context, teamName string) ([]*
 	return users, nil
 }
 
+// FetchActiveByTeam возвращает активных участников команды — кандидатов в ревьюверы, кроме отсутствующих в текущий момент.
+// Для архивной команды список пуст.
 func (ur *userRepository) FetchActiveByTeam(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
 	var (
 		rows pgx.Rows
@@ -85,19 +125,37 @@ func (ur *userRepository) FetchActiveByTeam(ctx context.Context, teamName string
 
 	if len(excludeIDs) == 0 {
 		const qNoExclude = `
-			SELECT id, name, team_name, is_active
-			FROM users
-			WHERE team_name = $1
-			  AND is_active = TRUE;
+			SELECT u.id, u.name, u.team_name, u.is_active, u.max_open_reviews
+			FROM users u
+			JOIN teams t ON t.name = u.team_name
+			WHERE u.team_name = $1
+			  AND u.is_active = TRUE
+			  AND t.archived_at IS NULL
+			  AND NOT EXISTS (
+			      SELECT 1
+			      FROM user_absences a
+			      WHERE a.user_id = u.id
+			        AND a.starts_at <= now()
+			        AND a.ends_at > now()
+			  );
 		`
 		rows, err = ur.q.Query(ctx, qNoExclude, teamName)
 	} else {
 		const q = `
-			SELECT id, name, team_name, is_active
-			FROM users
-			WHERE team_name = $1
-			  AND is_active = TRUE
-			  AND NOT (id = ANY($2));
+			SELECT u.id, u.name, u.team_name, u.is_active, u.max_open_reviews
+			FROM users u
+			JOIN teams t ON t.name = u.team_name
+			WHERE u.team_name = $1
+			  AND u.is_active = TRUE
+			  AND t.archived_at IS NULL
+			  AND NOT EXISTS (
+			      SELECT 1
+			      FROM user_absences a
+			      WHERE a.user_id = u.id
+			        AND a.starts_at <= now()
+			        AND a.ends_at > now()
+			  )
+			  AND NOT (u.id = ANY($2));
 		`
 		rows, err = ur.q.Query(ctx, q, teamName, excludeIDs)
 	}
@@ -109,7 +167,7 @@ func (ur *userRepository) FetchActiveByTeam(ctx context.Context, teamName string
 
 	users, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.User, error) {
 		var u domain.User
-		if err := r.Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive); err != nil {
+		if err := r.Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews); err != nil {
 			return nil, err
 		}
 		return &u, nil
@@ -144,7 +202,7 @@ func (ur *userRepository) UpdateIsActive(ctx context.Context, userID string, act
         UPDATE users
         SET is_active = $1
         WHERE id = $2
-        RETURNING id, name, team_name, is_active;
+        RETURNING id, name, COALESCE(team_name, ''), is_active, max_open_reviews;
     `
 
 	var user domain.User
@@ -153,6 +211,7 @@ func (ur *userRepository) UpdateIsActive(ctx context.Context, userID string, act
 		&user.Name,
 		&user.TeamName,
 		&user.IsActive,
+		&user.MaxOpenReviews,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -163,3 +222,343 @@ func (ur *userRepository) UpdateIsActive(ctx context.Context, userID string, act
 
 	return &user, nil
 }
+
+func (ur *userRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+	const q = `
+		UPDATE users
+		SET max_open_reviews = $2
+		WHERE id = $1
+		RETURNING id, name, COALESCE(team_name, ''), is_active, max_open_reviews;
+	`
+
+	var user domain.User
+	err := ur.q.QueryRow(ctx, q, userID, limit).Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.MaxOpenReviews)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (ur *userRepository) FetchReviewLimits(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const q = `
+		SELECT u.id, COALESCE(u.max_open_reviews, s.default_max_open_reviews)
+		FROM users u
+		LEFT JOIN team_settings s ON s.team_name = u.team_name
+		WHERE u.id = ANY($1)
+		  AND COALESCE(u.max_open_reviews, s.default_max_open_reviews) IS NOT NULL;
+	`
+
+	limits := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return limits, nil
+	}
+
+	rows, err := ur.q.Query(ctx, q, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			limit  int
+		)
+		if err := rows.Scan(&userID, &limit); err != nil {
+			return nil, err
+		}
+		limits[userID] = limit
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// DeactivateTeamMembers деактивирует перечисленных участников команды (или всех, кроме перечисленных, при allExcept)
+// и возвращает их id. Пользователи из других команд не затрагиваются.
+func (ur *userRepository) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error) {
+	var (
+		rows pgx.Rows
+		err  error
+	)
+
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+
+	if allExcept {
+		const qExcept = `
+			UPDATE users
+			SET is_active = FALSE
+			WHERE team_name = $1
+			  AND NOT (id = ANY($2))
+			RETURNING id;
+		`
+		rows, err = ur.q.Query(ctx, qExcept, teamName, userIDs)
+	} else {
+		const q = `
+			UPDATE users
+			SET is_active = FALSE
+			WHERE team_name = $1
+			  AND id = ANY($2)
+			RETURNING id;
+		`
+		rows, err = ur.q.Query(ctx, q, teamName, userIDs)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// RemoveFromTeam отвязывает перечисленных участников от команды (team_name = NULL) и возвращает их id.
+// Пользователи из других команд не затрагиваются.
+func (ur *userRepository) RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+	const q = `
+		UPDATE users
+		SET team_name = NULL
+		WHERE team_name = $1
+		  AND id = ANY($2)
+		RETURNING id;
+	`
+
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+
+	rows, err := ur.q.Query(ctx, q, teamName, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (ur *userRepository) List(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+	col, err := lookupSort(userSortColumns, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decodeCursor(filter.Cursor, filter.Sort, filter.Order, col)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, orderBy, keyArgs := keyset(col, "id", filter.Order, cur, 4)
+	q := fmt.Sprintf(`
+		SELECT id, name, COALESCE(team_name, ''), is_active, max_open_reviews
+		FROM users
+		WHERE ($1::text = '' OR team_name = $1)
+		  AND ($2::boolean IS NULL OR is_active = $2)
+		  AND %s
+		ORDER BY %s
+		LIMIT $3;
+	`, cond, orderBy)
+
+	args := append([]any{filter.TeamName, filter.IsActive, filter.Limit + 1}, keyArgs...)
+	rows, err := ur.q.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.User, error) {
+		var u domain.User
+		if err := r.Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews); err != nil {
+			return nil, err
+		}
+		return &u, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(users, filter.Limit, filter.Sort, filter.Order, func(u *domain.User) (string, string) {
+		if filter.Sort == domain.SortByName {
+			return u.Name, u.ID
+		}
+		return u.ID, u.ID
+	}), nil
+}
+
+const absenceColumns = `id, user_id, starts_at, ends_at, COALESCE(delegate_id, ''), handed_off_at, created_at`
+
+func scanAbsence(r pgx.Row) (*domain.Absence, error) {
+	var a domain.Absence
+	if err := r.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.DelegateID, &a.HandedOffAt, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// AddAbsence сохраняет период отсутствия; пересечение с другим периодом пользователя дает domain.ErrAbsenceOverlap
+func (ur *userRepository) AddAbsence(ctx context.Context, absence *domain.Absence) (*domain.Absence, error) {
+	q := `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, delegate_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING ` + absenceColumns + `;
+	`
+
+	created, err := scanAbsence(ur.q.QueryRow(ctx, q, absence.UserID, absence.StartsAt, absence.EndsAt, absence.DelegateID))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23P01" {
+			return nil, domain.ErrAbsenceOverlap
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (ur *userRepository) HasOverlappingAbsence(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error) {
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_absences
+			WHERE user_id = $1
+			  AND starts_at < $3
+			  AND ends_at > $2
+		);
+	`
+
+	var exists bool
+	if err := ur.q.QueryRow(ctx, q, userID, startsAt, endsAt).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// ListAbsences возвращает периоды отсутствия пользователя по времени начала
+func (ur *userRepository) ListAbsences(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	q := `
+		SELECT ` + absenceColumns + `
+		FROM user_absences
+		WHERE user_id = $1
+		ORDER BY starts_at, id;
+	`
+
+	rows, err := ur.q.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	absences, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.Absence, error) {
+		return scanAbsence(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+func (ur *userRepository) FetchCurrentAbsence(ctx context.Context, userID string) (*domain.Absence, error) {
+	q := `
+		SELECT ` + absenceColumns + `
+		FROM user_absences
+		WHERE user_id = $1
+		  AND starts_at <= now()
+		  AND ends_at > now();
+	`
+
+	absence, err := scanAbsence(ur.q.QueryRow(ctx, q, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+func (ur *userRepository) DeleteAbsence(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	q := `
+		DELETE FROM user_absences
+		WHERE id = $1
+		RETURNING ` + absenceColumns + `;
+	`
+
+	absence, err := scanAbsence(ur.q.QueryRow(ctx, q, absenceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+// ListDueHandoffs блокирует найденные строки (FOR UPDATE SKIP LOCKED), чтобы параллельные обработчики
+// не передали ревью дважды
+func (ur *userRepository) ListDueHandoffs(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	q := `
+		SELECT ` + absenceColumns + `
+		FROM user_absences
+		WHERE delegate_id IS NOT NULL
+		  AND handed_off_at IS NULL
+		  AND starts_at <= now()
+		  AND ends_at > now()
+		  AND ($1::text = '' OR user_id = $1)
+		ORDER BY starts_at, id
+		FOR UPDATE SKIP LOCKED;
+	`
+
+	rows, err := ur.q.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	absences, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (*domain.Absence, error) {
+		return scanAbsence(r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+func (ur *userRepository) MarkHandedOff(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	q := `
+		UPDATE user_absences
+		SET handed_off_at = now()
+		WHERE id = $1
+		RETURNING ` + absenceColumns + `;
+	`
+
+	absence, err := scanAbsence(ur.q.QueryRow(ctx, q, absenceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return absence, nil
+}
@@ -0,0 +1,50 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/internal/usecase"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testChainedAbsences проверяет, что ревью не передаются заместителю, у которого сейчас свой период отсутствия
+func testChainedAbsences(t *testing.T, backend Backend) {
+	ctx := domain.WithActor(context.Background(), "lead")
+	stores := backend(t)
+	addUser(t, stores, "u_extra", testutils.TestTeam)
+
+	now := time.Now()
+	_, err := stores.User.AddAbsence(ctx, &domain.Absence{
+		UserID:   testutils.User3ID,
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	userUC := usecase.NewUserUsecase(stores.User, stores.PR, stores.Tx, usecase.NewLeastLoadedSelector())
+	created, res, err := userUC.AddAbsence(ctx, &domain.Absence{
+		UserID:     testutils.User2ID,
+		StartsAt:   now.Add(-time.Minute),
+		EndsAt:     now.Add(24 * time.Hour),
+		DelegateID: testutils.User3ID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.HandedOffAt)
+	require.Equal(t, []*domain.ReviewReassignment{
+		{PRID: testutils.PR1ID, OldReviewerID: testutils.User2ID, NewReviewerID: "u_extra"},
+	}, res)
+
+	revs, err := stores.PR.ListReviewers(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u_extra", testutils.User3ID}, revs)
+
+	events, err := stores.PR.ListAssignmentEvents(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "u_extra", events[0].UserID)
+	require.Equal(t, domain.ReasonUserAbsent, events[0].Reason)
+}
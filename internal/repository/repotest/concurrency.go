@@ -0,0 +1,210 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/api/dto"
+	"avito-backend-trainee-autumn-2025/internal/api/handler"
+	"avito-backend-trainee-autumn-2025/internal/api/route"
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/internal/usecase"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// conflictErrors — ошибки, которыми операции над одним PR законно отвечают проигравшему в гонке
+var conflictErrors = []error{
+	domain.ErrConcurrentUpdate,
+	domain.ErrPRMerged,
+	domain.ErrNotAssigned,
+	domain.ErrNoCandidate,
+}
+
+// conflictCodes — коды ответа 409, которыми обработчики отвечают на conflictErrors
+var conflictCodes = []string{"CONCURRENT_UPDATE", "PR_MERGED", "NOT_ASSIGNED", "NO_CANDIDATE"}
+
+// newTestRouter собирает сервис целиком поверх хранилища stores
+func newTestRouter(stores *Stores) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	selector := usecase.NewLeastLoadedSelector()
+
+	router := gin.New()
+	route.Register(router,
+		&handler.PRHandler{PRUsecase: usecase.NewPRUsecase(stores.User, stores.PR, stores.Tx, selector, usecase.MergePolicy{})},
+		&handler.TeamHandler{TeamUsecase: usecase.NewTeamUsecase(stores.Team, stores.User, stores.Tx, selector)},
+		&handler.UserHandler{UserUsecase: usecase.NewUserUsecase(stores.User, stores.PR, stores.Tx, selector)},
+		&handler.StatsHandler{StatsUsecase: usecase.NewStatsUsecase(stores.Stats)},
+	)
+
+	return router
+}
+
+func testConcurrentReassignAndMerge(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	for i := 7; i <= 12; i++ {
+		addUser(t, stores, fmt.Sprintf("u%d", i), testutils.TestTeam)
+	}
+
+	prUsecase := usecase.NewPRUsecase(stores.User, stores.PR, stores.Tx, usecase.NewLeastLoadedSelector(), usecase.MergePolicy{})
+
+	const workers = 40
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		reassignOK  int
+		mergeOK     int
+		unexpected  []string
+		oldReviewer = []string{testutils.User2ID, testutils.User3ID}
+	)
+
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			var (
+				err   error
+				merge = i%10 == 9
+			)
+			if merge {
+				_, err = prUsecase.Merge(ctx, testutils.PR1ID, false)
+			} else {
+				_, _, err = prUsecase.Reassign(ctx, testutils.PR1ID, oldReviewer[i%2])
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil && merge:
+				mergeOK++
+			case err == nil:
+				reassignOK++
+			case !slices.ContainsFunc(conflictErrors, func(target error) bool { return errors.Is(err, target) }):
+				unexpected = append(unexpected, fmt.Sprintf("worker %d: %v", i, err))
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	require.Empty(t, unexpected, "only conflict errors are expected under contention")
+	require.Equal(t, workers/10, mergeOK, "repeated merge must stay idempotent")
+
+	pr, err := stores.PR.FetchByID(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusMerged, pr.Status)
+
+	reviewers, err := stores.PR.ListReviewers(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.Len(t, reviewers, 2)
+	slices.Sort(reviewers)
+	require.Len(t, slices.Compact(reviewers), 2)
+
+	events, err := stores.PR.ListAssignmentEvents(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	reassignEvents := 0
+	for _, e := range events {
+		if e.Type == domain.EventReassigned {
+			reassignEvents++
+		}
+	}
+	require.Equal(t, reassignOK, reassignEvents)
+
+	stats, err := stores.Stats.PRReassignments(ctx, domain.StatsFilter{})
+	require.NoError(t, err)
+	idx := slices.IndexFunc(stats, func(s *domain.PRReassignmentStats) bool { return s.PRID == testutils.PR1ID })
+	require.GreaterOrEqual(t, idx, 0)
+	require.Equal(t, reassignOK, stats[idx].Reassignments)
+}
+
+// testConcurrentReassignAndMergeHTTP гоняет те же операции через HTTP: проигравшие в гонке получают 409 с кодом
+// конфликта, а не 500
+func testConcurrentReassignAndMergeHTTP(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	for i := 7; i <= 12; i++ {
+		addUser(t, stores, fmt.Sprintf("u%d", i), testutils.TestTeam)
+	}
+
+	router := newTestRouter(stores)
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	const workers = 40
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		mergeOK     int
+		unexpected  []string
+		oldReviewer = []string{testutils.User2ID, testutils.User3ID}
+	)
+
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			var (
+				w     *httptest.ResponseRecorder
+				merge = i%10 == 9
+			)
+			if merge {
+				w = post("/pullRequest/merge", dto.PullRequestMergeRequest{PullRequestID: testutils.PR1ID})
+			} else {
+				w = post("/pullRequest/reassign", dto.PullRequestReassignRequest{
+					PullRequestID: testutils.PR1ID,
+					OldUserID:     oldReviewer[i%2],
+				})
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch w.Code {
+			case http.StatusOK:
+				if merge {
+					mergeOK++
+				}
+			case http.StatusConflict:
+				var resp dto.ErrorResponseDTO
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !slices.Contains(conflictCodes, resp.Error.Code) {
+					unexpected = append(unexpected, fmt.Sprintf("worker %d: 409 %s", i, w.Body.String()))
+				}
+			default:
+				unexpected = append(unexpected, fmt.Sprintf("worker %d: %d %s", i, w.Code, w.Body.String()))
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	require.Empty(t, unexpected, "only 200 and 409 with a conflict code are expected under contention")
+	require.Equal(t, workers/10, mergeOK, "repeated merge must stay idempotent")
+
+	pr, err := stores.PR.FetchByID(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusMerged, pr.Status)
+}
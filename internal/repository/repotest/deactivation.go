@@ -0,0 +1,222 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/internal/usecase"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTeamUsecase собирает teamUsecase поверх хранилища stores со стратегией least_loaded
+func newTeamUsecase(stores *Stores) domain.TeamUsecase {
+	return usecase.NewTeamUsecase(stores.Team, stores.User, stores.Tx, usecase.NewLeastLoadedSelector())
+}
+
+func testTeamDeactivation(t *testing.T, backend Backend) {
+	ctx := domain.WithActor(context.Background(), "lead")
+
+	t.Run("replaces_only_open_reviews", func(t *testing.T) {
+		stores := backend(t)
+		addUser(t, stores, "u_extra", testutils.TestTeam)
+
+		res, err := newTeamUsecase(stores).DeactivateUsers(ctx, testutils.TestTeam, []string{testutils.User2ID}, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{testutils.User2ID}, res.Deactivated)
+		require.Equal(t, []*domain.ReviewReassignment{
+			{PRID: testutils.PR1ID, OldReviewerID: testutils.User2ID, NewReviewerID: "u_extra"},
+		}, res.Reassignments)
+
+		revs, err := stores.PR.ListReviewers(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"u_extra", testutils.User3ID}, revs)
+
+		merged, err := stores.PR.ListReviewers(ctx, testutils.PR2ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{testutils.User2ID}, merged)
+
+		events, err := stores.PR.ListAssignmentEvents(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, domain.EventReassigned, events[0].Type)
+		require.Equal(t, "u_extra", events[0].UserID)
+		require.Equal(t, testutils.User2ID, events[0].PreviousUserID)
+		require.Equal(t, "lead", events[0].Actor)
+		require.Equal(t, domain.ReasonTeamDeactivation, events[0].Reason)
+	})
+
+	t.Run("no_candidate_keeps_reviewer", func(t *testing.T) {
+		stores := backend(t)
+
+		res, err := newTeamUsecase(stores).DeactivateUsers(ctx, testutils.TestTeam, []string{testutils.User2ID, testutils.User3ID}, false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*domain.ReviewReassignment{
+			{PRID: testutils.PR1ID, OldReviewerID: testutils.User2ID},
+			{PRID: testutils.PR1ID, OldReviewerID: testutils.User3ID},
+		}, res.Reassignments)
+
+		revs, err := stores.PR.ListReviewers(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{testutils.User2ID, testutils.User3ID}, revs)
+	})
+
+	t.Run("respects_review_capacity", func(t *testing.T) {
+		stores := backend(t)
+
+		addUser(t, stores, "u_extra", testutils.TestTeam)
+		capacity := 1
+		_, err := stores.User.SetMaxOpenReviews(ctx, "u_extra", &capacity)
+		require.NoError(t, err)
+		_, err = stores.PR.Create(ctx, &domain.PullRequest{ID: "pr_cap", Name: "cap", AuthorID: testutils.User1ID, Status: domain.StatusOpen})
+		require.NoError(t, err)
+		require.NoError(t, stores.PR.InsertReviewer(ctx, "pr_cap", testutils.User2ID))
+
+		res, err := newTeamUsecase(stores).DeactivateUsers(ctx, testutils.TestTeam, []string{testutils.User2ID}, false)
+		require.NoError(t, err)
+
+		var extra int
+		for _, ra := range res.Reassignments {
+			if ra.NewReviewerID == "u_extra" {
+				extra++
+			}
+		}
+		require.Equal(t, 1, extra, "u_extra must not exceed its capacity: %+v", res.Reassignments)
+	})
+
+	t.Run("round_robin_continues_after_cursor", func(t *testing.T) {
+		stores := backend(t)
+
+		for _, id := range []string{"u_a", "u_b", "u_c"} {
+			addUser(t, stores, id, testutils.TestTeam)
+		}
+		_, err := stores.Team.LockReviewCursor(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.NoError(t, stores.Team.UpdateReviewCursor(ctx, testutils.TestTeam, "u_a"))
+
+		uc := usecase.NewTeamUsecase(stores.Team, stores.User, stores.Tx, usecase.NewRoundRobinSelector())
+		res, err := uc.DeactivateUsers(ctx, testutils.TestTeam, []string{testutils.User2ID, testutils.User3ID}, false)
+		require.NoError(t, err)
+		require.Equal(t, []*domain.ReviewReassignment{
+			{PRID: testutils.PR1ID, OldReviewerID: testutils.User2ID, NewReviewerID: "u_b"},
+			{PRID: testutils.PR1ID, OldReviewerID: testutils.User3ID, NewReviewerID: "u_c"},
+		}, res.Reassignments)
+
+		cursor, err := stores.Team.LockReviewCursor(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.Equal(t, "u_c", cursor)
+	})
+
+	t.Run("distinct_replacements_on_same_pr", func(t *testing.T) {
+		stores := backend(t)
+
+		addUser(t, stores, "u_a", testutils.TestTeam)
+		addUser(t, stores, "u_b", testutils.TestTeam)
+
+		res, err := newTeamUsecase(stores).DeactivateUsers(ctx, testutils.TestTeam, []string{testutils.User2ID, testutils.User3ID}, false)
+		require.NoError(t, err)
+		require.Len(t, res.Reassignments, 2)
+
+		revs, err := stores.PR.ListReviewers(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"u_a", "u_b"}, revs)
+	})
+}
+
+// largeTeamBudget — предел времени массовой деактивации большой команды
+const largeTeamBudget = 500 * time.Millisecond
+
+func testTeamDeactivationLargeTeam(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	const teamSize = 220
+	err := stores.Tx.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		if err := repos.Team.Create(ctx, "big_team"); err != nil {
+			return err
+		}
+		for g := 1; g <= teamSize; g++ {
+			user := &domain.User{ID: fmt.Sprintf("big_%d", g), Name: fmt.Sprintf("Big %d", g), TeamName: "big_team", IsActive: true}
+			if err := repos.User.Upsert(ctx, user); err != nil {
+				return err
+			}
+		}
+		for g := 1; g <= teamSize; g++ {
+			prID := fmt.Sprintf("big_pr_%d", g)
+			pr := &domain.PullRequest{ID: prID, Name: fmt.Sprintf("Big PR %d", g), AuthorID: fmt.Sprintf("big_%d", g), Status: domain.StatusOpen}
+			if _, err := repos.PR.Create(ctx, pr); err != nil {
+				return err
+			}
+			for _, n := range []int{g%teamSize + 1, (g+1)%teamSize + 1} {
+				if err := repos.PR.InsertReviewer(ctx, prID, fmt.Sprintf("big_%d", n)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	res, err := newTeamUsecase(stores).DeactivateUsers(ctx, "big_team",
+		[]string{"big_1", "big_2", "big_3", "big_4", "big_5", "big_6", "big_7", "big_8", "big_9", "big_10"}, true)
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+	require.Len(t, res.Deactivated, teamSize-10)
+	require.Len(t, res.Reassignments, 2*(teamSize-10))
+	// замены подбираются одним запросом; поштучный подбор по каждому PR занимал на SQLite около секунды
+	require.Less(t, elapsed, largeTeamBudget, "deactivation of %d users took %s", len(res.Deactivated), elapsed)
+
+	load := make(map[string]int)
+	for _, r := range res.Reassignments {
+		require.NotEmpty(t, r.NewReviewerID, "review of %s on %s must be reassigned", r.OldReviewerID, r.PRID)
+		load[r.NewReviewerID]++
+	}
+	// замены раздаются по кругу, поэтому оставшиеся участники получают их поровну с точностью до исключений по PR
+	require.Len(t, load, 10)
+	for id, n := range load {
+		u, err := stores.User.FetchByID(ctx, id)
+		require.NoError(t, err)
+		require.True(t, u.IsActive)
+		require.InDelta(t, len(res.Reassignments)/10, n, 3, "replacements of %s: %v", id, load)
+	}
+
+	revs, err := stores.PR.ListReviewers(ctx, "big_pr_50")
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	require.NotEqual(t, revs[0], revs[1])
+}
+
+func testTeamDeleteAuthorInDeletedTeam(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	_, err := stores.Team.UpsertSettings(ctx, &domain.TeamSettings{
+		TeamName:      testutils.TestTeam,
+		MinReviewers:  domain.DefaultMinReviewers,
+		MaxReviewers:  domain.DefaultMaxReviewers,
+		FallbackTeams: []string{testutils.OtherTeam},
+	})
+	require.NoError(t, err)
+
+	res, err := newTeamUsecase(stores).Delete(ctx, testutils.TestTeam, true)
+	require.NoError(t, err)
+	require.Len(t, res.Reassignments, 2)
+
+	revs, err := stores.PR.ListReviewers(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	require.NotEqual(t, revs[0], revs[1])
+	for _, id := range revs {
+		u, err := stores.User.FetchByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, testutils.OtherTeam, u.TeamName, "replacement %s must come from the fallback team", id)
+	}
+
+	exists, err := stores.Team.Exists(ctx, testutils.TestTeam)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
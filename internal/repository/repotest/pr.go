@@ -0,0 +1,528 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPRRepositoryCreateSuccess(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	newPR := &domain.PullRequest{
+		ID:       "pr_new",
+		Name:     "new feature",
+		AuthorID: testutils.User1ID,
+		Status:   domain.StatusOpen,
+	}
+
+	created, err := repo.Create(ctx, newPR)
+	require.NoError(t, err)
+	require.NotNil(t, created)
+
+	require.Equal(t, newPR.ID, created.ID)
+	require.Equal(t, newPR.Name, created.Name)
+	require.Equal(t, newPR.AuthorID, created.AuthorID)
+	require.Equal(t, newPR.Status, created.Status)
+	require.NotNil(t, created.CreatedAt)
+
+	dbPR, err := repo.FetchByID(ctx, newPR.ID)
+	require.NoError(t, err)
+	require.Equal(t, newPR.ID, dbPR.ID)
+	require.Equal(t, newPR.Name, dbPR.Name)
+	require.Equal(t, newPR.AuthorID, dbPR.AuthorID)
+	require.Equal(t, newPR.Status, dbPR.Status)
+}
+
+func testPRRepositoryCreateErrAlreadyExists(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	pr := &domain.PullRequest{
+		ID:       testutils.PR1ID,
+		Name:     "whatever",
+		AuthorID: testutils.User1ID,
+		Status:   domain.StatusOpen,
+	}
+
+	created, err := repo.Create(ctx, pr)
+	require.Error(t, err)
+	require.Nil(t, created)
+	require.ErrorIs(t, err, domain.ErrAlreadyExists)
+
+	existing, err := repo.FetchByID(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.Equal(t, testutils.PR1Name, existing.Name)
+}
+
+func testPRRepositoryCreateErrNotFoundAuthor(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	const newID = "pr_bad_author"
+
+	pr := &domain.PullRequest{
+		ID:       newID,
+		Name:     "bad author",
+		AuthorID: "no_such_user",
+		Status:   domain.StatusOpen,
+	}
+
+	created, err := repo.Create(ctx, pr)
+	require.Error(t, err)
+	require.Nil(t, created)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = repo.FetchByID(ctx, newID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testPRRepositoryFetchByID(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("success", func(t *testing.T) {
+		pr, err := repo.FetchByID(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.NotNil(t, pr)
+
+		require.Equal(t, testutils.PR1ID, pr.ID)
+		require.Equal(t, testutils.PR1Name, pr.Name)
+		require.Equal(t, testutils.User1ID, pr.AuthorID)
+		require.Equal(t, domain.StatusOpen, pr.Status)
+		require.NotNil(t, pr.CreatedAt)
+		require.Nil(t, pr.MergedAt)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		pr, err := repo.FetchByID(ctx, "no_such_pr")
+		require.Error(t, err)
+		require.Nil(t, pr)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func testPRRepositoryUpdateStatusMerged(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("merge_open_pr", func(t *testing.T) {
+		pr, err := repo.UpdateStatusMerged(ctx, testutils.PR1ID, "")
+		require.NoError(t, err)
+		require.NotNil(t, pr)
+
+		require.Equal(t, testutils.PR1ID, pr.ID)
+		require.Equal(t, domain.StatusMerged, pr.Status)
+		require.NotNil(t, pr.MergedAt)
+
+		fetched, err := repo.FetchByID(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.StatusMerged, fetched.Status)
+		require.Empty(t, pr.MergeOverrideBy)
+	})
+
+	t.Run("merge_with_override", func(t *testing.T) {
+		pr, err := repo.UpdateStatusMerged(ctx, testutils.PR3ID, "lead")
+		require.NoError(t, err)
+		require.Equal(t, "lead", pr.MergeOverrideBy)
+
+		fetched, err := repo.FetchByID(ctx, testutils.PR3ID)
+		require.NoError(t, err)
+		require.Equal(t, "lead", fetched.MergeOverrideBy)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		pr, err := repo.UpdateStatusMerged(ctx, "no_such_pr", "")
+		require.Error(t, err)
+		require.Nil(t, pr)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func testPRRepositoryListReviewableByUserID(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("user2_has_two_prs", func(t *testing.T) {
+		prs, err := repo.ListReviewableByUserID(ctx, testutils.User2ID)
+		require.NoError(t, err)
+		require.Len(t, prs, 2)
+
+		ids := []string{prs[0].ID, prs[1].ID}
+		require.ElementsMatch(t, []string{testutils.PR1ID, testutils.PR2ID}, ids)
+	})
+
+	t.Run("user5_has_one_pr", func(t *testing.T) {
+		prs, err := repo.ListReviewableByUserID(ctx, testutils.User5ID)
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		require.Equal(t, testutils.PR3ID, prs[0].ID)
+	})
+
+	t.Run("user1_has_no_prs", func(t *testing.T) {
+		prs, err := repo.ListReviewableByUserID(ctx, testutils.User1ID)
+		require.NoError(t, err)
+		require.Len(t, prs, 0)
+	})
+
+	t.Run("closed_pr_excluded", func(t *testing.T) {
+		_, err := repo.UpdateStatusClosed(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+
+		prs, err := repo.ListReviewableByUserID(ctx, testutils.User2ID)
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		require.Equal(t, testutils.PR2ID, prs[0].ID)
+	})
+}
+
+func testPRRepositoryCloseAndReopen(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("close_open_pr", func(t *testing.T) {
+		pr, err := repo.UpdateStatusClosed(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.StatusClosed, pr.Status)
+		require.NotNil(t, pr.ClosedAt)
+
+		again, err := repo.UpdateStatusClosed(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.True(t, pr.ClosedAt.Equal(*again.ClosedAt))
+	})
+
+	t.Run("reopen_clears_closed_at", func(t *testing.T) {
+		pr, err := repo.UpdateStatusOpen(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.StatusOpen, pr.Status)
+		require.Nil(t, pr.ClosedAt)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		_, err := repo.UpdateStatusClosed(ctx, "no_such_pr")
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func testPRRepositoryDraftAndReady(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	created, err := repo.Create(ctx, &domain.PullRequest{
+		ID:       "pr_draft",
+		Name:     "wip",
+		AuthorID: testutils.User1ID,
+		Status:   domain.StatusOpen,
+		IsDraft:  true,
+	})
+	require.NoError(t, err)
+	require.True(t, created.IsDraft)
+
+	fetched, err := repo.FetchByID(ctx, "pr_draft")
+	require.NoError(t, err)
+	require.True(t, fetched.IsDraft)
+
+	ready, err := repo.UpdateReady(ctx, "pr_draft")
+	require.NoError(t, err)
+	require.False(t, ready.IsDraft)
+	require.Equal(t, domain.StatusOpen, ready.Status)
+
+	_, err = repo.UpdateReady(ctx, "no_such_pr")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testPRRepositoryReviewStates(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("new_reviewers_are_pending", func(t *testing.T) {
+		states, err := repo.ListReviewerStates(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.Len(t, states, 2)
+		for _, st := range states {
+			require.Equal(t, domain.ReviewPending, st.State)
+			require.Nil(t, st.UpdatedAt)
+		}
+	})
+
+	t.Run("approve_removes_from_pending_queue", func(t *testing.T) {
+		require.NoError(t, repo.UpdateReviewState(ctx, testutils.PR1ID, testutils.User2ID, domain.ReviewApproved))
+
+		pending, err := repo.ListReviewableByUserIDAndState(ctx, testutils.User2ID, domain.ReviewPending)
+		require.NoError(t, err)
+		for _, pr := range pending {
+			require.NotEqual(t, testutils.PR1ID, pr.ID)
+		}
+
+		approved, err := repo.ListReviewableByUserIDAndState(ctx, testutils.User2ID, domain.ReviewApproved)
+		require.NoError(t, err)
+		require.Len(t, approved, 1)
+		require.Equal(t, testutils.PR1ID, approved[0].ID)
+	})
+
+	t.Run("not_assigned", func(t *testing.T) {
+		err := repo.UpdateReviewState(ctx, testutils.PR1ID, testutils.User4ID, domain.ReviewApproved)
+		require.ErrorIs(t, err, domain.ErrNotAssigned)
+	})
+
+	t.Run("replacement_resets_state", func(t *testing.T) {
+		require.NoError(t, repo.ReplaceReviewer(ctx, testutils.PR1ID, testutils.User2ID, testutils.User4ID))
+
+		states, err := repo.ListReviewerStates(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		for _, st := range states {
+			if st.UserID == testutils.User4ID {
+				require.Equal(t, domain.ReviewPending, st.State)
+			}
+		}
+	})
+}
+
+func testPRRepositoryReviewQueue(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("pending_queue_skips_merged", func(t *testing.T) {
+		pending, err := repo.ListReviewableByUserIDAndState(ctx, testutils.User2ID, domain.ReviewPending)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, testutils.PR1ID, pending[0].ID)
+	})
+
+	t.Run("states_ordered_by_update_time", func(t *testing.T) {
+		require.NoError(t, repo.UpdateReviewState(ctx, testutils.PR1ID, testutils.User3ID, domain.ReviewApproved))
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, repo.UpdateReviewState(ctx, testutils.PR1ID, testutils.User2ID, domain.ReviewChangesRequested))
+
+		states, err := repo.ListReviewerStates(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.Len(t, states, 2)
+		require.Equal(t, testutils.User3ID, states[0].UserID)
+		require.Equal(t, testutils.User2ID, states[1].UserID)
+	})
+}
+
+func testPRRepositoryRemoveReviewer(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	require.NoError(t, repo.RemoveReviewer(ctx, testutils.PR1ID, testutils.User2ID))
+
+	revs, err := repo.ListReviewers(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{testutils.User3ID}, revs)
+}
+
+func testPRRepositoryListReviewers(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("pr1_reviewers", func(t *testing.T) {
+		revs, err := repo.ListReviewers(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{testutils.User2ID, testutils.User3ID}, revs)
+	})
+
+	t.Run("no_reviewers_for_unknown_pr", func(t *testing.T) {
+		revs, err := repo.ListReviewers(ctx, "no_such_pr")
+		require.NoError(t, err)
+		require.Len(t, revs, 0)
+	})
+}
+
+func testPRRepositoryInsertReviewer(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	err := repo.InsertReviewer(ctx, testutils.PR3ID, testutils.User2ID)
+	require.NoError(t, err)
+
+	revs, err := repo.ListReviewers(ctx, testutils.PR3ID)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{testutils.User5ID, testutils.User2ID}, revs)
+}
+
+func testPRRepositoryReplaceReviewer(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	repo := stores.PR
+
+	addUser(t, stores, "u_extra", testutils.TestTeam)
+
+	err := repo.ReplaceReviewer(ctx, testutils.PR1ID, testutils.User2ID, "u_extra")
+	require.NoError(t, err)
+
+	revs, err := repo.ListReviewers(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"u_extra", testutils.User3ID}, revs)
+	require.NotContains(t, revs, testutils.User2ID)
+}
+
+func testPRRepositoryReviewerAssigned(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("assigned_true", func(t *testing.T) {
+		ok, err := repo.ReviewerAssigned(ctx, testutils.PR1ID, testutils.User2ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("assigned_false", func(t *testing.T) {
+		ok, err := repo.ReviewerAssigned(ctx, testutils.PR1ID, testutils.User4ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func testPRRepositoryCountOpenReviews(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("only_open_prs_counted", func(t *testing.T) {
+		counts, err := repo.CountOpenReviews(ctx, []string{
+			testutils.User1ID, testutils.User2ID, testutils.User3ID, testutils.User5ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{
+			testutils.User2ID: 1,
+			testutils.User3ID: 1,
+			testutils.User5ID: 1,
+		}, counts)
+	})
+
+	t.Run("empty_input", func(t *testing.T) {
+		counts, err := repo.CountOpenReviews(ctx, nil)
+		require.NoError(t, err)
+		require.Empty(t, counts)
+	})
+}
+
+func testPRRepositoryAssignmentEvents(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("append_and_list_in_order", func(t *testing.T) {
+		err := repo.AddAssignmentEvents(ctx,
+			&domain.AssignmentEvent{PRID: testutils.PR1ID, Type: domain.EventAssigned, UserID: testutils.User2ID, Actor: domain.SystemActor, Reason: domain.ReasonPRCreated},
+			&domain.AssignmentEvent{PRID: testutils.PR1ID, Type: domain.EventReassigned, UserID: testutils.User4ID, PreviousUserID: testutils.User2ID, Actor: "admin", Reason: domain.ReasonManualReassign},
+		)
+		require.NoError(t, err)
+
+		events, err := repo.ListAssignmentEvents(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, domain.EventAssigned, events[0].Type)
+		require.Empty(t, events[0].PreviousUserID)
+		require.Equal(t, domain.EventReassigned, events[1].Type)
+		require.Equal(t, testutils.User2ID, events[1].PreviousUserID)
+		require.Equal(t, "admin", events[1].Actor)
+		require.False(t, events[1].CreatedAt.IsZero())
+	})
+
+	t.Run("no_events_is_noop", func(t *testing.T) {
+		require.NoError(t, repo.AddAssignmentEvents(ctx))
+	})
+
+	t.Run("empty_for_unknown_pr", func(t *testing.T) {
+		events, err := repo.ListAssignmentEvents(ctx, "missing")
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
+
+func testPRRepositoryList(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).PR
+
+	t.Run("paginates_by_id", func(t *testing.T) {
+		filter := domain.PRFilter{PageParams: domain.PageParams{Sort: domain.SortByID, Order: domain.SortAsc, Limit: 2}}
+
+		first, err := repo.List(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, first.Items, 2)
+		require.Equal(t, testutils.PR1ID, first.Items[0].ID)
+		require.ElementsMatch(t, []string{testutils.User2ID, testutils.User3ID}, first.Items[0].Reviewers)
+		require.NotEmpty(t, first.NextCursor)
+
+		filter.Cursor = first.NextCursor
+		second, err := repo.List(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, second.Items, 1)
+		require.Equal(t, testutils.PR3ID, second.Items[0].ID)
+		require.Empty(t, second.NextCursor)
+	})
+
+	t.Run("paginates_by_created_at_desc", func(t *testing.T) {
+		filter := domain.PRFilter{PageParams: domain.PageParams{Sort: domain.SortByCreatedAt, Order: domain.SortDesc, Limit: 1}}
+
+		var ids []string
+		for {
+			page, err := repo.List(ctx, filter)
+			require.NoError(t, err)
+			for _, pr := range page.Items {
+				ids = append(ids, pr.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			filter.Cursor = page.NextCursor
+		}
+		require.ElementsMatch(t, []string{testutils.PR1ID, testutils.PR2ID, testutils.PR3ID}, ids)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		page, err := repo.List(ctx, domain.PRFilter{
+			Status:     domain.StatusOpen,
+			ReviewerID: testutils.User2ID,
+			PageParams: domain.PageParams{Sort: domain.SortByID, Order: domain.SortAsc, Limit: 10},
+		})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		require.Equal(t, testutils.PR1ID, page.Items[0].ID)
+
+		page, err = repo.List(ctx, domain.PRFilter{
+			TeamName:   testutils.OtherTeam,
+			PageParams: domain.PageParams{Sort: domain.SortByID, Order: domain.SortAsc, Limit: 10},
+		})
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		require.Equal(t, testutils.PR3ID, page.Items[0].ID)
+	})
+
+	t.Run("malformed_cursor", func(t *testing.T) {
+		_, err := repo.List(ctx, domain.PRFilter{
+			PageParams: domain.PageParams{Sort: domain.SortByID, Order: domain.SortAsc, Limit: 10, Cursor: "%%%"},
+		})
+		require.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("cursor_for_other_sort", func(t *testing.T) {
+		page, err := repo.List(ctx, domain.PRFilter{PageParams: domain.PageParams{Sort: domain.SortByID, Order: domain.SortAsc, Limit: 1}})
+		require.NoError(t, err)
+		require.NotEmpty(t, page.NextCursor)
+
+		_, err = repo.List(ctx, domain.PRFilter{
+			PageParams: domain.PageParams{Sort: domain.SortByCreatedAt, Order: domain.SortAsc, Limit: 1, Cursor: page.NextCursor},
+		})
+		require.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("cursor_for_other_order", func(t *testing.T) {
+		page, err := repo.List(ctx, domain.PRFilter{PageParams: domain.PageParams{Sort: domain.SortByID, Order: domain.SortAsc, Limit: 1}})
+		require.NoError(t, err)
+		require.NotEmpty(t, page.NextCursor)
+
+		_, err = repo.List(ctx, domain.PRFilter{
+			PageParams: domain.PageParams{Sort: domain.SortByID, Order: domain.SortDesc, Limit: 1, Cursor: page.NextCursor},
+		})
+		require.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
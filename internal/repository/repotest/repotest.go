@@ -0,0 +1,109 @@
+// Package repotest — общий контрактный тест репозиториев: один набор проверок для всех реализаций хранилища.
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Stores — репозитории и менеджер транзакций одного хранилища
+type Stores struct {
+	User  domain.UserRepository
+	Team  domain.TeamRepository
+	PR    domain.PRRepository
+	Stats domain.StatsRepository
+	Tx    domain.TxManager
+}
+
+// Backend возвращает хранилище к набору фикстур testutils и отдает его репозитории.
+// Репозитории, полученные до повторного вызова, использовать нельзя.
+type Backend func(t *testing.T) *Stores
+
+// Run прогоняет контрактный тест на хранилище backend
+func Run(t *testing.T, backend Backend) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, backend Backend)
+	}{
+		{"UserRepository_Upsert_InsertNew", testUserRepositoryUpsertInsertNew},
+		{"UserRepository_Upsert_UpdateExisting", testUserRepositoryUpsertUpdateExisting},
+		{"UserRepository_FetchByID", testUserRepositoryFetchByID},
+		{"UserRepository_FetchByIDs", testUserRepositoryFetchByIDs},
+		{"UserRepository_FetchByTeam", testUserRepositoryFetchByTeam},
+		{"UserRepository_FetchActiveByTeam", testUserRepositoryFetchActiveByTeam},
+		{"UserRepository_Exists", testUserRepositoryExists},
+		{"UserRepository_UpdateIsActive", testUserRepositoryUpdateIsActive},
+		{"UserRepository_DeactivateTeamMembers", testUserRepositoryDeactivateTeamMembers},
+		{"UserRepository_RemoveFromTeam", testUserRepositoryRemoveFromTeam},
+		{"UserRepository_List", testUserRepositoryList},
+		{"UserRepository_ReviewLimits", testUserRepositoryReviewLimits},
+		{"UserRepository_Absences", testUserRepositoryAbsences},
+		{"UserUsecase_ChainedAbsences", testChainedAbsences},
+
+		{"TeamRepository_Create", testTeamRepositoryCreate},
+		{"TeamRepository_Exists", testTeamRepositoryExists},
+		{"TeamRepository_ReviewCursor", testTeamRepositoryReviewCursor},
+		{"TeamRepository_Rename", testTeamRepositoryRename},
+		{"TeamRepository_SetArchived", testTeamRepositorySetArchived},
+		{"TeamRepository_Delete", testTeamRepositoryDelete},
+		{"TeamRepository_List", testTeamRepositoryList},
+		{"TeamRepository_Settings", testTeamRepositorySettings},
+
+		{"PRRepository_Create_Success", testPRRepositoryCreateSuccess},
+		{"PRRepository_Create_ErrAlreadyExists", testPRRepositoryCreateErrAlreadyExists},
+		{"PRRepository_Create_ErrNotFoundAuthor", testPRRepositoryCreateErrNotFoundAuthor},
+		{"PRRepository_FetchByID", testPRRepositoryFetchByID},
+		{"PRRepository_UpdateStatusMerged", testPRRepositoryUpdateStatusMerged},
+		{"PRRepository_ListReviewableByUserID", testPRRepositoryListReviewableByUserID},
+		{"PRRepository_CloseAndReopen", testPRRepositoryCloseAndReopen},
+		{"PRRepository_DraftAndReady", testPRRepositoryDraftAndReady},
+		{"PRRepository_ReviewStates", testPRRepositoryReviewStates},
+		{"PRRepository_ReviewQueue", testPRRepositoryReviewQueue},
+		{"PRRepository_RemoveReviewer", testPRRepositoryRemoveReviewer},
+		{"PRRepository_ListReviewers", testPRRepositoryListReviewers},
+		{"PRRepository_InsertReviewer", testPRRepositoryInsertReviewer},
+		{"PRRepository_ReplaceReviewer", testPRRepositoryReplaceReviewer},
+		{"PRRepository_ReviewerAssigned", testPRRepositoryReviewerAssigned},
+		{"PRRepository_CountOpenReviews", testPRRepositoryCountOpenReviews},
+		{"PRRepository_AssignmentEvents", testPRRepositoryAssignmentEvents},
+		{"PRRepository_List", testPRRepositoryList},
+
+		{"StatsRepository_UserAssignments", testStatsRepositoryUserAssignments},
+		{"StatsRepository_PRReassignments", testStatsRepositoryPRReassignments},
+		{"StatsRepository_TeamTotals", testStatsRepositoryTeamTotals},
+		{"StatsRepository_TeamTotals_SkipsClosed", testStatsRepositoryTeamTotalsSkipsClosed},
+
+		{"TxManager_SuccessCommit", testTxManagerSuccessCommit},
+		{"TxManager_RollbackOnError", testTxManagerRollbackOnError},
+		{"TxManager_DoesNotRetryOtherErrors", testTxManagerDoesNotRetryOtherErrors},
+		{"TxManager_ReadOnly", testTxManagerReadOnly},
+		{"TxManager_NestedRollbackKeepsOuter", testTxManagerNestedRollbackKeepsOuter},
+		{"TxManager_OuterRollbackDiscardsNested", testTxManagerOuterRollbackDiscardsNested},
+
+		{"TeamDeactivation", testTeamDeactivation},
+		{"TeamDeactivation_LargeTeam", testTeamDeactivationLargeTeam},
+		{"TeamDelete_AuthorInDeletedTeam", testTeamDeleteAuthorInDeletedTeam},
+		{"ConcurrentReassignAndMerge", testConcurrentReassignAndMerge},
+		{"ConcurrentReassignAndMergeHTTP", testConcurrentReassignAndMergeHTTP},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, backend)
+		})
+	}
+}
+
+// addUser добавляет в команду активного пользователя с именем, совпадающим с id
+func addUser(t *testing.T, stores *Stores, userID, teamName string) {
+	t.Helper()
+	require.NoError(t, stores.User.Upsert(context.Background(), &domain.User{
+		ID:       userID,
+		Name:     userID,
+		TeamName: teamName,
+		IsActive: true,
+	}))
+}
@@ -0,0 +1,111 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testStatsRepositoryUserAssignments(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	repo := stores.Stats
+
+	_, err := stores.PR.UpdateStatusMerged(ctx, testutils.PR2ID, "")
+	require.NoError(t, err)
+
+	t.Run("team_filter", func(t *testing.T) {
+		stats, err := repo.UserAssignments(ctx, domain.StatsFilter{TeamName: testutils.TestTeam})
+		require.NoError(t, err)
+		require.Len(t, stats, 3)
+
+		byID := make(map[string]*domain.UserAssignmentStats, len(stats))
+		for _, s := range stats {
+			byID[s.UserID] = s
+		}
+
+		require.Equal(t, 2, byID[testutils.User2ID].Total)
+		require.Equal(t, 1, byID[testutils.User2ID].Open)
+		require.Equal(t, 1, byID[testutils.User2ID].Merged)
+		require.Equal(t, 1, byID[testutils.User3ID].Open)
+		require.Equal(t, 0, byID[testutils.User1ID].Total)
+	})
+
+	t.Run("closed_prs_not_counted", func(t *testing.T) {
+		_, err := stores.PR.UpdateStatusClosed(ctx, testutils.PR1ID)
+		require.NoError(t, err)
+
+		stats, err := repo.UserAssignments(ctx, domain.StatsFilter{TeamName: testutils.TestTeam})
+		require.NoError(t, err)
+
+		byID := make(map[string]*domain.UserAssignmentStats, len(stats))
+		for _, s := range stats {
+			byID[s.UserID] = s
+		}
+		require.Equal(t, 1, byID[testutils.User2ID].Total)
+		require.Zero(t, byID[testutils.User2ID].Open)
+		require.Zero(t, byID[testutils.User3ID].Total)
+	})
+
+	t.Run("future_range_counts_nothing", func(t *testing.T) {
+		from := time.Now().Add(time.Hour)
+		stats, err := repo.UserAssignments(ctx, domain.StatsFilter{From: &from})
+		require.NoError(t, err)
+		require.Len(t, stats, 6)
+		for _, s := range stats {
+			require.Zero(t, s.Total)
+			require.Zero(t, s.Merged)
+		}
+	})
+}
+
+func testStatsRepositoryPRReassignments(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	repo, prRepo := stores.Stats, stores.PR
+
+	addUser(t, stores, "u_extra", testutils.TestTeam)
+	require.NoError(t, prRepo.ReplaceReviewer(ctx, testutils.PR1ID, testutils.User2ID, "u_extra"))
+
+	stats, err := repo.PRReassignments(ctx, domain.StatsFilter{})
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+
+	require.Equal(t, testutils.PR1ID, stats[0].PRID)
+	require.Equal(t, 1, stats[0].Reassignments)
+	require.Equal(t, 2, stats[0].Reviewers)
+}
+
+func testStatsRepositoryTeamTotals(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	repo := stores.Stats
+
+	_, err := stores.PR.UpdateStatusMerged(ctx, testutils.PR2ID, "")
+	require.NoError(t, err)
+
+	stats, err := repo.TeamTotals(ctx, domain.StatsFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []*domain.TeamStats{
+		{TeamName: testutils.OtherTeam, Members: 3, ActiveMembers: 2, OpenPRs: 1, MergedPRs: 0, Assignments: 1},
+		{TeamName: testutils.TestTeam, Members: 3, ActiveMembers: 3, OpenPRs: 1, MergedPRs: 1, Assignments: 3},
+	}, stats)
+}
+
+func testStatsRepositoryTeamTotalsSkipsClosed(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	_, err := stores.PR.UpdateStatusClosed(ctx, testutils.PR1ID)
+	require.NoError(t, err)
+
+	stats, err := stores.Stats.TeamTotals(ctx, domain.StatsFilter{TeamName: testutils.TestTeam})
+	require.NoError(t, err)
+	require.Equal(t, []*domain.TeamStats{
+		{TeamName: testutils.TestTeam, Members: 3, ActiveMembers: 3, OpenPRs: 0, MergedPRs: 1, Assignments: 1},
+	}, stats)
+}
@@ -0,0 +1,219 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testTeamRepositoryCreate(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	tr := backend(t).Team
+
+	t.Run("success_create_new_team", func(t *testing.T) {
+		teamName := "new_team"
+
+		err := tr.Create(ctx, teamName)
+		require.NoError(t, err)
+
+		exists, err := tr.Exists(ctx, teamName)
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+
+	t.Run("error_team_already_exists", func(t *testing.T) {
+		err := tr.Create(ctx, testutils.TestTeam)
+		require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	})
+}
+
+func testTeamRepositoryExists(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	tr := backend(t).Team
+
+	t.Run("team_exists", func(t *testing.T) {
+		ok, err := tr.Exists(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("team_not_exists", func(t *testing.T) {
+		ok, err := tr.Exists(ctx, "unknown_team")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func testTeamRepositoryReviewCursor(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	tr := backend(t).Team
+
+	t.Run("new_team_cursor_is_empty", func(t *testing.T) {
+		last, err := tr.LockReviewCursor(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.Equal(t, "", last)
+	})
+
+	t.Run("update_and_read_back", func(t *testing.T) {
+		require.NoError(t, tr.UpdateReviewCursor(ctx, testutils.TestTeam, testutils.User2ID))
+
+		last, err := tr.LockReviewCursor(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.Equal(t, testutils.User2ID, last)
+	})
+}
+
+func testTeamRepositoryRename(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	tr, ur := stores.Team, stores.User
+
+	t.Run("cascades_to_members_and_cursor", func(t *testing.T) {
+		_, err := tr.LockReviewCursor(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+
+		require.NoError(t, tr.Rename(ctx, testutils.TestTeam, "renamed_team"))
+
+		members, err := ur.FetchByTeam(ctx, "renamed_team")
+		require.NoError(t, err)
+		require.Len(t, members, 3)
+
+		_, err = tr.LockReviewCursor(ctx, "renamed_team")
+		require.NoError(t, err)
+
+		ok, err := tr.Exists(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		err := tr.Rename(ctx, "unknown_team", "whatever")
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("target_taken", func(t *testing.T) {
+		// параллельное переименование могло занять имя уже после проверки в usecase
+		err := tr.Rename(ctx, testutils.OtherTeam, "renamed_team")
+		require.ErrorIs(t, err, domain.ErrAlreadyExists)
+	})
+}
+
+func testTeamRepositorySetArchived(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	tr, ur := stores.Team, stores.User
+
+	team, err := tr.SetArchived(ctx, testutils.TestTeam, true)
+	require.NoError(t, err)
+	require.NotNil(t, team.ArchivedAt)
+
+	active, err := ur.FetchActiveByTeam(ctx, testutils.TestTeam)
+	require.NoError(t, err)
+	require.Empty(t, active)
+
+	team, err = tr.SetArchived(ctx, testutils.TestTeam, false)
+	require.NoError(t, err)
+	require.Nil(t, team.ArchivedAt)
+
+	active, err = ur.FetchActiveByTeam(ctx, testutils.TestTeam)
+	require.NoError(t, err)
+	require.Len(t, active, 3)
+
+	_, err = tr.SetArchived(ctx, "unknown_team", true)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testTeamRepositoryDelete(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	tr := backend(t).Team
+
+	require.NoError(t, tr.Create(ctx, "empty_team"))
+	_, err := tr.LockReviewCursor(ctx, "empty_team")
+	require.NoError(t, err)
+
+	require.NoError(t, tr.Delete(ctx, "empty_team"))
+
+	ok, err := tr.Exists(ctx, "empty_team")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.ErrorIs(t, tr.Delete(ctx, "empty_team"), domain.ErrNotFound)
+}
+
+func testTeamRepositoryList(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	tr := backend(t).Team
+	_, err := tr.SetArchived(ctx, testutils.OtherTeam, true)
+	require.NoError(t, err)
+
+	page, err := tr.List(ctx, domain.TeamFilter{PageParams: domain.PageParams{Sort: domain.SortByName, Order: domain.SortAsc, Limit: 1}})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, testutils.OtherTeam, page.Items[0].Name)
+	require.NotEmpty(t, page.NextCursor)
+
+	archived := false
+	page, err = tr.List(ctx, domain.TeamFilter{
+		Archived:   &archived,
+		PageParams: domain.PageParams{Sort: domain.SortByName, Order: domain.SortAsc, Limit: 10},
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, testutils.TestTeam, page.Items[0].Name)
+}
+
+func testTeamRepositorySettings(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	tr, ur := stores.Team, stores.User
+
+	require.NoError(t, tr.Create(ctx, "third_team"))
+
+	t.Run("defaults", func(t *testing.T) {
+		got, err := tr.FetchSettings(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.Equal(t, domain.DefaultMinReviewers, got.MinReviewers)
+		require.Equal(t, domain.DefaultMaxReviewers, got.MaxReviewers)
+		require.Empty(t, got.FallbackTeams)
+	})
+
+	t.Run("upsert_keeps_fallback_order", func(t *testing.T) {
+		saved, err := tr.UpsertSettings(ctx, &domain.TeamSettings{
+			TeamName:      testutils.TestTeam,
+			MinReviewers:  1,
+			MaxReviewers:  3,
+			FallbackTeams: []string{"third_team", testutils.OtherTeam},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, saved.MinReviewers)
+		require.Equal(t, 3, saved.MaxReviewers)
+		require.Equal(t, []string{"third_team", testutils.OtherTeam}, saved.FallbackTeams)
+
+		saved, err = tr.UpsertSettings(ctx, &domain.TeamSettings{
+			TeamName:      testutils.TestTeam,
+			MaxReviewers:  2,
+			FallbackTeams: []string{testutils.OtherTeam},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, saved.MaxReviewers)
+		require.Equal(t, []string{testutils.OtherTeam}, saved.FallbackTeams)
+	})
+
+	t.Run("fallback_removed_with_team", func(t *testing.T) {
+		_, err := ur.RemoveFromTeam(ctx, testutils.OtherTeam, []string{testutils.User4ID, testutils.User5ID, testutils.User6ID})
+		require.NoError(t, err)
+		require.NoError(t, tr.Delete(ctx, testutils.OtherTeam))
+
+		got, err := tr.FetchSettings(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.Empty(t, got.FallbackTeams)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		_, err := tr.FetchSettings(ctx, "unknown_team")
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
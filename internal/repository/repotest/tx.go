@@ -0,0 +1,126 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/internal/usecase"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTxManagerSuccessCommit(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	err := stores.Tx.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		return repos.Team.Create(ctx, "tx_team")
+	})
+	require.NoError(t, err)
+
+	exists, err := stores.Team.Exists(ctx, "tx_team")
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func testTxManagerRollbackOnError(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	err := stores.Tx.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		require.NoError(t, repos.Team.Create(ctx, "rollback_team"))
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	exists, err := stores.Team.Exists(ctx, "rollback_team")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func testTxManagerDoesNotRetryOtherErrors(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	calls := 0
+	err := stores.Tx.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		calls++
+		return assert.AnError
+	})
+
+	require.ErrorIs(t, err, assert.AnError)
+	require.Equal(t, 1, calls)
+}
+
+func testTxManagerReadOnly(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	err := stores.Tx.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		return repos.Team.Create(ctx, "readonly_team")
+	}, domain.ReadOnly())
+	require.Error(t, err)
+
+	exists, err := stores.Team.Exists(ctx, "readonly_team")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func testTxManagerNestedRollbackKeepsOuter(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	m := stores.Tx
+
+	err := m.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		require.NoError(t, repos.Team.Create(ctx, "outer_team"))
+
+		innerErr := m.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+			// вложенная транзакция видит незафиксированные изменения внешней
+			exists, err := repos.Team.Exists(ctx, "outer_team")
+			require.NoError(t, err)
+			require.True(t, exists)
+
+			require.NoError(t, repos.Team.Create(ctx, "inner_team"))
+			return assert.AnError
+		})
+		require.ErrorIs(t, innerErr, assert.AnError)
+
+		// после отката вложенной транзакции внешняя остается рабочей
+		return m.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+			return repos.Team.Create(ctx, "second_inner_team")
+		})
+	})
+	require.NoError(t, err)
+
+	for name, want := range map[string]bool{"outer_team": true, "inner_team": false, "second_inner_team": true} {
+		exists, err := stores.Team.Exists(ctx, name)
+		require.NoError(t, err)
+		require.Equal(t, want, exists, name)
+	}
+}
+
+func testTxManagerOuterRollbackDiscardsNested(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+
+	userUC := usecase.NewUserUsecase(stores.User, stores.PR, stores.Tx, usecase.NewLeastLoadedSelector())
+
+	err := stores.Tx.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		// составная операция переиспользует метод usecase: его транзакция становится вложенной
+		for _, id := range []string{testutils.User2ID, testutils.User3ID} {
+			if _, _, err := userUC.SetIsActive(ctx, id, false); err != nil {
+				return err
+			}
+		}
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	for _, id := range []string{testutils.User2ID, testutils.User3ID} {
+		user, err := stores.User.FetchByID(ctx, id)
+		require.NoError(t, err)
+		require.True(t, user.IsActive, "nested changes must be rolled back with the outer transaction")
+	}
+}
@@ -0,0 +1,401 @@
+package repotest
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testUserRepositoryUpsertInsertNew(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	u := &domain.User{
+		ID:       "u_new",
+		Name:     "New User",
+		TeamName: testutils.TestTeam,
+		IsActive: true,
+	}
+
+	err := repo.Upsert(ctx, u)
+	require.NoError(t, err)
+
+	dbUser, err := repo.FetchByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, u, dbUser)
+}
+
+func testUserRepositoryUpsertUpdateExisting(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	before, err := repo.FetchByID(ctx, testutils.User1ID)
+	require.NoError(t, err)
+
+	u := &domain.User{
+		ID:       testutils.User1ID,
+		Name:     "Updated Name",
+		TeamName: testutils.TestTeam,
+		IsActive: !before.IsActive,
+	}
+
+	err = repo.Upsert(ctx, u)
+	require.NoError(t, err)
+
+	dbUser, err := repo.FetchByID(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, u, dbUser)
+}
+
+func testUserRepositoryFetchByID(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	t.Run("success", func(t *testing.T) {
+		got, err := repo.FetchByID(ctx, testutils.User1ID)
+		require.NoError(t, err)
+
+		require.Equal(t, &domain.User{
+			ID:       testutils.User1ID,
+			Name:     "User One",
+			TeamName: testutils.TestTeam,
+			IsActive: true,
+		}, got)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		got, err := repo.FetchByID(ctx, "no_such_user")
+		require.Error(t, err)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		require.Nil(t, got)
+	})
+}
+
+func testUserRepositoryFetchByIDs(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	got, err := repo.FetchByIDs(ctx, []string{testutils.User2ID, "no_such_user", testutils.User1ID})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, testutils.User1ID, got[0].ID)
+	require.Equal(t, "User One", got[0].Name)
+	require.Equal(t, testutils.User2ID, got[1].ID)
+}
+
+func testUserRepositoryFetchByTeam(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	t.Run("test_team", func(t *testing.T) {
+		users, err := repo.FetchByTeam(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+
+		require.Len(t, users, 3)
+		ids := []string{users[0].ID, users[1].ID, users[2].ID}
+		require.ElementsMatch(t, []string{
+			testutils.User1ID,
+			testutils.User2ID,
+			testutils.User3ID,
+		}, ids)
+	})
+
+	t.Run("other_team", func(t *testing.T) {
+		users, err := repo.FetchByTeam(ctx, testutils.OtherTeam)
+		require.NoError(t, err)
+
+		require.Len(t, users, 3)
+		ids := []string{users[0].ID, users[1].ID, users[2].ID}
+		require.ElementsMatch(t, []string{
+			testutils.User4ID,
+			testutils.User5ID,
+			testutils.User6ID,
+		}, ids)
+	})
+}
+
+func testUserRepositoryFetchActiveByTeam(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	t.Run("no_exclude", func(t *testing.T) {
+		users, err := repo.FetchActiveByTeam(ctx, testutils.OtherTeam)
+		require.NoError(t, err)
+
+		require.Len(t, users, 2)
+		ids := []string{users[0].ID, users[1].ID}
+		require.ElementsMatch(t, []string{testutils.User4ID, testutils.User5ID}, ids)
+	})
+
+	t.Run("exclude_one", func(t *testing.T) {
+		users, err := repo.FetchActiveByTeam(ctx, testutils.OtherTeam, testutils.User5ID)
+		require.NoError(t, err)
+
+		require.Len(t, users, 1)
+		require.Equal(t, testutils.User4ID, users[0].ID)
+	})
+
+	t.Run("exclude_all", func(t *testing.T) {
+		users, err := repo.FetchActiveByTeam(ctx, testutils.OtherTeam, testutils.User4ID, testutils.User5ID)
+		require.NoError(t, err)
+		require.Len(t, users, 0)
+	})
+}
+
+func testUserRepositoryExists(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	t.Run("exists_true", func(t *testing.T) {
+		ok, err := repo.Exists(ctx, testutils.User1ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("exists_false", func(t *testing.T) {
+		ok, err := repo.Exists(ctx, "no_such_user")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func testUserRepositoryUpdateIsActive(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	t.Run("success_change_status", func(t *testing.T) {
+		repo := backend(t).User
+
+		updated, err := repo.UpdateIsActive(ctx, testutils.User1ID, false)
+		require.NoError(t, err)
+		require.Equal(t, &domain.User{
+			ID:       testutils.User1ID,
+			Name:     "User One",
+			TeamName: testutils.TestTeam,
+			IsActive: false,
+		}, updated)
+
+		dbUser, err := repo.FetchByID(ctx, testutils.User1ID)
+		require.NoError(t, err)
+		require.False(t, dbUser.IsActive)
+	})
+
+	t.Run("success_same_status", func(t *testing.T) {
+		repo := backend(t).User
+
+		updated, err := repo.UpdateIsActive(ctx, testutils.User1ID, true)
+		require.NoError(t, err)
+		require.Equal(t, &domain.User{
+			ID:       testutils.User1ID,
+			Name:     "User One",
+			TeamName: testutils.TestTeam,
+			IsActive: true,
+		}, updated)
+
+		dbUser, err := repo.FetchByID(ctx, testutils.User1ID)
+		require.NoError(t, err)
+		require.True(t, dbUser.IsActive)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		repo := backend(t).User
+
+		updated, err := repo.UpdateIsActive(ctx, "no_such_user", false)
+		require.Error(t, err)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		require.Nil(t, updated)
+
+		exists, err := repo.Exists(ctx, "no_such_user")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+}
+
+func testUserRepositoryDeactivateTeamMembers(t *testing.T, backend Backend) {
+	ctx := context.Background()
+
+	t.Run("listed_members_only", func(t *testing.T) {
+		repo := backend(t).User
+
+		ids, err := repo.DeactivateTeamMembers(ctx, testutils.TestTeam, []string{testutils.User2ID, testutils.User4ID}, false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{testutils.User2ID}, ids)
+
+		u4, err := repo.FetchByID(ctx, testutils.User4ID)
+		require.NoError(t, err)
+		require.True(t, u4.IsActive)
+	})
+
+	t.Run("all_except", func(t *testing.T) {
+		repo := backend(t).User
+
+		ids, err := repo.DeactivateTeamMembers(ctx, testutils.TestTeam, []string{testutils.User1ID}, true)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{testutils.User2ID, testutils.User3ID}, ids)
+
+		active, err := repo.FetchActiveByTeam(ctx, testutils.TestTeam)
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		require.Equal(t, testutils.User1ID, active[0].ID)
+	})
+
+	t.Run("all_except_nobody", func(t *testing.T) {
+		repo := backend(t).User
+
+		ids, err := repo.DeactivateTeamMembers(ctx, testutils.OtherTeam, nil, true)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{testutils.User4ID, testutils.User5ID, testutils.User6ID}, ids)
+	})
+}
+
+func testUserRepositoryRemoveFromTeam(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	ids, err := repo.RemoveFromTeam(ctx, testutils.TestTeam, []string{testutils.User2ID, testutils.User4ID})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{testutils.User2ID}, ids)
+
+	u2, err := repo.FetchByID(ctx, testutils.User2ID)
+	require.NoError(t, err)
+	require.Empty(t, u2.TeamName)
+
+	u4, err := repo.FetchByID(ctx, testutils.User4ID)
+	require.NoError(t, err)
+	require.Equal(t, testutils.OtherTeam, u4.TeamName)
+
+	members, err := repo.FetchByTeam(ctx, testutils.TestTeam)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+}
+
+func testUserRepositoryList(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	active := true
+	filter := domain.UserFilter{
+		TeamName:   testutils.OtherTeam,
+		IsActive:   &active,
+		PageParams: domain.PageParams{Sort: domain.SortByName, Order: domain.SortDesc, Limit: 1},
+	}
+
+	first, err := repo.List(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, first.Items, 1)
+	require.Equal(t, testutils.User4ID, first.Items[0].ID)
+
+	filter.Cursor = first.NextCursor
+	second, err := repo.List(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, second.Items, 1)
+	require.Equal(t, testutils.User5ID, second.Items[0].ID)
+	require.Empty(t, second.NextCursor)
+}
+
+func testUserRepositoryReviewLimits(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	stores := backend(t)
+	repo, teams := stores.User, stores.Team
+
+	limits, err := repo.FetchReviewLimits(ctx, []string{testutils.User1ID, testutils.User2ID})
+	require.NoError(t, err)
+	require.Empty(t, limits)
+
+	teamDefault := 4
+	_, err = teams.UpsertSettings(ctx, &domain.TeamSettings{
+		TeamName:              testutils.TestTeam,
+		MaxReviewers:          2,
+		DefaultMaxOpenReviews: &teamDefault,
+	})
+	require.NoError(t, err)
+
+	own := 1
+	user, err := repo.SetMaxOpenReviews(ctx, testutils.User1ID, &own)
+	require.NoError(t, err)
+	require.Equal(t, &own, user.MaxOpenReviews)
+
+	limits, err = repo.FetchReviewLimits(ctx, []string{testutils.User1ID, testutils.User2ID, testutils.User4ID})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{testutils.User1ID: 1, testutils.User2ID: 4}, limits)
+
+	user, err = repo.SetMaxOpenReviews(ctx, testutils.User1ID, nil)
+	require.NoError(t, err)
+	require.Nil(t, user.MaxOpenReviews)
+
+	_, err = repo.SetMaxOpenReviews(ctx, "no_such_user", &own)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testUserRepositoryAbsences(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	repo := backend(t).User
+
+	now := time.Now()
+	current, err := repo.AddAbsence(ctx, &domain.Absence{
+		UserID:     testutils.User2ID,
+		StartsAt:   now.Add(-time.Hour),
+		EndsAt:     now.Add(24 * time.Hour),
+		DelegateID: testutils.User3ID,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, current.ID)
+	require.Nil(t, current.HandedOffAt)
+
+	future, err := repo.AddAbsence(ctx, &domain.Absence{
+		UserID:   testutils.User3ID,
+		StartsAt: now.Add(48 * time.Hour),
+		EndsAt:   now.Add(72 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Empty(t, future.DelegateID)
+
+	active, err := repo.FetchActiveByTeam(ctx, testutils.TestTeam)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(active))
+	for _, u := range active {
+		ids = append(ids, u.ID)
+	}
+	require.ElementsMatch(t, []string{testutils.User1ID, testutils.User3ID}, ids)
+
+	cur, err := repo.FetchCurrentAbsence(ctx, testutils.User2ID)
+	require.NoError(t, err)
+	require.Equal(t, current.ID, cur.ID)
+	_, err = repo.FetchCurrentAbsence(ctx, testutils.User3ID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	overlap, err := repo.HasOverlappingAbsence(ctx, testutils.User2ID, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, overlap)
+	overlap, err = repo.HasOverlappingAbsence(ctx, testutils.User2ID, now.Add(24*time.Hour), now.Add(48*time.Hour))
+	require.NoError(t, err)
+	require.False(t, overlap)
+
+	due, err := repo.ListDueHandoffs(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, current.ID, due[0].ID)
+
+	handedOff, err := repo.MarkHandedOff(ctx, current.ID)
+	require.NoError(t, err)
+	require.NotNil(t, handedOff.HandedOffAt)
+
+	due, err = repo.ListDueHandoffs(ctx, testutils.User2ID)
+	require.NoError(t, err)
+	require.Empty(t, due)
+
+	list, err := repo.ListAbsences(ctx, testutils.User3ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, future.ID, list[0].ID)
+
+	deleted, err := repo.DeleteAbsence(ctx, future.ID)
+	require.NoError(t, err)
+	require.Equal(t, future.ID, deleted.ID)
+
+	_, err = repo.DeleteAbsence(ctx, future.ID)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/repository/repotest"
+	"testing"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Stores {
+		db := openTestDB(t)
+
+		return &repotest.Stores{
+			User:  NewUserRepository(db),
+			Team:  NewTeamRepository(db),
+			PR:    NewPRRepository(db),
+			Stats: NewStatsRepository(db),
+			Tx:    NewTxManager(db),
+		}
+	})
+}
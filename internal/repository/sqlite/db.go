@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+
+	modernc "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Querier — общее подмножество *sql.DB и *sql.Tx, на котором работают репозитории
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Open открывает файл базы SQLite (создает, если его нет) и применяет к нему миграции.
+// Каждое соединение включает внешние ключи и ждет освобождения блокировки до 5 секунд; пишущие транзакции
+// начинаются с BEGIN IMMEDIATE, чтобы не получать SQLITE_BUSY при повышении блокировки посреди транзакции.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite path is empty")
+	}
+
+	dsn := "file:" + path + "?_txlock=immediate" +
+		"&_pragma=foreign_keys(1)" +
+		"&_pragma=busy_timeout(5000)" +
+		"&_pragma=journal_mode(WAL)" +
+		"&_pragma=synchronous(NORMAL)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(10)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	return db, nil
+}
+
+// migrate применяет еще не примененные миграции по порядку номеров. Номер последней примененной миграции
+// хранится в PRAGMA user_version, каждая миграция выполняется в своей транзакции.
+func migrate(ctx context.Context, db *sql.DB) error {
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	slices.Sort(files)
+
+	var current int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&current); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(strings.TrimPrefix(file, "migrations/"), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("%s: migration name must start with a number", file)
+		}
+		if version <= current {
+			continue
+		}
+
+		script, err := migrations.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := applyMigration(ctx, db, version, string(script)); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		current = version
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, version int, script string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// scanner — общее у *sql.Row и *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// collectRows читает все строки выборки функцией scan; пустая выборка дает пустой, а не nil срез
+func collectRows[T any](rows *sql.Rows, scan func(r scanner) (T, error)) ([]T, error) {
+	items := []T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// errorCode возвращает расширенный код ошибки SQLite
+func errorCode(err error) (int, bool) {
+	var sqliteErr *modernc.Error
+	if !errors.As(err, &sqliteErr) {
+		return 0, false
+	}
+	return sqliteErr.Code(), true
+}
+
+// atomically выполняет несколько запросов fn целиком или никак: на соединении из пула — в собственной транзакции,
+// внутри транзакции — в savepoint. Postgres-реализация укладывает такие операции в один запрос.
+func atomically(ctx context.Context, q Querier, fn func(q Querier) error) error {
+	if db, ok := q.(*sql.DB); ok {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if _, err := q.ExecContext(ctx, `SAVEPOINT repo_op`); err != nil {
+		return err
+	}
+	if err := fn(q); err != nil {
+		rollbackCtx := context.WithoutCancel(ctx)
+		_, _ = q.ExecContext(rollbackCtx, `ROLLBACK TO repo_op`)
+		_, _ = q.ExecContext(rollbackCtx, `RELEASE repo_op`)
+		return err
+	}
+
+	_, err := q.ExecContext(ctx, `RELEASE repo_op`)
+	return err
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDB открывает пустую базу во временном каталоге теста и загружает в нее фикстуры
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, testutils.LoadFixtures(ctx, newRepos(db)))
+	return db
+}
+
+func TestOpen_ReopenKeepsDataAndSkipsAppliedMigrations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "reopen.db")
+
+	db, err := Open(ctx, path)
+	require.NoError(t, err)
+	require.NoError(t, NewTeamRepository(db).Create(ctx, "persisted_team"))
+	require.NoError(t, db.Close())
+
+	db, err = Open(ctx, path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	exists, err := NewTeamRepository(db).Exists(ctx, "persisted_team")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	files, err := migrations.ReadDir("migrations")
+	require.NoError(t, err)
+
+	var version int
+	require.NoError(t, db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version))
+	require.Equal(t, len(files), version)
+}
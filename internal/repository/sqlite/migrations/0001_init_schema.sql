@@ -0,0 +1,122 @@
+-- Схема повторяет итог миграций Postgres из migrations/. Время хранится текстом в UTC
+-- в формате 2006-01-02T15:04:05.000Z: строки такой фиксированной ширины сравниваются в хронологическом порядке.
+
+CREATE TABLE teams
+(
+    name        TEXT PRIMARY KEY,
+    archived_at TEXT
+);
+
+CREATE TABLE users
+(
+    id               TEXT PRIMARY KEY,
+    name             TEXT    NOT NULL,
+    team_name        TEXT REFERENCES teams (name) ON UPDATE CASCADE,
+    is_active        INTEGER NOT NULL,
+    max_open_reviews INTEGER CHECK (max_open_reviews >= 0)
+);
+
+CREATE INDEX idx_users_team_name ON users (team_name);
+CREATE INDEX idx_users_name_id ON users (name, id);
+
+CREATE TABLE pull_requests
+(
+    id                TEXT PRIMARY KEY,
+    name              TEXT    NOT NULL,
+    author_id         TEXT REFERENCES users (id),
+    status            TEXT    NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED', 'CLOSED')),
+    is_draft          INTEGER NOT NULL DEFAULT 0,
+    created_at        TEXT    NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    merged_at         TEXT,
+    closed_at         TEXT,
+    merge_override_by TEXT,
+    reassign_count    INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_pull_requests_created_at_id ON pull_requests (created_at, id);
+CREATE INDEX idx_pull_requests_status_created_at_id ON pull_requests (status, created_at, id);
+CREATE INDEX idx_pull_requests_merged_at ON pull_requests (merged_at);
+CREATE INDEX idx_pull_requests_author_id ON pull_requests (author_id);
+
+CREATE TABLE pr_reviewers
+(
+    pr_id            TEXT NOT NULL REFERENCES pull_requests (id) ON DELETE CASCADE,
+    user_id          TEXT NOT NULL REFERENCES users (id),
+    state            TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'DISMISSED')),
+    state_updated_at TEXT,
+    PRIMARY KEY (pr_id, user_id)
+);
+
+CREATE INDEX idx_pr_reviewers_user_id ON pr_reviewers (user_id);
+
+CREATE TABLE team_review_cursors
+(
+    team_name    TEXT PRIMARY KEY REFERENCES teams (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    last_user_id TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE review_assignment_events
+(
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    pr_id            TEXT NOT NULL REFERENCES pull_requests (id),
+    event_type       TEXT NOT NULL CHECK (event_type IN ('ASSIGNED', 'REASSIGNED', 'UNASSIGNED')),
+    user_id          TEXT REFERENCES users (id),
+    previous_user_id TEXT REFERENCES users (id),
+    actor            TEXT NOT NULL,
+    reason           TEXT NOT NULL,
+    created_at       TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX idx_review_assignment_events_pr_id ON review_assignment_events (pr_id, id);
+
+CREATE TRIGGER trg_review_assignment_events_no_update
+    BEFORE UPDATE
+    ON review_assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'review_assignment_events is append-only');
+END;
+
+CREATE TRIGGER trg_review_assignment_events_no_delete
+    BEFORE DELETE
+    ON review_assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'review_assignment_events is append-only');
+END;
+
+CREATE TABLE team_settings
+(
+    team_name                TEXT PRIMARY KEY REFERENCES teams (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    min_reviewers            INTEGER NOT NULL DEFAULT 0,
+    max_reviewers            INTEGER NOT NULL DEFAULT 2,
+    default_max_open_reviews INTEGER CHECK (default_max_open_reviews >= 0),
+    CHECK (min_reviewers >= 0 AND min_reviewers <= max_reviewers)
+);
+
+CREATE TABLE team_fallback_teams
+(
+    team_name          TEXT    NOT NULL REFERENCES teams (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    fallback_team_name TEXT    NOT NULL REFERENCES teams (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    position           INTEGER NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    UNIQUE (team_name, position),
+    CHECK (fallback_team_name <> team_name)
+);
+
+CREATE TABLE user_absences
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id       TEXT NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    starts_at     TEXT NOT NULL,
+    ends_at       TEXT NOT NULL,
+    delegate_id   TEXT REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    handed_off_at TEXT,
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    CHECK (ends_at > starts_at),
+    CHECK (delegate_id <> user_id)
+);
+
+CREATE INDEX idx_user_absences_user_id_ends_at ON user_absences (user_id, ends_at);
+
+CREATE INDEX idx_user_absences_pending_handoff ON user_absences (starts_at)
+    WHERE delegate_id IS NOT NULL AND handed_off_at IS NULL;
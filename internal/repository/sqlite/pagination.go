@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// sortColumn описывает колонку сортировки для keyset-пагинации
+type sortColumn struct {
+	expr   string // SQL-выражение сортировки
+	unique bool   // значение уникально, добивка по id не нужна
+}
+
+// pageCursor — содержимое непрозрачного курсора: сортировка и порядок, для которых он выдан, значение сортировки
+// и id последней строки страницы
+type pageCursor struct {
+	Sort  string           `json:"s"`
+	Order domain.SortOrder `json:"o"`
+	Key   string           `json:"k"`
+	ID    string           `json:"id"`
+}
+
+func encodeCursor(sort string, order domain.SortOrder, key, id string) string {
+	raw, _ := json.Marshal(pageCursor{Sort: sort, Order: order, Key: key, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor разбирает курсор и проверяет, что он выдан для сортировки sort в порядке order
+func decodeCursor(s, sort string, order domain.SortOrder) (*pageCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", domain.ErrInvalidInput)
+	}
+
+	var cur pageCursor
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return nil, fmt.Errorf("malformed cursor: %w", domain.ErrInvalidInput)
+	}
+	if cur.Sort != sort || cur.Order != order {
+		return nil, fmt.Errorf("cursor was issued for sort %q %s: %w", cur.Sort, cur.Order, domain.ErrInvalidInput)
+	}
+
+	return &cur, nil
+}
+
+// keyset строит условие «после курсора» и ORDER BY для колонки col с добивкой по idExpr.
+// argPos — номер первого свободного позиционного параметра; возвращаемые args нужно добавить к запросу в этом порядке.
+// Все значения сортировки — строки (время хранится в timeLayout), поэтому курсор сравнивается без приведения типов.
+func keyset(col sortColumn, idExpr string, order domain.SortOrder, cur *pageCursor, argPos int) (cond, orderBy string, args []any) {
+	cmp, dir := ">", "ASC"
+	if order == domain.SortDesc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if col.unique {
+		orderBy = fmt.Sprintf("%s %s", col.expr, dir)
+	} else {
+		orderBy = fmt.Sprintf("%s %s, %s %s", col.expr, dir, idExpr, dir)
+	}
+
+	if cur == nil {
+		return "TRUE", orderBy, nil
+	}
+
+	if col.unique {
+		return fmt.Sprintf("%s %s $%d", col.expr, cmp, argPos), orderBy, []any{cur.Key}
+	}
+
+	cond = fmt.Sprintf("(%s, %s) %s ($%d, $%d)", col.expr, idExpr, cmp, argPos, argPos+1)
+	return cond, orderBy, []any{cur.Key, cur.ID}
+}
+
+// lookupSort возвращает колонку сортировки из допустимых для списка
+func lookupSort(columns map[string]sortColumn, sort string) (sortColumn, error) {
+	col, ok := columns[sort]
+	if !ok {
+		return sortColumn{}, fmt.Errorf("unsupported sort %q: %w", sort, domain.ErrInvalidInput)
+	}
+	return col, nil
+}
+
+// newPage обрезает выборку из limit+1 строк до limit и, если строк было больше, строит курсор по последней строке
+func newPage[T any](items []T, limit int, sort string, order domain.SortOrder, key func(T) (string, string)) *domain.Page[T] {
+	page := &domain.Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		k, id := key(page.Items[limit-1])
+		page.NextCursor = encodeCursor(sort, order, k, id)
+	}
+	return page
+}
@@ -0,0 +1,704 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var prSortColumns = map[string]sortColumn{
+	domain.SortByCreatedAt: {expr: "p.created_at"},
+	domain.SortByID:        {expr: "p.id", unique: true},
+}
+
+const prColumns = `id, name, author_id, status, is_draft, created_at, merged_at, closed_at, COALESCE(merge_override_by, '')`
+
+type prRepository struct {
+	q Querier
+}
+
+func NewPRRepository(q Querier) domain.PRRepository {
+	return &prRepository{q: q}
+}
+
+func scanPR(r scanner, extra ...any) (*domain.PullRequest, error) {
+	var pr domain.PullRequest
+	dest := []any{&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.IsDraft,
+		nullTimestamp{&pr.CreatedAt}, nullTimestamp{&pr.MergedAt}, nullTimestamp{&pr.ClosedAt}, &pr.MergeOverrideBy}
+	if err := r.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (p *prRepository) Create(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
+	const q = `
+		INSERT INTO pull_requests (id, name, author_id, status, is_draft)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, name, author_id, status, is_draft, created_at;
+	`
+
+	var created domain.PullRequest
+	err := p.q.QueryRowContext(ctx, q, pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.IsDraft).Scan(
+		&created.ID, &created.Name,
+		&created.AuthorID, &created.Status,
+		&created.IsDraft, nullTimestamp{&created.CreatedAt})
+	if err != nil {
+		if code, ok := errorCode(err); ok {
+			switch code {
+			case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+				return nil, domain.ErrAlreadyExists
+			case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+				return nil, domain.ErrNotFound
+			}
+		}
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (p *prRepository) FetchByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		SELECT ` + prColumns + `
+		FROM pull_requests
+		WHERE id = $1;
+	`
+
+	return p.fetchPR(ctx, q, prID)
+}
+
+// FetchByIDForUpdate читает PR так же, как FetchByID: пишущая транзакция SQLite держит блокировку записи всей базы
+// с начала и до конца, поэтому параллельная транзакция не изменит строку, пока эта не завершится
+func (p *prRepository) FetchByIDForUpdate(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return p.FetchByID(ctx, prID)
+}
+
+func (p *prRepository) List(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+	col, err := lookupSort(prSortColumns, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decodeCursor(filter.Cursor, filter.Sort, filter.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, orderBy, keyArgs := keyset(col, "p.id", filter.Order, cur, 8)
+	q := fmt.Sprintf(`
+		SELECT p.id, p.name, p.author_id, p.status, p.is_draft, p.created_at, p.merged_at, p.closed_at,
+		       COALESCE(p.merge_override_by, ''),
+		       (SELECT json_group_array(r.user_id ORDER BY r.user_id) FROM pr_reviewers r WHERE r.pr_id = p.id)
+		FROM pull_requests p
+		LEFT JOIN users a ON a.id = p.author_id
+		WHERE ($1 = '' OR p.status = $1)
+		  AND ($2 = '' OR p.author_id = $2)
+		  AND ($3 = '' OR EXISTS (
+		          SELECT 1
+		          FROM pr_reviewers x
+		          WHERE x.pr_id = p.id
+		            AND x.user_id = $3
+		      ))
+		  AND ($4 = '' OR a.team_name = $4)
+		  AND ($5 IS NULL OR p.created_at >= $5)
+		  AND ($6 IS NULL OR p.created_at < $6)
+		  AND %s
+		ORDER BY %s
+		LIMIT $7;
+	`, cond, orderBy)
+
+	args := append([]any{
+		string(filter.Status), filter.AuthorID, filter.ReviewerID, filter.TeamName,
+		nullableTime(filter.CreatedFrom), nullableTime(filter.CreatedTo), filter.Limit + 1,
+	}, keyArgs...)
+	rows, err := p.q.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs, err := collectRows(rows, func(r scanner) (*domain.PullRequest, error) {
+		var reviewers []string
+		pr, err := scanPR(r, stringList{&reviewers})
+		if err != nil {
+			return nil, err
+		}
+		pr.Reviewers = reviewers
+		return pr, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(prs, filter.Limit, filter.Sort, filter.Order, func(pr *domain.PullRequest) (string, string) {
+		if filter.Sort == domain.SortByCreatedAt {
+			return formatTime(*pr.CreatedAt), pr.ID
+		}
+		return pr.ID, pr.ID
+	}), nil
+}
+
+func (p *prRepository) UpdateStatusMerged(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
+	const q = `
+		UPDATE pull_requests
+			SET status = 'MERGED',
+			    merged_at = COALESCE(merged_at, ` + sqlNow + `),
+			    merge_override_by = COALESCE(merge_override_by, NULLIF($2, ''))
+		WHERE id = $1
+		RETURNING ` + prColumns + `;
+	`
+
+	return p.fetchPR(ctx, q, prID, overrideBy)
+}
+
+// UpdateStatusClosed закрывает PR без merge; повторное закрытие не меняет closed_at
+func (p *prRepository) UpdateStatusClosed(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		UPDATE pull_requests
+			SET status = 'CLOSED',
+			    closed_at = COALESCE(closed_at, ` + sqlNow + `)
+		WHERE id = $1
+		RETURNING ` + prColumns + `;
+	`
+
+	return p.fetchPR(ctx, q, prID)
+}
+
+// UpdateStatusOpen переоткрывает закрытый PR
+func (p *prRepository) UpdateStatusOpen(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		UPDATE pull_requests
+			SET status = 'OPEN',
+			    closed_at = NULL
+		WHERE id = $1
+		RETURNING ` + prColumns + `;
+	`
+
+	return p.fetchPR(ctx, q, prID)
+}
+
+// UpdateReady снимает с PR признак черновика
+func (p *prRepository) UpdateReady(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	const q = `
+		UPDATE pull_requests
+			SET is_draft = FALSE
+		WHERE id = $1
+		RETURNING ` + prColumns + `;
+	`
+
+	return p.fetchPR(ctx, q, prID)
+}
+
+// fetchPR выполняет запрос, возвращающий одну строку PR в порядке prColumns
+func (p *prRepository) fetchPR(ctx context.Context, q string, args ...any) (*domain.PullRequest, error) {
+	pr, err := scanPR(p.q.QueryRowContext(ctx, q, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (p *prRepository) ListReviewableByUserID(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+	const q = `
+		SELECT p.id, p.name, p.author_id, p.status, p.is_draft, p.created_at, p.merged_at, p.closed_at, COALESCE(p.merge_override_by, '')
+		FROM pr_reviewers r
+		JOIN pull_requests p ON p.id = r.pr_id
+		WHERE r.user_id = $1
+			AND p.status <> 'CLOSED';
+	`
+
+	rows, err := p.q.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, func(r scanner) (*domain.PullRequest, error) { return scanPR(r) })
+}
+
+// ListReviewableByUserIDAndState возвращает незакрытые PR, где решение пользователя находится в состоянии state
+func (p *prRepository) ListReviewableByUserIDAndState(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error) {
+	const q = `
+		SELECT p.id, p.name, p.author_id, p.status, p.is_draft, p.created_at, p.merged_at, p.closed_at, COALESCE(p.merge_override_by, '')
+		FROM pr_reviewers r
+		JOIN pull_requests p ON p.id = r.pr_id
+		WHERE r.user_id = $1
+			AND r.state = $2
+			AND p.status = 'OPEN'
+		ORDER BY p.created_at, p.id;
+	`
+
+	rows, err := p.q.QueryContext(ctx, q, userID, state)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, func(r scanner) (*domain.PullRequest, error) { return scanPR(r) })
+}
+
+func (p *prRepository) ListReviewers(ctx context.Context, prID string) ([]string, error) {
+	const q = `
+		SELECT user_id
+		FROM pr_reviewers
+		WHERE pr_id = $1;
+	`
+
+	rows, err := p.q.QueryContext(ctx, q, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanID)
+}
+
+func (p *prRepository) ListReviewerStates(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+	const q = `
+		SELECT user_id, state, state_updated_at
+		FROM pr_reviewers
+		WHERE pr_id = $1
+		ORDER BY state_updated_at NULLS LAST, user_id;
+	`
+
+	rows, err := p.q.QueryContext(ctx, q, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, func(r scanner) (*domain.ReviewerState, error) {
+		var st domain.ReviewerState
+		if err := r.Scan(&st.UserID, &st.State, nullTimestamp{&st.UpdatedAt}); err != nil {
+			return nil, err
+		}
+		return &st, nil
+	})
+}
+
+func (p *prRepository) UpdateReviewState(ctx context.Context, prID, userID string, state domain.ReviewState) error {
+	const q = `
+		UPDATE pr_reviewers
+			SET state = $3,
+			    state_updated_at = ` + sqlNow + `
+		WHERE pr_id = $1 AND user_id = $2;
+	`
+
+	res, err := p.q.ExecContext(ctx, q, prID, userID, state)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrNotAssigned
+	}
+
+	return nil
+}
+
+func (p *prRepository) InsertReviewer(ctx context.Context, prID, userID string) error {
+	const q = `
+		INSERT INTO pr_reviewers (pr_id, user_id)
+		VALUES ($1, $2);
+	`
+
+	_, err := p.q.ExecContext(ctx, q, prID, userID)
+	return conflictError(err)
+}
+
+func (p *prRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+	const replaceQ = `
+		UPDATE pr_reviewers
+		SET user_id = $1,
+		    state = 'PENDING',
+		    state_updated_at = NULL
+		WHERE pr_id = $2 AND user_id = $3;
+	`
+	const countQ = `
+		UPDATE pull_requests
+		SET reassign_count = reassign_count + 1
+		WHERE id = $1;
+	`
+
+	err := atomically(ctx, p.q, func(q Querier) error {
+		res, err := q.ExecContext(ctx, replaceQ, newReviewerID, prID, oldReviewerID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+
+		_, err = q.ExecContext(ctx, countQ, prID)
+		return err
+	})
+	return conflictError(err)
+}
+
+func (p *prRepository) RemoveReviewer(ctx context.Context, prID, userID string) error {
+	const q = `
+		DELETE FROM pr_reviewers
+		WHERE pr_id = $1 AND user_id = $2;
+	`
+
+	_, err := p.q.ExecContext(ctx, q, prID, userID)
+	return err
+}
+
+func (p *prRepository) ReviewerAssigned(ctx context.Context, prID, userID string) (bool, error) {
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM pr_reviewers
+			WHERE pr_id = $1
+				AND user_id = $2
+		);
+	`
+
+	var exists bool
+	err := p.q.QueryRowContext(ctx, q, prID, userID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (p *prRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const q = `
+		SELECT r.user_id, COUNT(*)
+		FROM pr_reviewers r
+		JOIN pull_requests p ON p.id = r.pr_id
+		WHERE r.user_id IN (SELECT value FROM json_each($1))
+			AND p.status = 'OPEN'
+		GROUP BY r.user_id;
+	`
+
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	rows, err := p.q.QueryContext(ctx, q, jsonList(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			count  int
+		)
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ReassignOpenReviews заменяет перечисленных пользователей на всех OPEN PR по тем же правилам, что и Postgres-реализация:
+// участники команды один раз упорядочиваются по стратегии, и замены раздаются по кругу в этом порядке; для weighted
+// каждая замена выбирается случайно пропорционально весу. Замены подбираются одним запросом, а применяются и пишутся
+// в журнал тремя пакетными запросами в той же транзакции: SQLite не позволяет изменять данные внутри WITH.
+func (p *prRepository) ReassignOpenReviews(ctx context.Context, req domain.BulkReassign) ([]*domain.ReviewReassignment, error) {
+	const matchQ = `
+		WITH affected AS (
+			SELECT r.pr_id,
+			       r.user_id AS old_user_id,
+			       u.team_name,
+			       p.author_id,
+			       row_number() OVER (PARTITION BY u.team_name ORDER BY r.pr_id, r.user_id) AS seq,
+			       row_number() OVER (PARTITION BY r.pr_id, u.team_name ORDER BY r.user_id) AS slot
+			FROM pr_reviewers r
+			JOIN pull_requests p ON p.id = r.pr_id
+			JOIN users u ON u.id = r.user_id
+			WHERE r.user_id IN (SELECT value FROM json_each($1))
+			  AND p.status = 'OPEN'
+		),
+		load AS (
+			SELECT r.user_id, COUNT(*) AS open_reviews
+			FROM pr_reviewers r
+			JOIN pull_requests p ON p.id = r.pr_id
+			WHERE p.status = 'OPEN'
+			GROUP BY r.user_id
+		),
+		pool AS (
+			SELECT c.team_name,
+			       c.id AS user_id,
+			       COALESCE(l.open_reviews, 0) AS open_reviews,
+			       COALESCE(c.max_open_reviews, cs.default_max_open_reviews) AS capacity,
+			       max(COALESCE(CAST(w.value AS INTEGER), 1), 1) AS weight,
+			       row_number() OVER (
+			           PARTITION BY c.team_name
+			           ORDER BY CASE WHEN $2 = 'least_loaded' THEN COALESCE(l.open_reviews, 0) ELSE 0 END,
+			                    CASE WHEN $2 = 'round_robin' THEN c.id ELSE '' END,
+			                    random()
+			       ) - 1 AS pos,
+			       COUNT(*) OVER (PARTITION BY c.team_name) AS size,
+			       CASE WHEN $2 = 'round_robin'
+			            THEN SUM(CASE WHEN c.id <= $4 THEN 1 ELSE 0 END) OVER (PARTITION BY c.team_name)
+			            ELSE 0
+			       END AS start
+			FROM users c
+			JOIN teams ct ON ct.name = c.team_name
+			             AND ct.archived_at IS NULL
+			LEFT JOIN team_settings cs ON cs.team_name = c.team_name
+			LEFT JOIN load l ON l.user_id = c.id
+			LEFT JOIN json_each($3) w ON w.key = c.id
+			WHERE c.team_name IN (SELECT team_name FROM affected)
+			  AND c.is_active
+			  AND c.id NOT IN (SELECT value FROM json_each($1))
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM user_absences ab
+				WHERE ab.user_id = c.id
+				  AND ab.starts_at <= ` + sqlNow + `
+				  AND ab.ends_at > ` + sqlNow + `
+			)
+			  AND (COALESCE(c.max_open_reviews, cs.default_max_open_reviews) IS NULL
+			       OR COALESCE(l.open_reviews, 0) < COALESCE(c.max_open_reviews, cs.default_max_open_reviews))
+		),
+		candidates AS (
+			-- PR с base заменяемыми раньше него в команде начинает обход пула с позиции base
+			SELECT a.pr_id,
+			       a.team_name,
+			       c.user_id,
+			       c.open_reviews,
+			       c.capacity,
+			       row_number() OVER (
+			           PARTITION BY a.pr_id, a.team_name
+			           ORDER BY CASE WHEN $2 = 'weighted' THEN -ln((abs(random() % 1000000) + 1) / 1000000.0) / c.weight
+			                         ELSE ((c.pos - c.start - a.base) % c.size + c.size) % c.size
+			                    END
+			       ) AS slot
+			FROM (
+				SELECT pr_id, team_name, author_id, MIN(seq) - 1 AS base
+				FROM affected
+				GROUP BY pr_id, team_name, author_id
+			) a
+			JOIN pool c ON c.team_name = a.team_name
+			           AND c.user_id <> a.author_id
+			WHERE NOT EXISTS (
+				SELECT 1
+				FROM pr_reviewers x
+				WHERE x.pr_id = a.pr_id
+				  AND x.user_id = c.user_id
+			)
+		),
+		proposed AS (
+			SELECT a.pr_id,
+			       a.old_user_id,
+			       c.user_id AS new_user_id,
+			       c.open_reviews,
+			       c.capacity,
+			       row_number() OVER (PARTITION BY c.user_id ORDER BY a.team_name, a.seq) AS taken
+			FROM affected a
+			LEFT JOIN candidates c ON c.pr_id = a.pr_id
+			                      AND c.team_name = a.team_name
+			                      AND c.slot = a.slot
+		)
+		-- один кандидат может выпасть на несколько PR: сверх лимита замены не назначаются
+		SELECT pr_id,
+		       old_user_id,
+		       CASE WHEN capacity IS NULL OR open_reviews + taken <= capacity THEN COALESCE(new_user_id, '') ELSE '' END
+		FROM proposed
+		ORDER BY pr_id, old_user_id;
+	`
+	const replaceQ = `
+		UPDATE pr_reviewers
+		SET user_id = m.value ->> 'new_user_id',
+		    state = 'PENDING',
+		    state_updated_at = NULL
+		FROM json_each($1) AS m
+		WHERE pr_reviewers.pr_id = m.value ->> 'pr_id'
+		  AND pr_reviewers.user_id = m.value ->> 'old_user_id';
+	`
+	const countQ = `
+		UPDATE pull_requests
+		SET reassign_count = reassign_count + c.replaced
+		FROM (
+			SELECT m.value ->> 'pr_id' AS pr_id, COUNT(*) AS replaced
+			FROM json_each($1) AS m
+			GROUP BY 1
+		) AS c
+		WHERE pull_requests.id = c.pr_id;
+	`
+
+	if len(req.UserIDs) == 0 {
+		return nil, nil
+	}
+
+	weights := req.Weights
+	if weights == nil {
+		weights = map[string]int{}
+	}
+	rawWeights, err := json.Marshal(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*domain.ReviewReassignment
+	err = atomically(ctx, p.q, func(q Querier) error {
+		rows, err := q.QueryContext(ctx, matchQ, jsonList(req.UserIDs), string(req.Strategy), string(rawWeights), req.Cursor)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		res, err = collectRows(rows, func(r scanner) (*domain.ReviewReassignment, error) {
+			var ra domain.ReviewReassignment
+			if err := r.Scan(&ra.PRID, &ra.OldReviewerID, &ra.NewReviewerID); err != nil {
+				return nil, err
+			}
+			return &ra, nil
+		})
+		if err != nil {
+			return err
+		}
+		rows.Close()
+
+		var (
+			replaced []reassignmentRow
+			events   []*domain.AssignmentEvent
+		)
+		for _, ra := range res {
+			if ra.NewReviewerID == "" {
+				continue
+			}
+			replaced = append(replaced, reassignmentRow{PRID: ra.PRID, OldUserID: ra.OldReviewerID, NewUserID: ra.NewReviewerID})
+			events = append(events, &domain.AssignmentEvent{
+				PRID:           ra.PRID,
+				Type:           domain.EventReassigned,
+				UserID:         ra.NewReviewerID,
+				PreviousUserID: ra.OldReviewerID,
+				Actor:          req.Actor,
+				Reason:         req.Reason,
+			})
+		}
+		if len(replaced) == 0 {
+			return nil
+		}
+
+		raw, err := json.Marshal(replaced)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, replaceQ, string(raw)); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, countQ, string(raw)); err != nil {
+			return err
+		}
+		return NewPRRepository(q).AddAssignmentEvents(ctx, events...)
+	})
+	if err != nil {
+		return nil, conflictError(err)
+	}
+
+	return res, nil
+}
+
+// reassignmentRow — замена ревьювера в JSON-массиве, который ReassignOpenReviews применяет через json_each
+type reassignmentRow struct {
+	PRID      string `json:"pr_id"`
+	OldUserID string `json:"old_user_id"`
+	NewUserID string `json:"new_user_id"`
+}
+
+// assignmentEventRow — запись журнала в JSON-массиве, который AddAssignmentEvents разворачивает через json_each
+type assignmentEventRow struct {
+	PRID           string `json:"pr_id"`
+	Type           string `json:"event_type"`
+	UserID         string `json:"user_id"`
+	PreviousUserID string `json:"previous_user_id"`
+	Actor          string `json:"actor"`
+	Reason         string `json:"reason"`
+}
+
+// AddAssignmentEvents добавляет записи в журнал назначений одним запросом
+func (p *prRepository) AddAssignmentEvents(ctx context.Context, events ...*domain.AssignmentEvent) error {
+	const q = `
+		INSERT INTO review_assignment_events (pr_id, event_type, user_id, previous_user_id, actor, reason)
+		SELECT e.value ->> 'pr_id',
+		       e.value ->> 'event_type',
+		       NULLIF(e.value ->> 'user_id', ''),
+		       NULLIF(e.value ->> 'previous_user_id', ''),
+		       e.value ->> 'actor',
+		       e.value ->> 'reason'
+		FROM json_each($1) AS e
+		ORDER BY e.key;
+	`
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]assignmentEventRow, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, assignmentEventRow{
+			PRID:           e.PRID,
+			Type:           string(e.Type),
+			UserID:         e.UserID,
+			PreviousUserID: e.PreviousUserID,
+			Actor:          e.Actor,
+			Reason:         e.Reason,
+		})
+	}
+	raw, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.q.ExecContext(ctx, q, string(raw))
+	return err
+}
+
+func (p *prRepository) ListAssignmentEvents(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+	const q = `
+		SELECT id, pr_id, event_type, COALESCE(user_id, ''), COALESCE(previous_user_id, ''), actor, reason, created_at
+		FROM review_assignment_events
+		WHERE pr_id = $1
+		ORDER BY id;
+	`
+
+	rows, err := p.q.QueryContext(ctx, q, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, func(r scanner) (*domain.AssignmentEvent, error) {
+		var e domain.AssignmentEvent
+		if err := r.Scan(&e.ID, &e.PRID, &e.Type, &e.UserID, &e.PreviousUserID, &e.Actor, &e.Reason, timestamp{&e.CreatedAt}); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	})
+}
+
+// conflictError переводит нарушение уникальности назначения и занятость базы, возникающие при гонке
+// параллельных транзакций, в domain.ErrConcurrentUpdate. Исходная ошибка остается в цепочке, чтобы TxManager
+// мог повторить транзакцию.
+func conflictError(err error) error {
+	code, ok := errorCode(err)
+	if ok && (code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || code&0xff == sqlite3.SQLITE_BUSY) {
+		return fmt.Errorf("%w: %w", domain.ErrConcurrentUpdate, err)
+	}
+	return err
+}
@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"avito-backend-trainee-autumn-2025/testutils"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRRepository_AssignmentEventsAreAppendOnly(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewPRRepository(db)
+
+	err := repo.AddAssignmentEvents(ctx,
+		&domain.AssignmentEvent{PRID: testutils.PR1ID, Type: domain.EventAssigned, UserID: testutils.User2ID, Actor: domain.SystemActor, Reason: domain.ReasonPRCreated},
+	)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `UPDATE review_assignment_events SET actor = 'forged'`)
+	require.Error(t, err)
+
+	_, err = db.ExecContext(ctx, `DELETE FROM review_assignment_events`)
+	require.Error(t, err)
+}
@@ -0,0 +1,301 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var teamSortColumns = map[string]sortColumn{
+	domain.SortByName: {expr: "name", unique: true},
+}
+
+type teamRepository struct {
+	q Querier
+}
+
+func NewTeamRepository(q Querier) domain.TeamRepository {
+	return &teamRepository{q: q}
+}
+
+func scanTeam(r scanner) (*domain.Team, error) {
+	var team domain.Team
+	if err := r.Scan(&team.Name, nullTimestamp{&team.ArchivedAt}); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (tr *teamRepository) Create(ctx context.Context, teamName string) error {
+	const q = `
+		INSERT INTO teams (name)
+		VALUES ($1)
+	`
+
+	_, err := tr.q.ExecContext(ctx, q, teamName)
+	if err != nil {
+		if code, ok := errorCode(err); ok && code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+			return domain.ErrAlreadyExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (tr *teamRepository) Exists(ctx context.Context, teamName string) (bool, error) {
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM teams
+			WHERE name = $1
+		);
+	`
+
+	var exists bool
+	err := tr.q.QueryRowContext(ctx, q, teamName).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (tr *teamRepository) FetchByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	const q = `
+		SELECT name, archived_at
+		FROM teams
+		WHERE name = $1;
+	`
+
+	team, err := scanTeam(tr.q.QueryRowContext(ctx, q, teamName))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return team, nil
+}
+
+func (tr *teamRepository) Rename(ctx context.Context, teamName, newName string) error {
+	const q = `
+		UPDATE teams
+		SET name = $2
+		WHERE name = $1;
+	`
+
+	res, err := tr.q.ExecContext(ctx, q, teamName, newName)
+	if err != nil {
+		if code, ok := errorCode(err); ok && code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+			return domain.ErrAlreadyExists
+		}
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetArchived архивирует команду или возвращает ее из архива; повторный вызов сохраняет исходное archived_at
+func (tr *teamRepository) SetArchived(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+	const q = `
+		UPDATE teams
+		SET archived_at = CASE WHEN $2 THEN COALESCE(archived_at, ` + sqlNow + `) END
+		WHERE name = $1
+		RETURNING name, archived_at;
+	`
+
+	team, err := scanTeam(tr.q.QueryRowContext(ctx, q, teamName, archived))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// Delete удаляет команду вместе с ее курсором round_robin. Участники к этому моменту должны быть отвязаны.
+func (tr *teamRepository) Delete(ctx context.Context, teamName string) error {
+	const q = `
+		DELETE FROM teams
+		WHERE name = $1;
+	`
+
+	res, err := tr.q.ExecContext(ctx, q, teamName)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr *teamRepository) List(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error) {
+	col, err := lookupSort(teamSortColumns, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decodeCursor(filter.Cursor, filter.Sort, filter.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, orderBy, keyArgs := keyset(col, "name", filter.Order, cur, 3)
+	q := fmt.Sprintf(`
+		SELECT name, archived_at
+		FROM teams
+		WHERE ($1 IS NULL OR (archived_at IS NOT NULL) = $1)
+		  AND %s
+		ORDER BY %s
+		LIMIT $2;
+	`, cond, orderBy)
+
+	args := append([]any{filter.Archived, filter.Limit + 1}, keyArgs...)
+	rows, err := tr.q.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams, err := collectRows(rows, scanTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(teams, filter.Limit, filter.Sort, filter.Order, func(t *domain.Team) (string, string) {
+		return t.Name, t.Name
+	}), nil
+}
+
+func (tr *teamRepository) FetchSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	const q = `
+		SELECT COALESCE(s.min_reviewers, $2),
+		       COALESCE(s.max_reviewers, $3),
+		       s.default_max_open_reviews
+		FROM teams t
+		LEFT JOIN team_settings s ON s.team_name = t.name
+		WHERE t.name = $1;
+	`
+	const fallbacksQ = `
+		SELECT fallback_team_name
+		FROM team_fallback_teams
+		WHERE team_name = $1
+		ORDER BY position;
+	`
+
+	settings := domain.TeamSettings{TeamName: teamName}
+	err := tr.q.QueryRowContext(ctx, q, teamName, domain.DefaultMinReviewers, domain.DefaultMaxReviewers).
+		Scan(&settings.MinReviewers, &settings.MaxReviewers, &settings.DefaultMaxOpenReviews)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := tr.q.QueryContext(ctx, fallbacksQ, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if settings.FallbackTeams, err = collectRows(rows, scanID); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// UpsertSettings сохраняет настройки команды; список резервных команд заменяется целиком с сохранением порядка
+func (tr *teamRepository) UpsertSettings(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+	const upsertQ = `
+		INSERT INTO team_settings (team_name, min_reviewers, max_reviewers, default_max_open_reviews)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (team_name) DO UPDATE
+		SET min_reviewers            = excluded.min_reviewers,
+		    max_reviewers            = excluded.max_reviewers,
+		    default_max_open_reviews = excluded.default_max_open_reviews;
+	`
+	const deleteFallbacksQ = `
+		DELETE FROM team_fallback_teams
+		WHERE team_name = $1;
+	`
+	const insertFallbacksQ = `
+		INSERT INTO team_fallback_teams (team_name, fallback_team_name, position)
+		SELECT $1, f.value, f.key + 1
+		FROM json_each($2) AS f;
+	`
+
+	err := atomically(ctx, tr.q, func(q Querier) error {
+		if _, err := q.ExecContext(ctx, upsertQ, settings.TeamName, settings.MinReviewers, settings.MaxReviewers, settings.DefaultMaxOpenReviews); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, deleteFallbacksQ, settings.TeamName); err != nil {
+			return err
+		}
+		if len(settings.FallbackTeams) > 0 {
+			if _, err := q.ExecContext(ctx, insertFallbacksQ, settings.TeamName, jsonList(settings.FallbackTeams)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tr.FetchSettings(ctx, settings.TeamName)
+}
+
+// LockReviewCursor возвращает последнего назначенного по кругу ревьювера команды. Отдельная блокировка строки
+// не нужна: пишущая транзакция SQLite держит блокировку записи всей базы до своего завершения.
+func (tr *teamRepository) LockReviewCursor(ctx context.Context, teamName string) (string, error) {
+	const insertQ = `
+		INSERT INTO team_review_cursors (team_name)
+		VALUES ($1)
+		ON CONFLICT (team_name) DO NOTHING;
+	`
+	const selectQ = `
+		SELECT last_user_id
+		FROM team_review_cursors
+		WHERE team_name = $1;
+	`
+
+	if _, err := tr.q.ExecContext(ctx, insertQ, teamName); err != nil {
+		return "", err
+	}
+
+	var lastUserID string
+	if err := tr.q.QueryRowContext(ctx, selectQ, teamName).Scan(&lastUserID); err != nil {
+		return "", err
+	}
+
+	return lastUserID, nil
+}
+
+func (tr *teamRepository) UpdateReviewCursor(ctx context.Context, teamName, lastUserID string) error {
+	const q = `
+		UPDATE team_review_cursors
+		SET last_user_id = $2
+		WHERE team_name = $1;
+	`
+
+	_, err := tr.q.ExecContext(ctx, q, teamName, lastUserID)
+	return err
+}
@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	defaultTxMaxAttempts = 5
+	defaultTxRetryDelay  = 10 * time.Millisecond
+)
+
+// txKey — ключ контекста, под которым лежит открытая WithinTx транзакция
+type txKey struct{}
+
+// txConn — открытая транзакция и глубина вложенного в нее savepoint
+type txConn struct {
+	tx    *sql.Tx
+	depth int
+}
+
+type txManager struct {
+	db *sql.DB
+	// maxAttempts — сколько раз всего выполнить транзакцию, пока база занята другой пишущей транзакцией
+	maxAttempts int
+	// retryDelay — базовая пауза перед повтором, удваивается с каждой попыткой
+	retryDelay time.Duration
+}
+
+func NewTxManager(db *sql.DB) domain.TxManager {
+	return &txManager{
+		db:          db,
+		maxAttempts: defaultTxMaxAttempts,
+		retryDelay:  defaultTxRetryDelay,
+	}
+}
+
+// WithinTx повторяет транзакцию с экспоненциальной паузой и случайным разбросом, пока база занята (SQLITE_BUSY)
+// дольше busy_timeout, попытки не исчерпаны и контекст не отменен. Если повторы не помогли, ошибка последней попытки
+// возвращается обернутой в domain.ErrConcurrentUpdate.
+// Пишущие транзакции SQLite выполняются по одной, поэтому любой уровень изоляции фактически SERIALIZABLE.
+// ReadOnly запрещает запись через PRAGMA query_only. StatementTimeout не применяется: в SQLite его нет.
+// Вызов из колбэка другого WithinTx не открывает новую транзакцию, а выполняется в ней же через savepoint.
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context, repos *domain.Repos) error, opts ...domain.TxOption) error {
+	if outer, ok := ctx.Value(txKey{}).(*txConn); ok {
+		return withinSavepoint(ctx, outer, fn)
+	}
+
+	o := domain.ApplyTxOptions(opts...)
+
+	var err error
+	for attempt := 0; attempt < m.maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := m.retryDelay << (attempt - 1)
+			delay += time.Duration(rand.Int63n(int64(delay) + 1))
+
+			select {
+			case <-ctx.Done():
+				return exhausted(err)
+			case <-time.After(delay):
+			}
+		}
+
+		err = m.runTx(ctx, o, fn)
+		if err == nil || !retryable(err) {
+			return err
+		}
+	}
+
+	return exhausted(err)
+}
+
+func (m *txManager) runTx(ctx context.Context, o domain.TxOptions, fn func(ctx context.Context, repos *domain.Repos) error) error {
+	// читающая транзакция начинается с обычного BEGIN и не ждет пишущих: в режиме WAL она читает снимок
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: o.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if o.ReadOnly {
+		if _, err := tx.ExecContext(ctx, `PRAGMA query_only = ON`); err != nil {
+			return err
+		}
+	}
+
+	err = fn(context.WithValue(ctx, txKey{}, &txConn{tx: tx}), newRepos(tx))
+	if o.ReadOnly {
+		// query_only — настройка соединения, а не транзакции: возвращаем соединение в пул пригодным для записи
+		if _, resetErr := tx.ExecContext(context.WithoutCancel(ctx), `PRAGMA query_only = OFF`); err == nil {
+			err = resetErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withinSavepoint выполняет fn во вложенной транзакции outer (SAVEPOINT). Ошибка fn откатывает только ее изменения,
+// судьбу внешней транзакции решает вызывающий. Опции и повторы не применяются: их задает внешняя транзакция.
+func withinSavepoint(ctx context.Context, outer *txConn, fn func(ctx context.Context, repos *domain.Repos) error) error {
+	sp := &txConn{tx: outer.tx, depth: outer.depth + 1}
+	name := fmt.Sprintf("sp_%d", sp.depth)
+
+	if _, err := sp.tx.ExecContext(ctx, `SAVEPOINT `+name); err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, sp), newRepos(sp.tx)); err != nil {
+		rollbackCtx := context.WithoutCancel(ctx)
+		_, _ = sp.tx.ExecContext(rollbackCtx, `ROLLBACK TO `+name)
+		_, _ = sp.tx.ExecContext(rollbackCtx, `RELEASE `+name)
+		return err
+	}
+
+	_, err := sp.tx.ExecContext(ctx, `RELEASE `+name)
+	return err
+}
+
+func newRepos(q Querier) *domain.Repos {
+	return &domain.Repos{
+		PR:   NewPRRepository(q),
+		User: NewUserRepository(q),
+		Team: NewTeamRepository(q),
+	}
+}
+
+// retryable сообщает, что транзакцию можно безопасно повторить целиком: база была занята другой транзакцией
+func retryable(err error) bool {
+	code, ok := errorCode(err)
+	return ok && code&0xff == sqlite3.SQLITE_BUSY
+}
+
+// exhausted помечает повторяемую ошибку, которую не удалось преодолеть повторами, как конфликт параллельных изменений
+func exhausted(err error) error {
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", domain.ErrConcurrentUpdate, err)
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+func TestTxManager_ReadOnlyRejectsWrites(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	// одно соединение: следующая запись пойдет через то же соединение, что и читающая транзакция
+	db.SetMaxOpenConns(1)
+
+	err := NewTxManager(db).WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		return repos.Team.Create(ctx, "readonly_team")
+	}, domain.ReadOnly())
+
+	code, ok := errorCode(err)
+	require.True(t, ok, "unexpected error: %v", err)
+	require.Equal(t, sqlite3.SQLITE_READONLY, code&0xff)
+
+	// соединение вернулось в пул пригодным для записи
+	require.NoError(t, NewTeamRepository(db).Create(ctx, "readonly_team"))
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timeLayout — формат хранения времени; совпадает с strftime('%Y-%m-%dT%H:%M:%fZ', 'now') в SQL
+const timeLayout = "2006-01-02T15:04:05.000Z"
+
+// sqlNow — текущее время в формате timeLayout для подстановки в текст запроса
+const sqlNow = `strftime('%Y-%m-%dT%H:%M:%fZ', 'now')`
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// nullableTime переводит необязательную границу фильтра в параметр запроса
+func nullableTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return formatTime(*t)
+}
+
+// timestamp читает время, сохраненное строкой в формате timeLayout
+type timestamp struct {
+	t *time.Time
+}
+
+func (ts timestamp) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("unsupported timestamp value %T", src)
+	}
+
+	t, err := time.Parse(timeLayout, raw)
+	if err != nil {
+		return err
+	}
+	*ts.t = t
+	return nil
+}
+
+// nullTimestamp — timestamp для колонок, допускающих NULL
+type nullTimestamp struct {
+	t **time.Time
+}
+
+func (ts nullTimestamp) Scan(src any) error {
+	if src == nil {
+		*ts.t = nil
+		return nil
+	}
+
+	var t time.Time
+	if err := (timestamp{t: &t}).Scan(src); err != nil {
+		return err
+	}
+	*ts.t = &t
+	return nil
+}
+
+// stringList читает JSON-массив строк, собранный json_group_array
+type stringList struct {
+	s *[]string
+}
+
+func (l stringList) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported list value %T", src)
+	}
+
+	list := []string{}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return err
+	}
+	*l.s = list
+	return nil
+}
+
+// jsonList передает список строк одним параметром; в запросе он раскрывается через json_each.
+// Заменяет массивы Postgres в условиях вида id = ANY($1).
+func jsonList(items []string) string {
+	if items == nil {
+		items = []string{}
+	}
+	raw, _ := json.Marshal(items)
+	return string(raw)
+}
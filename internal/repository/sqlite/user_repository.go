@@ -0,0 +1,464 @@
+package sqlite
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var userSortColumns = map[string]sortColumn{
+	domain.SortByID:   {expr: "id", unique: true},
+	domain.SortByName: {expr: "name"},
+}
+
+const userColumns = `id, name, COALESCE(team_name, ''), is_active, max_open_reviews`
+
+type userRepository struct {
+	q Querier
+}
+
+func NewUserRepository(q Querier) domain.UserRepository {
+	return &userRepository{q: q}
+}
+
+func scanUser(r scanner) (*domain.User, error) {
+	var user domain.User
+	if err := r.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.MaxOpenReviews); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (ur *userRepository) Upsert(ctx context.Context, user *domain.User) error {
+	const q = `
+		INSERT INTO users (id, name, team_name, is_active)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE
+		SET name = excluded.name,
+		    team_name = excluded.team_name,
+		    is_active = excluded.is_active;
+	`
+
+	_, err := ur.q.ExecContext(ctx, q, user.ID, user.Name, user.TeamName, user.IsActive)
+	return err
+}
+
+func (ur *userRepository) FetchByID(ctx context.Context, userID string) (*domain.User, error) {
+	const q = `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = $1
+	`
+
+	user, err := scanUser(ur.q.QueryRowContext(ctx, q, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// FetchByIDs возвращает найденных пользователей из userIDs; отсутствующие id пропускаются
+func (ur *userRepository) FetchByIDs(ctx context.Context, userIDs []string) ([]*domain.User, error) {
+	const q = `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id IN (SELECT value FROM json_each($1))
+		ORDER BY id
+	`
+
+	rows, err := ur.q.QueryContext(ctx, q, jsonList(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanUser)
+}
+
+func (ur *userRepository) FetchByTeam(ctx context.Context, teamName string) ([]*domain.User, error) {
+	const q = `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE team_name = $1
+	`
+
+	rows, err := ur.q.QueryContext(ctx, q, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanUser)
+}
+
+// FetchActiveByTeam возвращает активных участников команды — кандидатов в ревьюверы, кроме отсутствующих в текущий момент.
+// Для архивной команды список пуст.
+func (ur *userRepository) FetchActiveByTeam(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+	const q = `
+		SELECT u.id, u.name, u.team_name, u.is_active, u.max_open_reviews
+		FROM users u
+		JOIN teams t ON t.name = u.team_name
+		WHERE u.team_name = $1
+		  AND u.is_active
+		  AND t.archived_at IS NULL
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM user_absences a
+		      WHERE a.user_id = u.id
+		        AND a.starts_at <= ` + sqlNow + `
+		        AND a.ends_at > ` + sqlNow + `
+		  )
+		  AND u.id NOT IN (SELECT value FROM json_each($2));
+	`
+
+	rows, err := ur.q.QueryContext(ctx, q, teamName, jsonList(excludeIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanUser)
+}
+
+func (ur *userRepository) Exists(ctx context.Context, userID string) (bool, error) {
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM users
+			WHERE id = $1
+		);
+	`
+
+	var exists bool
+	err := ur.q.QueryRowContext(ctx, q, userID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (ur *userRepository) UpdateIsActive(ctx context.Context, userID string, active bool) (*domain.User, error) {
+	const q = `
+		UPDATE users
+		SET is_active = $1
+		WHERE id = $2
+		RETURNING ` + userColumns + `;
+	`
+
+	user, err := scanUser(ur.q.QueryRowContext(ctx, q, active, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (ur *userRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+	const q = `
+		UPDATE users
+		SET max_open_reviews = $2
+		WHERE id = $1
+		RETURNING ` + userColumns + `;
+	`
+
+	user, err := scanUser(ur.q.QueryRowContext(ctx, q, userID, limit))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (ur *userRepository) FetchReviewLimits(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const q = `
+		SELECT u.id, COALESCE(u.max_open_reviews, s.default_max_open_reviews)
+		FROM users u
+		LEFT JOIN team_settings s ON s.team_name = u.team_name
+		WHERE u.id IN (SELECT value FROM json_each($1))
+		  AND COALESCE(u.max_open_reviews, s.default_max_open_reviews) IS NOT NULL;
+	`
+
+	limits := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return limits, nil
+	}
+
+	rows, err := ur.q.QueryContext(ctx, q, jsonList(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			limit  int
+		)
+		if err := rows.Scan(&userID, &limit); err != nil {
+			return nil, err
+		}
+		limits[userID] = limit
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// DeactivateTeamMembers деактивирует перечисленных участников команды (или всех, кроме перечисленных, при allExcept)
+// и возвращает их id. Пользователи из других команд не затрагиваются.
+func (ur *userRepository) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error) {
+	var (
+		rows *sql.Rows
+		err  error
+	)
+
+	if allExcept {
+		const qExcept = `
+			UPDATE users
+			SET is_active = FALSE
+			WHERE team_name = $1
+			  AND id NOT IN (SELECT value FROM json_each($2))
+			RETURNING id;
+		`
+		rows, err = ur.q.QueryContext(ctx, qExcept, teamName, jsonList(userIDs))
+	} else {
+		const q = `
+			UPDATE users
+			SET is_active = FALSE
+			WHERE team_name = $1
+			  AND id IN (SELECT value FROM json_each($2))
+			RETURNING id;
+		`
+		rows, err = ur.q.QueryContext(ctx, q, teamName, jsonList(userIDs))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanID)
+}
+
+// RemoveFromTeam отвязывает перечисленных участников от команды (team_name = NULL) и возвращает их id.
+// Пользователи из других команд не затрагиваются.
+func (ur *userRepository) RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+	const q = `
+		UPDATE users
+		SET team_name = NULL
+		WHERE team_name = $1
+		  AND id IN (SELECT value FROM json_each($2))
+		RETURNING id;
+	`
+
+	rows, err := ur.q.QueryContext(ctx, q, teamName, jsonList(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanID)
+}
+
+func (ur *userRepository) List(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+	col, err := lookupSort(userSortColumns, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := decodeCursor(filter.Cursor, filter.Sort, filter.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	cond, orderBy, keyArgs := keyset(col, "id", filter.Order, cur, 4)
+	q := fmt.Sprintf(`
+		SELECT %s
+		FROM users
+		WHERE ($1 = '' OR team_name = $1)
+		  AND ($2 IS NULL OR is_active = $2)
+		  AND %s
+		ORDER BY %s
+		LIMIT $3;
+	`, userColumns, cond, orderBy)
+
+	args := append([]any{filter.TeamName, filter.IsActive, filter.Limit + 1}, keyArgs...)
+	rows, err := ur.q.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users, err := collectRows(rows, scanUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(users, filter.Limit, filter.Sort, filter.Order, func(u *domain.User) (string, string) {
+		if filter.Sort == domain.SortByName {
+			return u.Name, u.ID
+		}
+		return u.ID, u.ID
+	}), nil
+}
+
+const absenceColumns = `id, user_id, starts_at, ends_at, COALESCE(delegate_id, ''), handed_off_at, created_at`
+
+func scanAbsence(r scanner) (*domain.Absence, error) {
+	var a domain.Absence
+	err := r.Scan(&a.ID, &a.UserID, timestamp{&a.StartsAt}, timestamp{&a.EndsAt}, &a.DelegateID,
+		nullTimestamp{&a.HandedOffAt}, timestamp{&a.CreatedAt})
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (ur *userRepository) AddAbsence(ctx context.Context, absence *domain.Absence) (*domain.Absence, error) {
+	const q = `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, delegate_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING ` + absenceColumns + `;
+	`
+
+	return scanAbsence(ur.q.QueryRowContext(ctx, q,
+		absence.UserID, formatTime(absence.StartsAt), formatTime(absence.EndsAt), absence.DelegateID))
+}
+
+func (ur *userRepository) HasOverlappingAbsence(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error) {
+	const q = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_absences
+			WHERE user_id = $1
+			  AND starts_at < $3
+			  AND ends_at > $2
+		);
+	`
+
+	var exists bool
+	if err := ur.q.QueryRowContext(ctx, q, userID, formatTime(startsAt), formatTime(endsAt)).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// ListAbsences возвращает периоды отсутствия пользователя по времени начала
+func (ur *userRepository) ListAbsences(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	const q = `
+		SELECT ` + absenceColumns + `
+		FROM user_absences
+		WHERE user_id = $1
+		ORDER BY starts_at, id;
+	`
+
+	rows, err := ur.q.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanAbsence)
+}
+
+func (ur *userRepository) FetchCurrentAbsence(ctx context.Context, userID string) (*domain.Absence, error) {
+	const q = `
+		SELECT ` + absenceColumns + `
+		FROM user_absences
+		WHERE user_id = $1
+		  AND starts_at <= ` + sqlNow + `
+		  AND ends_at > ` + sqlNow + `;
+	`
+
+	absence, err := scanAbsence(ur.q.QueryRowContext(ctx, q, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+func (ur *userRepository) DeleteAbsence(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	const q = `
+		DELETE FROM user_absences
+		WHERE id = $1
+		RETURNING ` + absenceColumns + `;
+	`
+
+	absence, err := scanAbsence(ur.q.QueryRowContext(ctx, q, absenceID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+// ListDueHandoffs возвращает начавшиеся периоды с заместителем. Блокировка строк не нужна: пишущая транзакция
+// SQLite выполняется в одиночку, поэтому параллельный обработчик увидит уже отмеченные handed_off_at.
+func (ur *userRepository) ListDueHandoffs(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	const q = `
+		SELECT ` + absenceColumns + `
+		FROM user_absences
+		WHERE delegate_id IS NOT NULL
+		  AND handed_off_at IS NULL
+		  AND starts_at <= ` + sqlNow + `
+		  AND ends_at > ` + sqlNow + `
+		  AND ($1 = '' OR user_id = $1)
+		ORDER BY starts_at, id;
+	`
+
+	rows, err := ur.q.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return collectRows(rows, scanAbsence)
+}
+
+func (ur *userRepository) MarkHandedOff(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	const q = `
+		UPDATE user_absences
+		SET handed_off_at = ` + sqlNow + `
+		WHERE id = $1
+		RETURNING ` + absenceColumns + `;
+	`
+
+	absence, err := scanAbsence(ur.q.QueryRowContext(ctx, q, absenceID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return absence, nil
+}
+
+func scanID(r scanner) (string, error) {
+	var id string
+	err := r.Scan(&id)
+	return id, err
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// MergePolicy — условия, при которых PR можно смержить. Нулевое значение ничего не требует;
+// значения по умолчанию для сервиса задает config.Load.
+type MergePolicy struct {
+	// MinApprovals — минимальное число ревьюверов в состоянии APPROVED.
+	MinApprovals int
+	// BlockOnChangesRequested запрещает merge, пока кто-то из ревьюверов запрашивает изменения.
+	BlockOnChangesRequested bool
+	// AllowOverride разрешает смержить PR в обход политики с флагом override.
+	AllowOverride bool
+}
+
+// Missing возвращает описания невыполненных условий для текущих решений ревьюверов.
+func (p MergePolicy) Missing(states []*domain.ReviewerState) []string {
+	var (
+		approvals int
+		blockers  []string
+	)
+	for _, st := range states {
+		switch st.State {
+		case domain.ReviewApproved:
+			approvals++
+		case domain.ReviewChangesRequested:
+			blockers = append(blockers, st.UserID)
+		}
+	}
+
+	var missing []string
+	if approvals < p.MinApprovals {
+		missing = append(missing, fmt.Sprintf("%d of %d required approvals", approvals, p.MinApprovals))
+	}
+	if p.BlockOnChangesRequested && len(blockers) > 0 {
+		missing = append(missing, "changes requested by "+strings.Join(blockers, ", "))
+	}
+
+	return missing
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"fmt"
+	"slices"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// normalizePage подставляет значения по умолчанию и проверяет сортировку и размер страницы
+func normalizePage(p *domain.PageParams, sorts []string, defaultOrder domain.SortOrder) error {
+	if p.Sort == "" {
+		p.Sort = sorts[0]
+	}
+	if !slices.Contains(sorts, p.Sort) {
+		return fmt.Errorf("sort must be one of %v: %w", sorts, domain.ErrInvalidInput)
+	}
+
+	switch p.Order {
+	case "":
+		p.Order = defaultOrder
+	case domain.SortAsc, domain.SortDesc:
+	default:
+		return fmt.Errorf("order must be asc or desc: %w", domain.ErrInvalidInput)
+	}
+
+	switch {
+	case p.Limit == 0:
+		p.Limit = DefaultPageLimit
+	case p.Limit < 0 || p.Limit > MaxPageLimit:
+		return fmt.Errorf("limit must be between 1 and %d: %w", MaxPageLimit, domain.ErrInvalidInput)
+	}
+
+	return nil
+}
@@ -5,21 +5,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
-	"time"
+	"slices"
 )
 
 type prUsecase struct {
 	userRepository domain.UserRepository
 	prRepository   domain.PRRepository
 	txManager      domain.TxManager
+	selector       ReviewerSelector
+	mergePolicy    MergePolicy
 }
 
-func NewPRUsecase(userRepository domain.UserRepository, prRepository domain.PRRepository, txManager domain.TxManager) domain.PRUsecase {
+func NewPRUsecase(userRepository domain.UserRepository, prRepository domain.PRRepository, txManager domain.TxManager, selector ReviewerSelector, mergePolicy MergePolicy) domain.PRUsecase {
 	return &prUsecase{
 		userRepository: userRepository,
 		prRepository:   prRepository,
 		txManager:      txManager,
+		selector:       selector,
+		mergePolicy:    mergePolicy,
 	}
 }
 
@@ -39,43 +42,79 @@ func (p *prUsecase) CreateWithReviewers(ctx context.Context, newPR *domain.PullR
 			return err
 		}
 
-		author, err := repos.User.FetchByID(ctx, createdPR.AuthorID)
+		if createdPR.IsDraft {
+			result = createdPR
+			return nil
+		}
+
+		reviewerIDs, err := assignFromAuthorTeam(ctx, repos, p.selector, createdPR, nil, domain.ReasonPRCreated)
 		if err != nil {
 			return err
 		}
 
-		candidates, err := repos.User.FetchActiveByTeam(ctx, author.TeamName, author.ID)
+		createdPR.Reviewers = reviewerIDs
+		for _, id := range reviewerIDs {
+			createdPR.ReviewerStates = append(createdPR.ReviewerStates, &domain.ReviewerState{UserID: id, State: domain.ReviewPending})
+		}
+		result = createdPR
+		return nil
+	}, assignmentTx...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Merge блокирует строку PR на время транзакции, поэтому параллельные merge, переназначение и смена статуса
+// выполняются по очереди и видят результат друг друга.
+func (p *prUsecase) Merge(ctx context.Context, prID string, override bool) (*domain.PullRequest, error) {
+	var result *domain.PullRequest
+
+	err := p.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		current, err := repos.PR.FetchByIDForUpdate(ctx, prID)
 		if err != nil {
 			return err
 		}
 
-		if len(candidates) == 0 {
-			createdPR.Reviewers = nil
-			result = createdPR
-			return nil
+		switch current.Status {
+		case domain.StatusClosed:
+			return domain.ErrPRClosed
+		case domain.StatusMerged:
+			// повторный merge идемпотентен и политику не проверяет
+			result = current
+			return loadReviewers(ctx, repos, current)
+		}
+		if current.IsDraft {
+			return domain.ErrPRDraft
 		}
 
-		rand.New(rand.NewSource(time.Now().UnixNano())).Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
-		})
-
-		if len(candidates) > 2 {
-			candidates = candidates[:2]
+		states, err := repos.PR.ListReviewerStates(ctx, prID)
+		if err != nil {
+			return err
 		}
 
-		reviewerIDs := make([]string, 0, len(candidates))
-		for _, r := range candidates {
-			if err := repos.PR.InsertReviewer(ctx, createdPR.ID, r.ID); err != nil {
-				return err
+		var overrideBy string
+		if missing := p.mergePolicy.Missing(states); len(missing) > 0 {
+			if !override || !p.mergePolicy.AllowOverride {
+				return &domain.MergeBlockedError{Missing: missing}
 			}
-			reviewerIDs = append(reviewerIDs, r.ID)
+			overrideBy = domain.ActorFromContext(ctx)
 		}
 
-		createdPR.Reviewers = reviewerIDs
-		result = createdPR
+		pr, err := repos.PR.UpdateStatusMerged(ctx, prID, overrideBy)
+		if err != nil {
+			return err
+		}
+
+		if err := loadReviewers(ctx, repos, pr); err != nil {
+			return err
+		}
+
+		result = pr
 		return nil
 	})
-
 	if err != nil {
 		return nil, err
 	}
@@ -83,21 +122,28 @@ func (p *prUsecase) CreateWithReviewers(ctx context.Context, newPR *domain.PullR
 	return result, nil
 }
 
-func (p *prUsecase) Merge(ctx context.Context, prID string) (*domain.PullRequest, error) {
+// Close закрывает PR без merge. Ревьюверы остаются привязаны к PR, но пропадают из их очереди ревью.
+func (p *prUsecase) Close(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	var result *domain.PullRequest
 
 	err := p.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
-		pr, err := repos.PR.UpdateStatusMerged(ctx, prID)
+		current, err := repos.PR.FetchByIDForUpdate(ctx, prID)
 		if err != nil {
 			return err
 		}
+		if current.Status == domain.StatusMerged {
+			return domain.ErrPRMerged
+		}
 
-		reviewers, err := repos.PR.ListReviewers(ctx, pr.ID)
+		pr, err := repos.PR.UpdateStatusClosed(ctx, prID)
 		if err != nil {
 			return err
 		}
 
-		pr.Reviewers = reviewers
+		if err := loadReviewers(ctx, repos, pr); err != nil {
+			return err
+		}
+
 		result = pr
 		return nil
 	})
@@ -108,6 +154,134 @@ func (p *prUsecase) Merge(ctx context.Context, prID string) (*domain.PullRequest
 	return result, nil
 }
 
+// Reopen возвращает закрытый PR в OPEN. Ревьюверы, ставшие неактивными, снимаются,
+// а недостающие до max_reviewers команды автора назначаются заново (кроме черновиков).
+func (p *prUsecase) Reopen(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	var result *domain.PullRequest
+
+	err := p.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		pr, err := repos.PR.FetchByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if pr.Status == domain.StatusMerged {
+			return domain.ErrPRMerged
+		}
+
+		reviewerIDs, err := repos.PR.ListReviewers(ctx, pr.ID)
+		if err != nil {
+			return err
+		}
+
+		if pr.Status == domain.StatusOpen {
+			result = pr
+			return loadReviewers(ctx, repos, pr)
+		}
+
+		pr, err = repos.PR.UpdateStatusOpen(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		kept := make([]string, 0, len(reviewerIDs))
+		for _, id := range reviewerIDs {
+			reviewer, err := repos.User.FetchByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if reviewer.IsActive {
+				kept = append(kept, id)
+				continue
+			}
+
+			if err := repos.PR.RemoveReviewer(ctx, pr.ID, id); err != nil {
+				return err
+			}
+			err = repos.PR.AddAssignmentEvents(ctx, &domain.AssignmentEvent{
+				PRID:   pr.ID,
+				Type:   domain.EventUnassigned,
+				UserID: id,
+				Actor:  domain.ActorFromContext(ctx),
+				Reason: domain.ReasonPRReopened,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !pr.IsDraft {
+			if _, err := assignFromAuthorTeam(ctx, repos, p.selector, pr, kept, domain.ReasonPRReopened); err != nil {
+				return err
+			}
+		}
+
+		if err := loadReviewers(ctx, repos, pr); err != nil {
+			return err
+		}
+
+		result = pr
+		return nil
+	}, assignmentTx...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarkReady снимает с PR признак черновика и назначает ревьюверов так же, как при создании.
+// Для PR, который уже не черновик, возвращает его текущее состояние.
+func (p *prUsecase) MarkReady(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	var result *domain.PullRequest
+
+	err := p.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		pr, err := repos.PR.FetchByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		switch pr.Status {
+		case domain.StatusMerged:
+			return domain.ErrPRMerged
+		case domain.StatusClosed:
+			return domain.ErrPRClosed
+		}
+
+		reviewerIDs, err := repos.PR.ListReviewers(ctx, pr.ID)
+		if err != nil {
+			return err
+		}
+
+		if !pr.IsDraft {
+			result = pr
+			return loadReviewers(ctx, repos, pr)
+		}
+
+		pr, err = repos.PR.UpdateReady(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := assignFromAuthorTeam(ctx, repos, p.selector, pr, reviewerIDs, domain.ReasonPRReady); err != nil {
+			return err
+		}
+
+		if err := loadReviewers(ctx, repos, pr); err != nil {
+			return err
+		}
+
+		result = pr
+		return nil
+	}, assignmentTx...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Reassign блокирует строку PR, чтобы параллельные переназначения не выбрали одну и ту же замену.
 func (p *prUsecase) Reassign(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error) {
 	var (
 		result   *domain.PullRequest
@@ -115,13 +289,16 @@ func (p *prUsecase) Reassign(ctx context.Context, prID, oldReviewerID string) (*
 	)
 
 	err := p.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
-		pr, err := repos.PR.FetchByID(ctx, prID)
+		pr, err := repos.PR.FetchByIDForUpdate(ctx, prID)
 		if err != nil {
 			return err
 		}
 
-		if pr.Status == domain.StatusMerged {
+		switch pr.Status {
+		case domain.StatusMerged:
 			return domain.ErrPRMerged
+		case domain.StatusClosed:
+			return domain.ErrPRClosed
 		}
 
 		assigned, err := repos.PR.ReviewerAssigned(ctx, prID, oldReviewerID)
@@ -137,48 +314,183 @@ func (p *prUsecase) Reassign(ctx context.Context, prID, oldReviewerID string) (*
 			return err
 		}
 
-		currentReviewers, err := repos.PR.ListReviewers(ctx, pr.ID)
+		newRevID, err = replaceReviewer(ctx, repos, p.selector, pr, oldReviewer, domain.ReasonManualReassign)
 		if err != nil {
 			return err
 		}
 
-		excludeIDs := make([]string, 0, len(currentReviewers)+2)
-		excludeIDs = append(excludeIDs, oldReviewerID, pr.AuthorID)
-		for _, reviewerID := range currentReviewers {
-			if reviewerID != oldReviewerID {
-				excludeIDs = append(excludeIDs, reviewerID)
-			}
+		if err := loadReviewers(ctx, repos, pr); err != nil {
+			return err
 		}
 
-		revs, err := repos.User.FetchActiveByTeam(ctx, oldReviewer.TeamName, excludeIDs...)
+		result = pr
+		return nil
+	}, assignmentTx...)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return result, newRevID, nil
+}
+
+// SubmitReview сохраняет решение ревьювера. Менять решения можно только на открытом PR.
+func (p *prUsecase) SubmitReview(ctx context.Context, prID, userID string, state domain.ReviewState) (*domain.PullRequest, error) {
+	switch state {
+	case domain.ReviewApproved, domain.ReviewChangesRequested, domain.ReviewDismissed:
+	default:
+		return nil, fmt.Errorf("%w: unsupported review state %q", domain.ErrInvalidInput, state)
+	}
+
+	var result *domain.PullRequest
+
+	err := p.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		pr, err := repos.PR.FetchByIDForUpdate(ctx, prID)
 		if err != nil {
 			return err
 		}
-		if len(revs) == 0 {
-			return domain.ErrNoCandidate
+
+		switch pr.Status {
+		case domain.StatusMerged:
+			return domain.ErrPRMerged
+		case domain.StatusClosed:
+			return domain.ErrPRClosed
 		}
 
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		newRevID = revs[r.Intn(len(revs))].ID
+		if err := repos.PR.UpdateReviewState(ctx, prID, userID, state); err != nil {
+			return err
+		}
+
+		if err := loadReviewers(ctx, repos, pr); err != nil {
+			return err
+		}
+
+		result = pr
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *prUsecase) History(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+	if _, err := p.prRepository.FetchByID(ctx, prID); err != nil {
+		return nil, err
+	}
+
+	return p.prRepository.ListAssignmentEvents(ctx, prID)
+}
+
+// Get собирает карточку PR фиксированным числом запросов: PR, решения ревьюверов, профили и журнал назначений
+func (p *prUsecase) Get(ctx context.Context, prID string) (*domain.PRDetails, error) {
+	var result *domain.PRDetails
+
+	err := p.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		pr, err := repos.PR.FetchByID(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if err := loadReviewers(ctx, repos, pr); err != nil {
+			return err
+		}
 
-		err = repos.PR.ReplaceReviewer(ctx, pr.ID, oldReviewerID, newRevID)
+		users, err := repos.User.FetchByIDs(ctx, append([]string{pr.AuthorID}, pr.Reviewers...))
 		if err != nil {
 			return err
 		}
+		byID := make(map[string]*domain.User, len(users))
+		for _, u := range users {
+			byID[u.ID] = u
+		}
 
-		revsIDs, err := repos.PR.ListReviewers(ctx, pr.ID)
+		events, err := repos.PR.ListAssignmentEvents(ctx, prID)
 		if err != nil {
 			return err
 		}
 
-		pr.Reviewers = revsIDs
-		result = pr
+		result = &domain.PRDetails{
+			PR:        pr,
+			Author:    byID[pr.AuthorID],
+			Reviewers: make([]*domain.User, 0, len(pr.Reviewers)),
+			Timeline:  buildTimeline(pr, events),
+		}
+		for _, id := range pr.Reviewers {
+			if u, ok := byID[id]; ok {
+				result.Reviewers = append(result.Reviewers, u)
+			}
+		}
+
 		return nil
+	}, domain.ReadOnly(), domain.WithIsolation(domain.RepeatableRead))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// buildTimeline объединяет журнал назначений с отметками создания, merge и закрытия PR в хронологическом порядке
+func buildTimeline(pr *domain.PullRequest, events []*domain.AssignmentEvent) []*domain.TimelineEvent {
+	timeline := make([]*domain.TimelineEvent, 0, len(events)+2)
+	if pr.CreatedAt != nil {
+		timeline = append(timeline, &domain.TimelineEvent{Type: domain.TimelineCreated, At: *pr.CreatedAt, UserID: pr.AuthorID})
+	}
+
+	for _, e := range events {
+		timeline = append(timeline, &domain.TimelineEvent{
+			Type:           domain.TimelineEventType(e.Type),
+			At:             e.CreatedAt,
+			UserID:         e.UserID,
+			PreviousUserID: e.PreviousUserID,
+			Actor:          e.Actor,
+			Reason:         e.Reason,
+		})
+	}
+
+	if pr.MergedAt != nil {
+		timeline = append(timeline, &domain.TimelineEvent{Type: domain.TimelineMerged, At: *pr.MergedAt})
+	}
+	if pr.ClosedAt != nil {
+		timeline = append(timeline, &domain.TimelineEvent{Type: domain.TimelineClosed, At: *pr.ClosedAt})
+	}
+
+	// Стабильная сортировка сохраняет порядок журнала и ставит создание раньше назначений с тем же временем
+	slices.SortStableFunc(timeline, func(a, b *domain.TimelineEvent) int {
+		return a.At.Compare(b.At)
 	})
 
+	return timeline
+}
+
+// List возвращает страницу PR, по умолчанию сначала новые
+func (p *prUsecase) List(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, fmt.Errorf("unknown status %q: %w", filter.Status, domain.ErrInvalidInput)
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, fmt.Errorf("created_from must be before created_to: %w", domain.ErrInvalidInput)
+	}
+	if err := normalizePage(&filter.PageParams, []string{domain.SortByCreatedAt, domain.SortByID}, domain.SortDesc); err != nil {
+		return nil, err
+	}
+
+	return p.prRepository.List(ctx, filter)
+}
+
+// loadReviewers заполняет ревьюверов PR вместе с их решениями
+func loadReviewers(ctx context.Context, repos *domain.Repos, pr *domain.PullRequest) error {
+	states, err := repos.PR.ListReviewerStates(ctx, pr.ID)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
 
-	return result, newRevID, nil
+	pr.Reviewers = make([]string, 0, len(states))
+	for _, st := range states {
+		pr.Reviewers = append(pr.Reviewers, st.UserID)
+	}
+	pr.ReviewerStates = states
+
+	return nil
 }
@@ -5,11 +5,14 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestPRUsecaseCreateWithReviewers_AssignsUpToTwo(t *testing.T) {
 	inserted := make(map[string]struct{})
+	var events []*domain.AssignmentEvent
 
 	prRepo := &prRepositoryMock{
 		createFn: func(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
@@ -24,6 +27,10 @@ func TestPRUsecaseCreateWithReviewers_AssignsUpToTwo(t *testing.T) {
 			inserted[userID] = struct{}{}
 			return nil
 		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			events = append(events, evs...)
+			return nil
+		},
 	}
 
 	userRepo := &userRepositoryMock{
@@ -43,10 +50,11 @@ func TestPRUsecaseCreateWithReviewers_AssignsUpToTwo(t *testing.T) {
 		repos: &domain.Repos{
 			PR:   prRepo,
 			User: userRepo,
+			Team: &teamRepositoryMock{},
 		},
 	}
 
-	uc := NewPRUsecase(userRepo, prRepo, tx)
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
 
 	pr, err := uc.CreateWithReviewers(context.Background(), &domain.PullRequest{
 		ID:       "pr1",
@@ -66,6 +74,18 @@ func TestPRUsecaseCreateWithReviewers_AssignsUpToTwo(t *testing.T) {
 			t.Fatalf("reviewer %s was not inserted", id)
 		}
 	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 history events, got %d", len(events))
+	}
+	for _, e := range events {
+		if e.Type != domain.EventAssigned || e.Reason != domain.ReasonPRCreated || e.Actor != domain.SystemActor {
+			t.Fatalf("unexpected history event: %+v", e)
+		}
+	}
+	if tx.lastOpts.Isolation != domain.Serializable {
+		t.Fatalf("expected assignment to run at SERIALIZABLE, got %+v", tx.lastOpts)
+	}
 }
 
 func TestPRUsecaseCreateWithReviewers_NoCandidates(t *testing.T) {
@@ -88,9 +108,9 @@ func TestPRUsecaseCreateWithReviewers_NoCandidates(t *testing.T) {
 		},
 	}
 
-	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo}}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: &teamRepositoryMock{}}}
 
-	uc := NewPRUsecase(userRepo, prRepo, tx)
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
 	pr, err := uc.CreateWithReviewers(context.Background(), &domain.PullRequest{ID: "pr2", AuthorID: "author"})
 	if err != nil {
 		t.Fatalf("CreateWithReviewers: %v", err)
@@ -100,6 +120,100 @@ func TestPRUsecaseCreateWithReviewers_NoCandidates(t *testing.T) {
 	}
 }
 
+func TestPRUsecaseCreateWithReviewers_UsesTeamSettingsAndFallback(t *testing.T) {
+	var inserted []string
+	prRepo := &prRepositoryMock{
+		createFn: func(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
+			return pr, nil
+		},
+		insertReviewerFn: func(ctx context.Context, prID, userID string) error {
+			inserted = append(inserted, userID)
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error { return nil },
+	}
+
+	var excludedInFallback []string
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team"}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			if teamName == "team" {
+				return []*domain.User{{ID: "u2", TeamName: teamName, IsActive: true}}, nil
+			}
+			excludedInFallback = excludeIDs
+			return []*domain.User{{ID: "f1", TeamName: teamName, IsActive: true}}, nil
+		},
+	}
+	teamRepo := &teamRepositoryMock{
+		fetchSettingsFn: func(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+			return &domain.TeamSettings{TeamName: teamName, MinReviewers: 3, MaxReviewers: 3, FallbackTeams: []string{"fallback"}}, nil
+		},
+	}
+
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: teamRepo}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.CreateWithReviewers(context.Background(), &domain.PullRequest{ID: "pr1", AuthorID: "author"})
+	if err != nil {
+		t.Fatalf("CreateWithReviewers: %v", err)
+	}
+	if !reflect.DeepEqual(pr.Reviewers, []string{"u2", "f1"}) || !reflect.DeepEqual(inserted, pr.Reviewers) {
+		t.Fatalf("expected own team first, then fallback, got %v", pr.Reviewers)
+	}
+	if !slices.Contains(excludedInFallback, "author") || !slices.Contains(excludedInFallback, "u2") {
+		t.Fatalf("fallback must exclude author and assigned reviewers, got %v", excludedInFallback)
+	}
+	if pr.MissingReviewers != 1 {
+		t.Fatalf("expected 1 missing reviewer, got %d", pr.MissingReviewers)
+	}
+	if len(pr.FallbackReviewers) != 1 || *pr.FallbackReviewers[0] != (domain.FallbackReviewer{UserID: "f1", TeamName: "fallback"}) {
+		t.Fatalf("expected f1 reported as fallback reviewer, got %+v", pr.FallbackReviewers)
+	}
+}
+
+func TestPRUsecaseCreateWithReviewers_SkipsUsersAtCapacity(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		createFn: func(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
+			return pr, nil
+		},
+		insertReviewerFn: func(ctx context.Context, prID, userID string) error { return nil },
+		addEventsFn:      func(ctx context.Context, evs ...*domain.AssignmentEvent) error { return nil },
+		countOpenReviewsFn: func(ctx context.Context, userIDs []string) (map[string]int, error) {
+			return map[string]int{"u2": 3, "u3": 1}, nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team"}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			return []*domain.User{
+				{ID: "u2", TeamName: teamName, IsActive: true},
+				{ID: "u3", TeamName: teamName, IsActive: true},
+				{ID: "u4", TeamName: teamName, IsActive: true},
+			}, nil
+		},
+		reviewLimitsFn: func(ctx context.Context, userIDs []string) (map[string]int, error) {
+			return map[string]int{"u2": 3, "u3": 1, "u4": 5}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: &teamRepositoryMock{}}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.CreateWithReviewers(context.Background(), &domain.PullRequest{ID: "pr1", AuthorID: "author"})
+	if err != nil {
+		t.Fatalf("CreateWithReviewers: %v", err)
+	}
+	if !reflect.DeepEqual(pr.Reviewers, []string{"u4"}) {
+		t.Fatalf("expected only u4 below capacity, got %v", pr.Reviewers)
+	}
+	if !reflect.DeepEqual(pr.SkippedAtCapacity, []string{"u2", "u3"}) {
+		t.Fatalf("unexpected skipped candidates: %v", pr.SkippedAtCapacity)
+	}
+}
+
 func TestPRUsecaseReassign_UsesReviewerTeam(t *testing.T) {
 	var teamAsked string
 	prRepo := &prRepositoryMock{
@@ -115,6 +229,13 @@ func TestPRUsecaseReassign_UsesReviewerTeam(t *testing.T) {
 			}
 			return nil
 		},
+		addEventsFn: func(ctx context.Context, events ...*domain.AssignmentEvent) error {
+			if len(events) != 1 || events[0].Type != domain.EventReassigned || events[0].PreviousUserID != "old" ||
+				events[0].UserID != "cand1" || events[0].Actor != "admin" || events[0].Reason != domain.ReasonManualReassign {
+				t.Fatalf("unexpected history events: %+v", events)
+			}
+			return nil
+		},
 		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
 			return []string{"old", "other"}, nil
 		},
@@ -128,10 +249,10 @@ func TestPRUsecaseReassign_UsesReviewerTeam(t *testing.T) {
 			return []*domain.User{{ID: "cand1", TeamName: teamName, IsActive: true}}, nil
 		},
 	}
-	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo}}
-	uc := NewPRUsecase(userRepo, prRepo, tx)
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: &teamRepositoryMock{}}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
 
-	pr, newID, err := uc.Reassign(context.Background(), "pr1", "old")
+	pr, newID, err := uc.Reassign(domain.WithActor(context.Background(), "admin"), "pr1", "old")
 	if err != nil {
 		t.Fatalf("Reassign: %v", err)
 	}
@@ -166,8 +287,8 @@ func TestPRUsecaseReassign_NoCandidate(t *testing.T) {
 			return nil, nil
 		},
 	}
-	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo}}
-	uc := NewPRUsecase(userRepo, prRepo, tx)
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: &teamRepositoryMock{}}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
 
 	_, _, err := uc.Reassign(context.Background(), "pr1", "old")
 	if !errors.Is(err, domain.ErrNoCandidate) {
@@ -175,9 +296,167 @@ func TestPRUsecaseReassign_NoCandidate(t *testing.T) {
 	}
 }
 
+func TestPRUsecaseReassign_UsesFallbackTeam(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, AuthorID: "author", Status: domain.StatusOpen}, nil
+		},
+		reviewerAssignedFn: func(ctx context.Context, prID, userID string) (bool, error) {
+			return true, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"old"}, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, events ...*domain.AssignmentEvent) error { return nil },
+		listStatesFn: func(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+			return []*domain.ReviewerState{{UserID: "f1", State: domain.ReviewPending}}, nil
+		},
+	}
+	var consulted []string
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team"}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			consulted = append(consulted, teamName)
+			if teamName == "second" {
+				return []*domain.User{{ID: "f1", TeamName: teamName, IsActive: true}}, nil
+			}
+			return nil, nil
+		},
+	}
+	teamRepo := &teamRepositoryMock{
+		fetchSettingsFn: func(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+			return &domain.TeamSettings{TeamName: teamName, MaxReviewers: 2, FallbackTeams: []string{"first", "second"}}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: teamRepo}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, newID, err := uc.Reassign(context.Background(), "pr1", "old")
+	if err != nil {
+		t.Fatalf("Reassign: %v", err)
+	}
+	if newID != "f1" {
+		t.Fatalf("expected f1, got %s", newID)
+	}
+	if !reflect.DeepEqual(consulted, []string{"team", "first", "second"}) {
+		t.Fatalf("unexpected team lookup order: %v", consulted)
+	}
+	if len(pr.FallbackReviewers) != 1 || pr.FallbackReviewers[0].TeamName != "second" {
+		t.Fatalf("expected replacement reported as fallback, got %+v", pr.FallbackReviewers)
+	}
+}
+
+func TestPRUsecaseCreateWithReviewers_DraftSkipsAssignment(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		createFn: func(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: pr.ID, AuthorID: pr.AuthorID, Status: pr.Status, IsDraft: pr.IsDraft}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: &userRepositoryMock{}}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.CreateWithReviewers(context.Background(), &domain.PullRequest{ID: "pr1", AuthorID: "author", IsDraft: true})
+	if err != nil {
+		t.Fatalf("CreateWithReviewers: %v", err)
+	}
+	if !pr.IsDraft || len(pr.Reviewers) != 0 {
+		t.Fatalf("expected draft without reviewers, got %+v", pr)
+	}
+}
+
+func TestPRUsecaseMarkReady_AssignsReviewers(t *testing.T) {
+	var (
+		reviewers []string
+		events    []*domain.AssignmentEvent
+	)
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, AuthorID: "author", Status: domain.StatusOpen, IsDraft: true}, nil
+		},
+		readyFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, AuthorID: "author", Status: domain.StatusOpen}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return append([]string(nil), reviewers...), nil
+		},
+		insertReviewerFn: func(ctx context.Context, prID, userID string) error {
+			reviewers = append(reviewers, userID)
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			events = append(events, evs...)
+			return nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team", IsActive: true}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			return []*domain.User{{ID: "u2"}, {ID: "u3"}, {ID: "u4"}}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: &teamRepositoryMock{}}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.MarkReady(context.Background(), "pr1")
+	if err != nil {
+		t.Fatalf("MarkReady: %v", err)
+	}
+	if pr.IsDraft || len(pr.Reviewers) != 2 {
+		t.Fatalf("expected ready PR with 2 reviewers, got %+v", pr)
+	}
+	if len(events) != 2 || events[0].Reason != domain.ReasonPRReady {
+		t.Fatalf("unexpected history events: %+v", events)
+	}
+}
+
+func TestPRUsecaseMarkReady_NotDraftIsNoop(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusOpen}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u2", "u3"}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.MarkReady(context.Background(), "pr1")
+	if err != nil {
+		t.Fatalf("MarkReady: %v", err)
+	}
+	if !reflect.DeepEqual(pr.Reviewers, []string{"u2", "u3"}) {
+		t.Fatalf("expected unchanged reviewers, got %v", pr.Reviewers)
+	}
+}
+
+func TestPRUsecaseMarkReady_ClosedPR(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusClosed, IsDraft: true}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.MarkReady(context.Background(), "pr1"); !errors.Is(err, domain.ErrPRClosed) {
+		t.Fatalf("expected ErrPRClosed, got %v", err)
+	}
+}
+
 func TestPRUsecaseMerge_ReturnsReviewers(t *testing.T) {
 	prRepo := &prRepositoryMock{
-		updateStatusFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusOpen}, nil
+		},
+		updateStatusFn: func(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
 			return &domain.PullRequest{ID: prID, Status: domain.StatusMerged}, nil
 		},
 		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
@@ -185,9 +464,9 @@ func TestPRUsecaseMerge_ReturnsReviewers(t *testing.T) {
 		},
 	}
 	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
-	uc := NewPRUsecase(nil, prRepo, tx)
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
 
-	pr, err := uc.Merge(context.Background(), "pr1")
+	pr, err := uc.Merge(context.Background(), "pr1", false)
 	if err != nil {
 		t.Fatalf("Merge: %v", err)
 	}
@@ -195,3 +474,477 @@ func TestPRUsecaseMerge_ReturnsReviewers(t *testing.T) {
 		t.Fatalf("expected reviewers in merge response, got %v", pr.Reviewers)
 	}
 }
+
+func TestPRUsecaseMerge_LocksPR(t *testing.T) {
+	locked := false
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			t.Fatalf("merge must read the PR with a row lock")
+			return nil, nil
+		},
+		fetchForUpdateFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			locked = true
+			return &domain.PullRequest{ID: prID, Status: domain.StatusOpen}, nil
+		},
+		listStatesFn: func(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+			return nil, nil
+		},
+		updateStatusFn: func(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusMerged}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return nil, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.Merge(context.Background(), "pr1", false); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if !locked {
+		t.Fatalf("expected PR row to be locked")
+	}
+}
+
+func mergeGateRepo(t *testing.T, states []*domain.ReviewerState, wantOverrideBy string) *prRepositoryMock {
+	return &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusOpen}, nil
+		},
+		listStatesFn: func(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+			return states, nil
+		},
+		updateStatusFn: func(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
+			if overrideBy != wantOverrideBy {
+				t.Fatalf("expected override by %q, got %q", wantOverrideBy, overrideBy)
+			}
+			return &domain.PullRequest{ID: prID, Status: domain.StatusMerged, MergeOverrideBy: overrideBy}, nil
+		},
+	}
+}
+
+func TestPRUsecaseMerge_BlockedByPolicy(t *testing.T) {
+	states := []*domain.ReviewerState{
+		{UserID: "u2", State: domain.ReviewApproved},
+		{UserID: "u3", State: domain.ReviewChangesRequested},
+	}
+	prRepo := mergeGateRepo(t, states, "")
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{MinApprovals: 2, BlockOnChangesRequested: true})
+
+	_, err := uc.Merge(context.Background(), "pr1", false)
+	if !errors.Is(err, domain.ErrMergeBlocked) {
+		t.Fatalf("expected ErrMergeBlocked, got %v", err)
+	}
+
+	var blocked *domain.MergeBlockedError
+	if !errors.As(err, &blocked) || len(blocked.Missing) != 2 {
+		t.Fatalf("expected both unmet conditions, got %v", err)
+	}
+}
+
+func TestPRUsecaseMerge_OverrideRecordsActor(t *testing.T) {
+	states := []*domain.ReviewerState{{UserID: "u2", State: domain.ReviewPending}}
+	prRepo := mergeGateRepo(t, states, "lead")
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{MinApprovals: 1, AllowOverride: true})
+
+	pr, err := uc.Merge(domain.WithActor(context.Background(), "lead"), "pr1", true)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if pr.MergeOverrideBy != "lead" {
+		t.Fatalf("expected override actor, got %q", pr.MergeOverrideBy)
+	}
+}
+
+func TestPRUsecaseMerge_OverrideDisabled(t *testing.T) {
+	states := []*domain.ReviewerState{{UserID: "u2", State: domain.ReviewPending}}
+	prRepo := mergeGateRepo(t, states, "")
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{MinApprovals: 1})
+
+	if _, err := uc.Merge(context.Background(), "pr1", true); !errors.Is(err, domain.ErrMergeBlocked) {
+		t.Fatalf("expected ErrMergeBlocked, got %v", err)
+	}
+}
+
+func TestPRUsecaseMerge_AlreadyMergedIsIdempotent(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusMerged}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u2"}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{MinApprovals: 2, BlockOnChangesRequested: true})
+
+	pr, err := uc.Merge(context.Background(), "pr1", false)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if pr.Status != domain.StatusMerged || !reflect.DeepEqual(pr.Reviewers, []string{"u2"}) {
+		t.Fatalf("unexpected merged PR: %+v", pr)
+	}
+}
+
+func TestPRUsecaseMerge_ClosedPR(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusClosed}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.Merge(context.Background(), "pr1", false); !errors.Is(err, domain.ErrPRClosed) {
+		t.Fatalf("expected ErrPRClosed, got %v", err)
+	}
+}
+
+func TestPRUsecaseMerge_DraftPR(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusOpen, IsDraft: true}, nil
+		},
+		updateStatusFn: func(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
+			t.Fatalf("draft PR must not be merged")
+			return nil, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.Merge(context.Background(), "pr1", true); !errors.Is(err, domain.ErrPRDraft) {
+		t.Fatalf("expected ErrPRDraft, got %v", err)
+	}
+}
+
+func TestPRUsecaseClose_MergedPR(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusMerged}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.Close(context.Background(), "pr1"); !errors.Is(err, domain.ErrPRMerged) {
+		t.Fatalf("expected ErrPRMerged, got %v", err)
+	}
+}
+
+func TestPRUsecaseClose_KeepsReviewers(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusOpen}, nil
+		},
+		closeFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusClosed}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u2"}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.Close(context.Background(), "pr1")
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if pr.Status != domain.StatusClosed || !reflect.DeepEqual(pr.Reviewers, []string{"u2"}) {
+		t.Fatalf("unexpected closed PR: %+v", pr)
+	}
+}
+
+func TestPRUsecaseReassign_ClosedPR(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusClosed}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, _, err := uc.Reassign(context.Background(), "pr1", "u2"); !errors.Is(err, domain.ErrPRClosed) {
+		t.Fatalf("expected ErrPRClosed, got %v", err)
+	}
+}
+
+func TestPRUsecaseReopen_ReplacesInactiveReviewers(t *testing.T) {
+	var (
+		reviewers = []string{"active", "gone"}
+		removed   []string
+		added     []string
+		events    []*domain.AssignmentEvent
+	)
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, AuthorID: "author", Status: domain.StatusClosed}, nil
+		},
+		reopenFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, AuthorID: "author", Status: domain.StatusOpen}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return append([]string(nil), reviewers...), nil
+		},
+		removeReviewerFn: func(ctx context.Context, prID, userID string) error {
+			removed = append(removed, userID)
+			reviewers = slices.DeleteFunc(reviewers, func(id string) bool { return id == userID })
+			return nil
+		},
+		insertReviewerFn: func(ctx context.Context, prID, userID string) error {
+			added = append(added, userID)
+			reviewers = append(reviewers, userID)
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			events = append(events, evs...)
+			return nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team", IsActive: id != "gone"}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			if !reflect.DeepEqual(excludeIDs, []string{"author", "active"}) {
+				t.Fatalf("unexpected exclude list: %v", excludeIDs)
+			}
+			return []*domain.User{{ID: "fresh", TeamName: teamName}}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: &teamRepositoryMock{}}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.Reopen(context.Background(), "pr1")
+	if err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	if pr.Status != domain.StatusOpen || !reflect.DeepEqual(pr.Reviewers, []string{"active", "fresh"}) {
+		t.Fatalf("unexpected reopened PR: %+v", pr)
+	}
+	if !reflect.DeepEqual(removed, []string{"gone"}) || !reflect.DeepEqual(added, []string{"fresh"}) {
+		t.Fatalf("unexpected changes: removed %v, added %v", removed, added)
+	}
+	if len(events) != 2 || events[0].Type != domain.EventUnassigned || events[1].Type != domain.EventAssigned ||
+		events[1].Reason != domain.ReasonPRReopened {
+		t.Fatalf("unexpected history events: %+v", events)
+	}
+}
+
+func TestPRUsecaseReopen_MergedPR(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusMerged}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.Reopen(context.Background(), "pr1"); !errors.Is(err, domain.ErrPRMerged) {
+		t.Fatalf("expected ErrPRMerged, got %v", err)
+	}
+}
+
+func TestPRUsecaseSubmitReview_StoresVerdict(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusOpen}, nil
+		},
+		updateReviewFn: func(ctx context.Context, prID, userID string, state domain.ReviewState) error {
+			if userID != "u2" || state != domain.ReviewApproved {
+				t.Fatalf("unexpected verdict: %s %s", userID, state)
+			}
+			return nil
+		},
+		listStatesFn: func(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+			return []*domain.ReviewerState{
+				{UserID: "u2", State: domain.ReviewApproved},
+				{UserID: "u3", State: domain.ReviewPending},
+			}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	pr, err := uc.SubmitReview(context.Background(), "pr1", "u2", domain.ReviewApproved)
+	if err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if !reflect.DeepEqual(pr.Reviewers, []string{"u2", "u3"}) || pr.ReviewerStates[0].State != domain.ReviewApproved {
+		t.Fatalf("unexpected reviewers: %+v", pr)
+	}
+}
+
+func TestPRUsecaseSubmitReview_RejectsPending(t *testing.T) {
+	uc := NewPRUsecase(nil, &prRepositoryMock{}, nil, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.SubmitReview(context.Background(), "pr1", "u2", domain.ReviewPending); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestPRUsecaseSubmitReview_MergedPR(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, Status: domain.StatusMerged}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.SubmitReview(context.Background(), "pr1", "u2", domain.ReviewChangesRequested); !errors.Is(err, domain.ErrPRMerged) {
+		t.Fatalf("expected ErrPRMerged, got %v", err)
+	}
+}
+
+func TestPRUsecaseHistory(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID}, nil
+		},
+		listEventsFn: func(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+			return []*domain.AssignmentEvent{{ID: 1, PRID: prID, Type: domain.EventAssigned, UserID: "u2"}}, nil
+		},
+	}
+	uc := NewPRUsecase(nil, prRepo, nil, NewRandomSelector(), MergePolicy{})
+
+	events, err := uc.History(context.Background(), "pr1")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(events) != 1 || events[0].UserID != "u2" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestPRUsecaseHistory_NotFound(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+	uc := NewPRUsecase(nil, prRepo, nil, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.History(context.Background(), "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPRUsecaseGet_BuildsTimeline(t *testing.T) {
+	created := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	merged := created.Add(2 * time.Hour)
+
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return &domain.PullRequest{ID: prID, AuthorID: "u1", Status: domain.StatusMerged, CreatedAt: &created, MergedAt: &merged}, nil
+		},
+		listStatesFn: func(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+			return []*domain.ReviewerState{
+				{UserID: "u3", State: domain.ReviewApproved},
+				{UserID: "u4", State: domain.ReviewPending},
+			}, nil
+		},
+		listEventsFn: func(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+			return []*domain.AssignmentEvent{
+				{ID: 1, Type: domain.EventAssigned, UserID: "u2", CreatedAt: created},
+				{ID: 2, Type: domain.EventAssigned, UserID: "u4", CreatedAt: created},
+				{ID: 3, Type: domain.EventReassigned, UserID: "u3", PreviousUserID: "u2", CreatedAt: created.Add(time.Hour)},
+			}, nil
+		},
+	}
+	var requested []string
+	userRepo := &userRepositoryMock{
+		fetchByIDsFn: func(ctx context.Context, ids []string) ([]*domain.User, error) {
+			requested = ids
+			return []*domain.User{
+				{ID: "u1", Name: "Alice", TeamName: "backend"},
+				{ID: "u3", Name: "Carol", TeamName: "backend"},
+				{ID: "u4", Name: "Dan", TeamName: "backend"},
+			}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo, User: userRepo, Team: &teamRepositoryMock{}}}
+	uc := NewPRUsecase(userRepo, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	details, err := uc.Get(context.Background(), "pr1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(requested) != 3 {
+		t.Fatalf("expected profiles fetched in one call for author and reviewers, got %v", requested)
+	}
+	if details.Author == nil || details.Author.Name != "Alice" {
+		t.Fatalf("unexpected author: %+v", details.Author)
+	}
+	if len(details.Reviewers) != 2 || details.Reviewers[0].ID != "u3" || details.Reviewers[1].ID != "u4" {
+		t.Fatalf("unexpected reviewers: %+v", details.Reviewers)
+	}
+
+	want := []domain.TimelineEventType{
+		domain.TimelineCreated, domain.TimelineAssigned, domain.TimelineAssigned, domain.TimelineReassigned, domain.TimelineMerged,
+	}
+	if len(details.Timeline) != len(want) {
+		t.Fatalf("unexpected timeline: %+v", details.Timeline)
+	}
+	for i, e := range details.Timeline {
+		if e.Type != want[i] {
+			t.Fatalf("timeline[%d]: expected %s, got %s", i, want[i], e.Type)
+		}
+	}
+}
+
+func TestPRUsecaseGet_NotFound(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, prID string) (*domain.PullRequest, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{PR: prRepo}}
+	uc := NewPRUsecase(nil, prRepo, tx, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.Get(context.Background(), "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPRUsecaseList_AppliesDefaults(t *testing.T) {
+	var got domain.PRFilter
+	prRepo := &prRepositoryMock{
+		listFn: func(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+			got = filter
+			return &domain.Page[*domain.PullRequest]{}, nil
+		},
+	}
+	uc := NewPRUsecase(nil, prRepo, nil, NewRandomSelector(), MergePolicy{})
+
+	if _, err := uc.List(context.Background(), domain.PRFilter{Status: domain.StatusOpen}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got.Sort != domain.SortByCreatedAt || got.Order != domain.SortDesc || got.Limit != DefaultPageLimit {
+		t.Fatalf("unexpected defaults: %+v", got.PageParams)
+	}
+}
+
+func TestPRUsecaseList_InvalidInput(t *testing.T) {
+	uc := NewPRUsecase(nil, &prRepositoryMock{}, nil, NewRandomSelector(), MergePolicy{})
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	for name, filter := range map[string]domain.PRFilter{
+		"status": {Status: "DRAFT"},
+		"range":  {CreatedFrom: &from, CreatedTo: &to},
+		"sort":   {PageParams: domain.PageParams{Sort: "name"}},
+		"order":  {PageParams: domain.PageParams{Order: "up"}},
+		"limit":  {PageParams: domain.PageParams{Limit: MaxPageLimit + 1}},
+	} {
+		if _, err := uc.List(context.Background(), filter); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("%s: expected ErrInvalidInput, got %v", name, err)
+		}
+	}
+}
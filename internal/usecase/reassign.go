@@ -0,0 +1,393 @@
+package usecase
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"errors"
+)
+
+// assignmentTx — параметры транзакций, которые выбирают ревьюверов. SERIALIZABLE не дает параллельным запросам
+// выбрать одного кандидата сверх его лимита открытых ревью; конфликт сериализации TxManager разрешает повтором.
+var assignmentTx = []domain.TxOption{domain.WithIsolation(domain.Serializable)}
+
+// replaceReviewer подбирает замену ревьюверу из его команды, обновляет назначение на PR и пишет событие в историю.
+// Если в команде ревьювера кандидатов нет, замена ищется в команде автора и затем в ее резервных командах;
+// замена из резервной команды добавляется в pr.FallbackReviewers. Автор и уже назначенные ревьюверы исключаются.
+// Если кандидатов нет, возвращает domain.ErrNoCandidate.
+func replaceReviewer(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, pr *domain.PullRequest, oldReviewer *domain.User, reason string) (string, error) {
+	currentReviewers, err := repos.PR.ListReviewers(ctx, pr.ID)
+	if err != nil {
+		return "", err
+	}
+
+	excludeIDs := make([]string, 0, len(currentReviewers)+2)
+	excludeIDs = append(excludeIDs, oldReviewer.ID, pr.AuthorID)
+	for _, reviewerID := range currentReviewers {
+		if reviewerID != oldReviewer.ID {
+			excludeIDs = append(excludeIDs, reviewerID)
+		}
+	}
+
+	teamName := oldReviewer.TeamName
+	revs, skipped, err := activeCandidates(ctx, repos, teamName, excludeIDs)
+	if err != nil {
+		return "", err
+	}
+	pr.SkippedAtCapacity = append(pr.SkippedAtCapacity, skipped...)
+
+	fromFallback := false
+	if len(revs) == 0 {
+		teamName, revs, fromFallback, err = replacementFromAuthorTeams(ctx, repos, pr, oldReviewer.TeamName, excludeIDs)
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(revs) == 0 {
+		return "", domain.ErrNoCandidate
+	}
+
+	picked, err := selector.Select(ctx, repos, teamName, revs, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(picked) == 0 {
+		return "", domain.ErrNoCandidate
+	}
+	newRevID := picked[0].ID
+
+	if err := repos.PR.ReplaceReviewer(ctx, pr.ID, oldReviewer.ID, newRevID); err != nil {
+		return "", err
+	}
+
+	err = repos.PR.AddAssignmentEvents(ctx, &domain.AssignmentEvent{
+		PRID:           pr.ID,
+		Type:           domain.EventReassigned,
+		UserID:         newRevID,
+		PreviousUserID: oldReviewer.ID,
+		Actor:          domain.ActorFromContext(ctx),
+		Reason:         reason,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if fromFallback {
+		pr.FallbackReviewers = append(pr.FallbackReviewers, &domain.FallbackReviewer{UserID: newRevID, TeamName: teamName})
+	}
+
+	return newRevID, nil
+}
+
+// replacementFromAuthorTeams ищет кандидатов в команде автора PR, а затем по порядку в ее резервных командах,
+// пропуская уже проверенную команду skipTeam. Возвращает первую команду с кандидатами и признак резервной команды;
+// пропущенные из-за лимита открытых ревью добавляются в pr.SkippedAtCapacity.
+func replacementFromAuthorTeams(ctx context.Context, repos *domain.Repos, pr *domain.PullRequest, skipTeam string, excludeIDs []string) (string, []*domain.User, bool, error) {
+	author, err := repos.User.FetchByID(ctx, pr.AuthorID)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if author.TeamName == "" {
+		return "", nil, false, nil
+	}
+
+	settings, err := repos.Team.FetchSettings(ctx, author.TeamName)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	for i, teamName := range append([]string{author.TeamName}, settings.FallbackTeams...) {
+		if teamName == skipTeam {
+			continue
+		}
+
+		revs, skipped, err := activeCandidates(ctx, repos, teamName, excludeIDs)
+		if err != nil {
+			return "", nil, false, err
+		}
+		pr.SkippedAtCapacity = append(pr.SkippedAtCapacity, skipped...)
+		if len(revs) > 0 {
+			return teamName, revs, i > 0, nil
+		}
+	}
+
+	return "", nil, false, nil
+}
+
+// reassignTeamReviews одним запросом к хранилищу передает открытые ревью пользователей userIDs другим участникам
+// команды teamName по ее стратегии выбора ревьюверов. Для round_robin курсор команды читается до подбора замен
+// и сдвигается на последнего назначенного. PR без подходящего кандидата возвращаются с пустым NewReviewerID.
+func reassignTeamReviews(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, teamName string, userIDs []string, reason string) ([]*domain.ReviewReassignment, error) {
+	strategy, weights := bulkStrategy(selector, teamName)
+	req := domain.BulkReassign{
+		UserIDs:  userIDs,
+		Strategy: strategy,
+		Weights:  weights,
+		Actor:    domain.ActorFromContext(ctx),
+		Reason:   reason,
+	}
+
+	if strategy == domain.ReassignRoundRobin {
+		cursor, err := repos.Team.LockReviewCursor(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		req.Cursor = cursor
+	}
+
+	reassignments, err := repos.PR.ReassignOpenReviews(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if strategy == domain.ReassignRoundRobin {
+		for i := len(reassignments) - 1; i >= 0; i-- {
+			if id := reassignments[i].NewReviewerID; id != "" {
+				if err := repos.Team.UpdateReviewCursor(ctx, teamName, id); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	return reassignments, nil
+}
+
+// reassignOpenReviews передает открытые ревью пользователя другим активным участникам команды user.TeamName.
+// PR без подходящего кандидата возвращаются с пустым NewReviewerID.
+func reassignOpenReviews(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, user *domain.User, reason string) ([]*domain.ReviewReassignment, error) {
+	prs, err := repos.PR.ListReviewableByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reassignments []*domain.ReviewReassignment
+	for _, pr := range prs {
+		if pr.Status != domain.StatusOpen {
+			continue
+		}
+
+		newRevID, err := replaceReviewer(ctx, repos, selector, pr, user, reason)
+		if err != nil && !errors.Is(err, domain.ErrNoCandidate) {
+			return nil, err
+		}
+
+		reassignments = append(reassignments, &domain.ReviewReassignment{
+			PRID:          pr.ID,
+			OldReviewerID: user.ID,
+			NewReviewerID: newRevID,
+		})
+	}
+
+	return reassignments, nil
+}
+
+// reassignToAuthorTeams передает открытые ревью пользователя без команды активным коллегам авторов PR.
+// PR без подходящего кандидата возвращаются с пустым NewReviewerID.
+func reassignToAuthorTeams(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, userID, reason string) ([]*domain.ReviewReassignment, error) {
+	prs, err := repos.PR.ListReviewableByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reassignments []*domain.ReviewReassignment
+	for _, pr := range prs {
+		if pr.Status != domain.StatusOpen {
+			continue
+		}
+
+		author, err := repos.User.FetchByID(ctx, pr.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+
+		// кандидаты ищутся в команде автора, как при создании PR
+		newRevID, err := replaceReviewer(ctx, repos, selector, pr, &domain.User{ID: userID, TeamName: author.TeamName}, reason)
+		if err != nil && !errors.Is(err, domain.ErrNoCandidate) {
+			return nil, err
+		}
+
+		reassignments = append(reassignments, &domain.ReviewReassignment{
+			PRID:          pr.ID,
+			OldReviewerID: userID,
+			NewReviewerID: newRevID,
+		})
+	}
+
+	return reassignments, nil
+}
+
+// handOffReviews передает открытые ревью пользователя заместителю в обход стратегии выбора и лимита открытых ревью.
+// PR, где заместитель — автор или уже ревьювер, остаются без замены и возвращаются с пустым NewReviewerID.
+func handOffReviews(ctx context.Context, repos *domain.Repos, userID, delegateID string) ([]*domain.ReviewReassignment, error) {
+	prs, err := repos.PR.ListReviewableByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reassignments []*domain.ReviewReassignment
+	for _, pr := range prs {
+		if pr.Status != domain.StatusOpen {
+			continue
+		}
+
+		ra := &domain.ReviewReassignment{PRID: pr.ID, OldReviewerID: userID}
+		reassignments = append(reassignments, ra)
+
+		if pr.AuthorID == delegateID {
+			continue
+		}
+		assigned, err := repos.PR.ReviewerAssigned(ctx, pr.ID, delegateID)
+		if err != nil {
+			return nil, err
+		}
+		if assigned {
+			continue
+		}
+
+		if err := repos.PR.ReplaceReviewer(ctx, pr.ID, userID, delegateID); err != nil {
+			return nil, err
+		}
+		err = repos.PR.AddAssignmentEvents(ctx, &domain.AssignmentEvent{
+			PRID:           pr.ID,
+			Type:           domain.EventReassigned,
+			UserID:         delegateID,
+			PreviousUserID: userID,
+			Actor:          domain.ActorFromContext(ctx),
+			Reason:         domain.ReasonUserAbsent,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ra.NewReviewerID = delegateID
+	}
+
+	return reassignments, nil
+}
+
+// assignReviewers выбирает до n ревьюверов из кандидатов, назначает их на PR и пишет события в историю.
+func assignReviewers(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, prID, teamName string, candidates []*domain.User, n int, reason string) ([]string, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	reviewers, err := selector.Select(ctx, repos, teamName, candidates, n)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewerIDs := make([]string, 0, len(reviewers))
+	events := make([]*domain.AssignmentEvent, 0, len(reviewers))
+	for _, r := range reviewers {
+		if err := repos.PR.InsertReviewer(ctx, prID, r.ID); err != nil {
+			return nil, err
+		}
+		reviewerIDs = append(reviewerIDs, r.ID)
+		events = append(events, &domain.AssignmentEvent{
+			PRID:   prID,
+			Type:   domain.EventAssigned,
+			UserID: r.ID,
+			Actor:  domain.ActorFromContext(ctx),
+			Reason: reason,
+		})
+	}
+
+	if err := repos.PR.AddAssignmentEvents(ctx, events...); err != nil {
+		return nil, err
+	}
+
+	return reviewerIDs, nil
+}
+
+// assignFromAuthorTeam добирает ревьюверов до max_reviewers команды автора PR: сначала из активных коллег автора,
+// затем по порядку из резервных команд, исключая автора, уже назначенных и достигших лимита открытых ревью.
+// Назначенные из резервных команд добавляются в pr.FallbackReviewers, пропущенные из-за лимита — в pr.SkippedAtCapacity,
+// а в pr.MissingReviewers записывается, скольких не хватило до min_reviewers.
+func assignFromAuthorTeam(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, pr *domain.PullRequest, assigned []string, reason string) ([]string, error) {
+	author, err := repos.User.FetchByID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := domain.DefaultTeamSettings(author.TeamName)
+	if author.TeamName != "" {
+		settings, err = repos.Team.FetchSettings(ctx, author.TeamName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	excludeIDs := append([]string{author.ID}, assigned...)
+	var picked []string
+	for i, teamName := range append([]string{author.TeamName}, settings.FallbackTeams...) {
+		n := settings.MaxReviewers - len(assigned) - len(picked)
+		if n <= 0 {
+			break
+		}
+		if teamName == "" {
+			continue
+		}
+
+		candidates, skipped, err := activeCandidates(ctx, repos, teamName, excludeIDs)
+		if err != nil {
+			return nil, err
+		}
+		pr.SkippedAtCapacity = append(pr.SkippedAtCapacity, skipped...)
+
+		ids, err := assignReviewers(ctx, repos, selector, pr.ID, teamName, candidates, n, reason)
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, ids...)
+		excludeIDs = append(excludeIDs, ids...)
+
+		if i > 0 {
+			for _, id := range ids {
+				pr.FallbackReviewers = append(pr.FallbackReviewers, &domain.FallbackReviewer{UserID: id, TeamName: teamName})
+			}
+		}
+	}
+
+	pr.MissingReviewers = max(0, settings.MinReviewers-len(assigned)-len(picked))
+
+	return picked, nil
+}
+
+// activeCandidates возвращает активных участников команды, кроме excludeIDs, у которых не исчерпан лимит открытых ревью,
+// и id участников, пропущенных из-за лимита
+func activeCandidates(ctx context.Context, repos *domain.Repos, teamName string, excludeIDs []string) ([]*domain.User, []string, error) {
+	users, err := repos.User.FetchActiveByTeam(ctx, teamName, excludeIDs...)
+	if err != nil || len(users) == 0 {
+		return users, nil, err
+	}
+
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+
+	limits, err := repos.User.FetchReviewLimits(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(limits) == 0 {
+		return users, nil, nil
+	}
+
+	load, err := repos.PR.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	available := make([]*domain.User, 0, len(users))
+	var skipped []string
+	for _, u := range users {
+		if limit, ok := limits[u.ID]; ok && load[u.ID] >= limit {
+			skipped = append(skipped, u.ID)
+			continue
+		}
+		available = append(available, u)
+	}
+
+	return available, skipped, nil
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyRoundRobin  = "round_robin"
+	StrategyWeighted    = "weighted"
+)
+
+var ErrUnknownStrategy = errors.New("unknown reviewer selection strategy")
+
+// ReviewerSelector выбирает до n ревьюверов из уже отфильтрованных кандидатов команды.
+// repos привязаны к текущей транзакции, поэтому стратегия может читать и менять состояние БД.
+type ReviewerSelector interface {
+	Select(ctx context.Context, repos *domain.Repos, teamName string, candidates []*domain.User, n int) ([]*domain.User, error)
+}
+
+// bulkSelector — стратегия, которую хранилище воспроизводит при массовом переназначении одним запросом
+type bulkSelector interface {
+	bulkStrategy(teamName string) (domain.ReassignStrategy, map[string]int)
+}
+
+// bulkStrategy возвращает стратегию команды teamName для массового переназначения. Стратегии, которые хранилище
+// не воспроизводит, заменяются least_loaded.
+func bulkStrategy(selector ReviewerSelector, teamName string) (domain.ReassignStrategy, map[string]int) {
+	if s, ok := selector.(bulkSelector); ok {
+		return s.bulkStrategy(teamName)
+	}
+	return domain.ReassignLeastLoaded, nil
+}
+
+// NewReviewerSelector создает стратегию по имени. weights используются только стратегией weighted.
+func NewReviewerSelector(strategy string, weights map[string]int) (ReviewerSelector, error) {
+	switch strategy {
+	case StrategyRandom:
+		return NewRandomSelector(), nil
+	case StrategyLeastLoaded:
+		return NewLeastLoadedSelector(), nil
+	case StrategyRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case StrategyWeighted:
+		return NewWeightedSelector(weights), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, strategy)
+	}
+}
+
+// NewConfiguredReviewerSelector собирает глобальную стратегию и переопределения для отдельных команд.
+// Одноименные стратегии разделяют один экземпляр.
+func NewConfiguredReviewerSelector(defaultStrategy string, teamStrategies map[string]string, weights map[string]int) (ReviewerSelector, error) {
+	instances := make(map[string]ReviewerSelector)
+	build := func(strategy string) (ReviewerSelector, error) {
+		if s, ok := instances[strategy]; ok {
+			return s, nil
+		}
+		s, err := NewReviewerSelector(strategy, weights)
+		if err != nil {
+			return nil, err
+		}
+		instances[strategy] = s
+		return s, nil
+	}
+
+	def, err := build(defaultStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	perTeam := make(map[string]ReviewerSelector, len(teamStrategies))
+	for team, strategy := range teamStrategies {
+		s, err := build(strategy)
+		if err != nil {
+			return nil, fmt.Errorf("team %s: %w", team, err)
+		}
+		perTeam[team] = s
+	}
+
+	return NewTeamReviewerSelector(def, perTeam), nil
+}
+
+type teamReviewerSelector struct {
+	def     ReviewerSelector
+	perTeam map[string]ReviewerSelector
+}
+
+// NewTeamReviewerSelector делегирует выбор стратегии команды, а при ее отсутствии — стратегии по умолчанию.
+func NewTeamReviewerSelector(def ReviewerSelector, perTeam map[string]ReviewerSelector) ReviewerSelector {
+	return &teamReviewerSelector{def: def, perTeam: perTeam}
+}
+
+func (s *teamReviewerSelector) Select(ctx context.Context, repos *domain.Repos, teamName string, candidates []*domain.User, n int) ([]*domain.User, error) {
+	if sel, ok := s.perTeam[teamName]; ok {
+		return sel.Select(ctx, repos, teamName, candidates, n)
+	}
+	return s.def.Select(ctx, repos, teamName, candidates, n)
+}
+
+func (s *teamReviewerSelector) bulkStrategy(teamName string) (domain.ReassignStrategy, map[string]int) {
+	if sel, ok := s.perTeam[teamName]; ok {
+		return bulkStrategy(sel, teamName)
+	}
+	return bulkStrategy(s.def, teamName)
+}
+
+type randomSelector struct{}
+
+// NewRandomSelector выбирает кандидатов равновероятно.
+func NewRandomSelector() ReviewerSelector {
+	return randomSelector{}
+}
+
+func (randomSelector) Select(_ context.Context, _ *domain.Repos, _ string, candidates []*domain.User, n int) ([]*domain.User, error) {
+	shuffled := append([]*domain.User(nil), candidates...)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return firstN(shuffled, n), nil
+}
+
+func (randomSelector) bulkStrategy(string) (domain.ReassignStrategy, map[string]int) {
+	return domain.ReassignRandom, nil
+}
+
+type leastLoadedSelector struct{}
+
+// NewLeastLoadedSelector выбирает кандидатов с наименьшим числом открытых ревью, при равенстве — случайно.
+func NewLeastLoadedSelector() ReviewerSelector {
+	return leastLoadedSelector{}
+}
+
+func (leastLoadedSelector) Select(ctx context.Context, repos *domain.Repos, _ string, candidates []*domain.User, n int) ([]*domain.User, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.ID)
+	}
+
+	load, err := repos.PR.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := append([]*domain.User(nil), candidates...)
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return load[ordered[i].ID] < load[ordered[j].ID]
+	})
+
+	return firstN(ordered, n), nil
+}
+
+func (leastLoadedSelector) bulkStrategy(string) (domain.ReassignStrategy, map[string]int) {
+	return domain.ReassignLeastLoaded, nil
+}
+
+type roundRobinSelector struct{}
+
+// NewRoundRobinSelector обходит кандидатов команды по кругу в порядке user_id.
+// Курсор хранится в БД и сдвигается в той же транзакции, что и назначение ревьюверов,
+// поэтому параллельные назначения в одной команде не получают один и тот же слот.
+func NewRoundRobinSelector() ReviewerSelector {
+	return roundRobinSelector{}
+}
+
+func (roundRobinSelector) Select(ctx context.Context, repos *domain.Repos, teamName string, candidates []*domain.User, n int) ([]*domain.User, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	lastID, err := repos.Team.LockReviewCursor(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	picked := rotate(candidates, lastID, n)
+	if err := repos.Team.UpdateReviewCursor(ctx, teamName, picked[len(picked)-1].ID); err != nil {
+		return nil, err
+	}
+
+	return picked, nil
+}
+
+func (roundRobinSelector) bulkStrategy(string) (domain.ReassignStrategy, map[string]int) {
+	return domain.ReassignRoundRobin, nil
+}
+
+type weightedSelector struct {
+	weights map[string]int
+}
+
+// NewWeightedSelector выбирает кандидатов случайно пропорционально весу (по умолчанию 1).
+func NewWeightedSelector(weights map[string]int) ReviewerSelector {
+	return &weightedSelector{weights: weights}
+}
+
+func (s *weightedSelector) Select(_ context.Context, _ *domain.Repos, _ string, candidates []*domain.User, n int) ([]*domain.User, error) {
+	// Взвешенная выборка без возвращения (Efraimidis–Spirakis): ключ u^(1/w), берем n наибольших.
+	type keyed struct {
+		user *domain.User
+		key  float64
+	}
+
+	items := make([]keyed, 0, len(candidates))
+	for _, c := range candidates {
+		w, ok := s.weights[c.ID]
+		if !ok || w <= 0 {
+			w = 1
+		}
+		items = append(items, keyed{user: c, key: math.Pow(rand.Float64(), 1/float64(w))})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key > items[j].key
+	})
+
+	res := make([]*domain.User, 0, len(items))
+	for _, it := range items {
+		res = append(res, it.user)
+	}
+
+	return firstN(res, n), nil
+}
+
+func (s *weightedSelector) bulkStrategy(string) (domain.ReassignStrategy, map[string]int) {
+	return domain.ReassignWeighted, s.weights
+}
+
+// rotate сортирует кандидатов по user_id и возвращает до n пользователей, следующих за lastID по кругу.
+func rotate(candidates []*domain.User, lastID string, n int) []*domain.User {
+	ordered := append([]*domain.User(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	start := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].ID > lastID
+	})
+
+	res := make([]*domain.User, 0, min(n, len(ordered)))
+	for i := 0; i < len(ordered) && len(res) < n; i++ {
+		res = append(res, ordered[(start+i)%len(ordered)])
+	}
+
+	return res
+}
+
+func firstN(users []*domain.User, n int) []*domain.User {
+	if len(users) > n {
+		return users[:n]
+	}
+	return users
+}
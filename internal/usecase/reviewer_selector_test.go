@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func userIDs(users []*domain.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func TestRandomSelector_ReturnsAtMostN(t *testing.T) {
+	candidates := []*domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}}
+
+	picked, err := NewRandomSelector().Select(context.Background(), nil, "team", candidates, 2)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if len(picked) != 2 || picked[0].ID == picked[1].ID {
+		t.Fatalf("expected 2 distinct reviewers, got %v", userIDs(picked))
+	}
+	if !reflect.DeepEqual(userIDs(candidates), []string{"u1", "u2", "u3"}) {
+		t.Fatalf("candidates slice must not be modified: %v", userIDs(candidates))
+	}
+}
+
+func cursorTeamRepo(cursors map[string]string) *teamRepositoryMock {
+	return &teamRepositoryMock{
+		lockReviewCursorFn: func(ctx context.Context, teamName string) (string, error) {
+			return cursors[teamName], nil
+		},
+		updateReviewCursorFn: func(ctx context.Context, teamName, lastUserID string) error {
+			cursors[teamName] = lastUserID
+			return nil
+		},
+	}
+}
+
+func TestRoundRobinSelector_RotatesPerTeam(t *testing.T) {
+	s := NewRoundRobinSelector()
+	cursors := make(map[string]string)
+	repos := &domain.Repos{Team: cursorTeamRepo(cursors)}
+	candidates := []*domain.User{{ID: "u3"}, {ID: "u1"}, {ID: "u2"}}
+	ctx := context.Background()
+
+	want := [][]string{{"u1", "u2"}, {"u3", "u1"}, {"u2", "u3"}}
+	for i, w := range want {
+		picked, err := s.Select(ctx, repos, "team", candidates, 2)
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		if !reflect.DeepEqual(userIDs(picked), w) {
+			t.Fatalf("round %d: expected %v, got %v", i, w, userIDs(picked))
+		}
+	}
+
+	picked, err := s.Select(ctx, repos, "other", candidates, 1)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if !reflect.DeepEqual(userIDs(picked), []string{"u1"}) {
+		t.Fatalf("other team must have its own cursor, got %v", userIDs(picked))
+	}
+	if cursors["team"] != "u3" || cursors["other"] != "u1" {
+		t.Fatalf("unexpected stored cursors: %v", cursors)
+	}
+}
+
+func TestRoundRobinSelector_SkipsMissingCursorUser(t *testing.T) {
+	s := NewRoundRobinSelector()
+	repos := &domain.Repos{Team: cursorTeamRepo(map[string]string{"team": "u2"})}
+
+	picked, err := s.Select(context.Background(), repos, "team", []*domain.User{{ID: "u1"}, {ID: "u3"}}, 1)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if !reflect.DeepEqual(userIDs(picked), []string{"u3"}) {
+		t.Fatalf("expected rotation to continue after u2, got %v", userIDs(picked))
+	}
+}
+
+func TestRoundRobinSelector_NoCandidatesKeepsCursor(t *testing.T) {
+	repos := &domain.Repos{Team: &teamRepositoryMock{}}
+
+	picked, err := NewRoundRobinSelector().Select(context.Background(), repos, "team", nil, 2)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if len(picked) != 0 {
+		t.Fatalf("expected no reviewers, got %v", userIDs(picked))
+	}
+}
+
+func TestWeightedSelector_PrefersHeavierCandidates(t *testing.T) {
+	s := NewWeightedSelector(map[string]int{"heavy": 50})
+	candidates := []*domain.User{{ID: "light"}, {ID: "heavy"}}
+
+	heavyFirst := 0
+	for i := 0; i < 200; i++ {
+		picked, err := s.Select(context.Background(), nil, "team", candidates, 1)
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		if picked[0].ID == "heavy" {
+			heavyFirst++
+		}
+	}
+
+	if heavyFirst < 150 {
+		t.Fatalf("expected heavy candidate to win most draws, got %d of 200", heavyFirst)
+	}
+}
+
+type selectorFunc func(teamName string, candidates []*domain.User, n int) []*domain.User
+
+func (f selectorFunc) Select(_ context.Context, _ *domain.Repos, teamName string, candidates []*domain.User, n int) ([]*domain.User, error) {
+	return f(teamName, candidates, n), nil
+}
+
+func TestTeamReviewerSelector_UsesTeamOverride(t *testing.T) {
+	var used string
+	def := selectorFunc(func(string, []*domain.User, int) []*domain.User {
+		used = "default"
+		return nil
+	})
+	override := selectorFunc(func(string, []*domain.User, int) []*domain.User {
+		used = "override"
+		return nil
+	})
+
+	s := NewTeamReviewerSelector(def, map[string]ReviewerSelector{"special": override})
+
+	if _, err := s.Select(context.Background(), nil, "special", nil, 1); err != nil || used != "override" {
+		t.Fatalf("expected override for special team, got %s (%v)", used, err)
+	}
+	if _, err := s.Select(context.Background(), nil, "plain", nil, 1); err != nil || used != "default" {
+		t.Fatalf("expected default for plain team, got %s (%v)", used, err)
+	}
+}
+
+func TestNewConfiguredReviewerSelector_UnknownStrategy(t *testing.T) {
+	_, err := NewConfiguredReviewerSelector(StrategyRandom, map[string]string{"team": "nope"}, nil)
+	if !errors.Is(err, ErrUnknownStrategy) {
+		t.Fatalf("expected ErrUnknownStrategy, got %v", err)
+	}
+}
+
+func TestLeastLoadedSelector_PrefersFewestOpenReviews(t *testing.T) {
+	var asked []string
+	prRepo := &prRepositoryMock{
+		countOpenReviewsFn: func(ctx context.Context, ids []string) (map[string]int, error) {
+			asked = ids
+			return map[string]int{"busy": 5, "mid": 1}, nil
+		},
+	}
+	repos := &domain.Repos{PR: prRepo}
+	candidates := []*domain.User{{ID: "busy"}, {ID: "mid"}, {ID: "free"}}
+
+	picked, err := NewLeastLoadedSelector().Select(context.Background(), repos, "team", candidates, 2)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if !reflect.DeepEqual(userIDs(picked), []string{"free", "mid"}) {
+		t.Fatalf("expected least loaded reviewers, got %v", userIDs(picked))
+	}
+	if len(asked) != 3 {
+		t.Fatalf("expected counts for all candidates in one call, got %v", asked)
+	}
+}
+
+func TestLeastLoadedSelector_BreaksTiesRandomly(t *testing.T) {
+	prRepo := &prRepositoryMock{
+		countOpenReviewsFn: func(ctx context.Context, ids []string) (map[string]int, error) {
+			return map[string]int{}, nil
+		},
+	}
+	repos := &domain.Repos{PR: prRepo}
+	candidates := []*domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}}
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		picked, err := NewLeastLoadedSelector().Select(context.Background(), repos, "team", candidates, 1)
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		seen[picked[0].ID] = struct{}{}
+	}
+
+	if len(seen) < 2 {
+		t.Fatalf("expected ties to be broken randomly, always got %v", seen)
+	}
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"fmt"
+)
+
+type statsUsecase struct {
+	statsRepository domain.StatsRepository
+}
+
+func NewStatsUsecase(statsRepository domain.StatsRepository) domain.StatsUsecase {
+	return &statsUsecase{statsRepository: statsRepository}
+}
+
+func (s *statsUsecase) UserAssignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error) {
+	if err := validateStatsFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.statsRepository.UserAssignments(ctx, filter)
+}
+
+func (s *statsUsecase) PRReassignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error) {
+	if err := validateStatsFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.statsRepository.PRReassignments(ctx, filter)
+}
+
+func (s *statsUsecase) TeamTotals(ctx context.Context, filter domain.StatsFilter) ([]*domain.TeamStats, error) {
+	if err := validateStatsFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.statsRepository.TeamTotals(ctx, filter)
+}
+
+func validateStatsFilter(filter domain.StatsFilter) error {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return fmt.Errorf("from must be before to: %w", domain.ErrInvalidInput)
+	}
+	return nil
+}
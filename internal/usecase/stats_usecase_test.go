@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatsUsecaseUserAssignments_PassesFilter(t *testing.T) {
+	from := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	repo := &statsRepositoryMock{
+		userAssignmentsFn: func(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error) {
+			if filter.TeamName != "team" || filter.From == nil || !filter.From.Equal(from) || filter.To != nil {
+				t.Fatalf("unexpected filter: %+v", filter)
+			}
+			return []*domain.UserAssignmentStats{{UserID: "u1", Total: 3, Open: 2, Merged: 1}}, nil
+		},
+	}
+	uc := NewStatsUsecase(repo)
+
+	stats, err := uc.UserAssignments(context.Background(), domain.StatsFilter{TeamName: "team", From: &from})
+	if err != nil {
+		t.Fatalf("UserAssignments: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Open != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestStatsUsecase_InvalidRange(t *testing.T) {
+	from := time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	uc := NewStatsUsecase(&statsRepositoryMock{})
+
+	_, err := uc.TeamTotals(context.Background(), domain.StatsFilter{From: &from, To: &to})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
@@ -3,50 +3,229 @@ package usecase
 import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 )
 
 type teamUsecase struct {
 	teamRepository domain.TeamRepository
 	userRepository domain.UserRepository
 	txManager      domain.TxManager
+	selector       ReviewerSelector
 }
 
-func NewTeamUsecase(teamRepository domain.TeamRepository, userRepository domain.UserRepository, txManager domain.TxManager) domain.TeamUsecase {
+func NewTeamUsecase(teamRepository domain.TeamRepository, userRepository domain.UserRepository, txManager domain.TxManager, selector ReviewerSelector) domain.TeamUsecase {
 	return &teamUsecase{
 		teamRepository: teamRepository,
 		userRepository: userRepository,
 		txManager:      txManager,
+		selector:       selector,
 	}
 }
 
-func (tu *teamUsecase) Add(ctx context.Context, team *domain.Team) (*domain.Team, error) {
-	exists, err := tu.teamRepository.Exists(ctx, team.Name)
+// Add создает команду с участниками. Участники других команд переносятся только при moveExisting,
+// иначе возвращается *domain.OtherTeamError со всеми такими участниками.
+func (tu *teamUsecase) Add(ctx context.Context, team *domain.Team, moveExisting bool) (*domain.TeamMembersChange, error) {
+	var result *domain.TeamMembersChange
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		exists, err := repos.Team.Exists(ctx, team.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("team_name %w", domain.ErrAlreadyExists)
+		}
+
+		if err := repos.Team.Create(ctx, team.Name); err != nil {
+			return err
+		}
+
+		movedIDs, reassignments, err := tu.addMembers(ctx, repos, team.Name, team.Members, moveExisting)
+		if err != nil {
+			return err
+		}
+
+		result = &domain.TeamMembersChange{Team: team, UserIDs: movedIDs, Reassignments: reassignments}
+		return nil
+	}, assignmentTx...)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, fmt.Errorf("team_name %w", domain.ErrAlreadyExists)
+
+	return result, nil
+}
+
+// ListByName возвращает команду с участниками из одного снимка
+func (tu *teamUsecase) ListByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	var result *domain.Team
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		var err error
+		result, err = fetchTeam(ctx, repos, teamName)
+		return err
+	}, domain.ReadOnly(), domain.WithIsolation(domain.RepeatableRead))
+	if err != nil {
+		return nil, err
 	}
 
-	err = tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
-		if err := repos.Team.Create(ctx, team.Name); err != nil {
+	return result, nil
+}
+
+// List возвращает страницу команд без участников, по умолчанию по имени
+func (tu *teamUsecase) List(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error) {
+	if err := normalizePage(&filter.PageParams, []string{domain.SortByName}, domain.SortAsc); err != nil {
+		return nil, err
+	}
+	return tu.teamRepository.List(ctx, filter)
+}
+
+// DeactivateUsers деактивирует участников команды (или всех, кроме перечисленных) одним запросом и в той же транзакции
+// переназначает их открытые ревью другим участникам команды: замены для всех PR подбирает хранилище одним запросом
+// по стратегии выбора ревьюверов команды. Все участники деактивируются до подбора замен, поэтому никто из них
+// не становится заменой другому.
+func (tu *teamUsecase) DeactivateUsers(ctx context.Context, teamName string, userIDs []string, allExcept bool) (*domain.TeamDeactivation, error) {
+	var result *domain.TeamDeactivation
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		exists, err := repos.Team.Exists(ctx, teamName)
+		if err != nil {
 			return err
 		}
+		if !exists {
+			return domain.ErrNotFound
+		}
 
-		for _, m := range team.Members {
-			u := &domain.User{
-				ID:       m.ID,
-				Name:     m.Name,
-				TeamName: team.Name,
-				IsActive: m.IsActive,
+		deactivated, err := repos.User.DeactivateTeamMembers(ctx, teamName, userIDs, allExcept)
+		if err != nil {
+			return err
+		}
+
+		if !allExcept {
+			if missing := missingIDs(userIDs, deactivated); len(missing) > 0 {
+				return fmt.Errorf("users %s are not members of team %s: %w", strings.Join(missing, ", "), teamName, domain.ErrNotFound)
 			}
-			if err := repos.User.Upsert(ctx, u); err != nil {
+		}
+
+		reassignments, err := reassignTeamReviews(ctx, repos, tu.selector, teamName, deactivated, domain.ReasonTeamDeactivation)
+		if err != nil {
+			return err
+		}
+
+		result = &domain.TeamDeactivation{TeamName: teamName, Deactivated: deactivated, Reassignments: reassignments}
+		return nil
+	}, assignmentTx...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddMembers добавляет участников в существующую команду. Участник другой команды переносится только при move,
+// его открытые ревью в той же транзакции передаются активным участникам прежней команды.
+func (tu *teamUsecase) AddMembers(ctx context.Context, teamName string, members []*domain.User, move bool) (*domain.TeamMembersChange, error) {
+	var result *domain.TeamMembersChange
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		exists, err := repos.Team.Exists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+
+		movedIDs, reassignments, err := tu.addMembers(ctx, repos, teamName, members, move)
+		if err != nil {
+			return err
+		}
+
+		team, err := fetchTeam(ctx, repos, teamName)
+		if err != nil {
+			return err
+		}
+
+		result = &domain.TeamMembersChange{Team: team, UserIDs: movedIDs, Reassignments: reassignments}
+		return nil
+	}, assignmentTx...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RemoveMembers отвязывает участников от команды и в той же транзакции передает их открытые ревью
+// оставшимся активным участникам; PR без подходящего кандидата возвращаются с пустым NewReviewerID.
+func (tu *teamUsecase) RemoveMembers(ctx context.Context, teamName string, userIDs []string) (*domain.TeamMembersChange, error) {
+	var result *domain.TeamMembersChange
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		exists, err := repos.Team.Exists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+
+		removed, err := repos.User.RemoveFromTeam(ctx, teamName, userIDs)
+		if err != nil {
+			return err
+		}
+		if missing := missingIDs(userIDs, removed); len(missing) > 0 {
+			return fmt.Errorf("users %s are not members of team %s: %w", strings.Join(missing, ", "), teamName, domain.ErrNotFound)
+		}
+
+		var reassignments []*domain.ReviewReassignment
+		for _, id := range removed {
+			// участник уже отвязан от команды, поэтому сам в кандидаты не попадет
+			res, err := reassignOpenReviews(ctx, repos, tu.selector, &domain.User{ID: id, TeamName: teamName}, domain.ReasonMemberRemoved)
+			if err != nil {
 				return err
 			}
+			reassignments = append(reassignments, res...)
 		}
 
+		team, err := fetchTeam(ctx, repos, teamName)
+		if err != nil {
+			return err
+		}
+
+		result = &domain.TeamMembersChange{Team: team, UserIDs: removed, Reassignments: reassignments}
 		return nil
+	}, assignmentTx...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Rename переименовывает команду; участники и курсор round_robin переезжают на новое имя каскадно.
+func (tu *teamUsecase) Rename(ctx context.Context, teamName, newName string) (*domain.Team, error) {
+	var team *domain.Team
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		exists, err := repos.Team.Exists(ctx, newName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("team_name %s %w", newName, domain.ErrAlreadyExists)
+		}
+
+		if err := repos.Team.Rename(ctx, teamName, newName); err != nil {
+			if errors.Is(err, domain.ErrAlreadyExists) {
+				return fmt.Errorf("team_name %s %w", newName, err)
+			}
+			return err
+		}
+
+		team, err = fetchTeam(ctx, repos, newName)
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -55,22 +234,241 @@ func (tu *teamUsecase) Add(ctx context.Context, team *domain.Team) (*domain.Team
 	return team, nil
 }
 
-func (tu *teamUsecase) ListByName(ctx context.Context, teamName string) (*domain.Team, error) {
-	exists, err := tu.teamRepository.Exists(ctx, teamName)
+func (tu *teamUsecase) GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	var result *domain.TeamSettings
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		var err error
+		result, err = repos.Team.FetchSettings(ctx, teamName)
+		return err
+	}, domain.ReadOnly())
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, domain.ErrNotFound
+
+	return result, nil
+}
+
+// UpdateSettings заменяет настройки назначения ревьюверов команды. Резервные команды должны существовать,
+// не повторяться и отличаться от самой команды.
+func (tu *teamUsecase) UpdateSettings(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+	switch {
+	case settings.MaxReviewers < 1 || settings.MaxReviewers > domain.MaxReviewersLimit:
+		return nil, fmt.Errorf("max_reviewers must be between 1 and %d: %w", domain.MaxReviewersLimit, domain.ErrInvalidInput)
+	case settings.MinReviewers < 0 || settings.MinReviewers > settings.MaxReviewers:
+		return nil, fmt.Errorf("min_reviewers must be between 0 and max_reviewers: %w", domain.ErrInvalidInput)
+	case settings.DefaultMaxOpenReviews != nil && *settings.DefaultMaxOpenReviews < 0:
+		return nil, fmt.Errorf("default_max_open_reviews must not be negative: %w", domain.ErrInvalidInput)
 	}
 
-	members, err := tu.userRepository.FetchByTeam(ctx, teamName)
+	seen := make(map[string]struct{}, len(settings.FallbackTeams))
+	for _, name := range settings.FallbackTeams {
+		if name == "" || name == settings.TeamName {
+			return nil, fmt.Errorf("fallback_teams must not contain %q: %w", name, domain.ErrInvalidInput)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("fallback team %s is listed twice: %w", name, domain.ErrInvalidInput)
+		}
+		seen[name] = struct{}{}
+	}
+
+	var result *domain.TeamSettings
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		if _, err := repos.Team.FetchByName(ctx, settings.TeamName); err != nil {
+			return err
+		}
+		for _, name := range settings.FallbackTeams {
+			if _, err := repos.Team.FetchByName(ctx, name); err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					return fmt.Errorf("fallback team %s %w", name, err)
+				}
+				return err
+			}
+		}
+
+		var err error
+		result, err = repos.Team.UpsertSettings(ctx, settings)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SetArchived архивирует команду или возвращает ее из архива. Участники архивной команды не назначаются ревьюверами,
+// но уже сделанные назначения, история и статистика сохраняются.
+func (tu *teamUsecase) SetArchived(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+	var result *domain.Team
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		team, err := repos.Team.SetArchived(ctx, teamName, archived)
+		if err != nil {
+			return err
+		}
+
+		team.Members, err = repos.User.FetchByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		result = team
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Delete удаляет команду, оставляя ее участников без команды. Пока у участников есть открытые ревью, удаление
+// отклоняется; при force их ревью в той же транзакции передаются активным коллегам авторов PR, а для авторов
+// из удаляемой команды — участникам ее резервных команд.
+func (tu *teamUsecase) Delete(ctx context.Context, teamName string, force bool) (*domain.TeamMembersChange, error) {
+	var result *domain.TeamMembersChange
+
+	err := tu.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		team, err := fetchTeam(ctx, repos, teamName)
+		if err != nil {
+			return err
+		}
+
+		memberIDs := make([]string, 0, len(team.Members))
+		for _, m := range team.Members {
+			memberIDs = append(memberIDs, m.ID)
+		}
+
+		counts, err := repos.PR.CountOpenReviews(ctx, memberIDs)
+		if err != nil {
+			return err
+		}
+
+		var busy []string
+		for _, id := range memberIDs {
+			if counts[id] > 0 {
+				busy = append(busy, id)
+			}
+		}
+		if len(busy) > 0 && !force {
+			return fmt.Errorf("users %s: %w", strings.Join(busy, ", "), domain.ErrTeamHasOpenReviews)
+		}
+
+		// ревью передаются до отвязки участников, пока авторы из удаляемой команды еще знают свою команду;
+		// архивная команда не дает кандидатов, поэтому ее ревью уходят в резервные команды
+		if len(busy) > 0 {
+			if _, err := repos.Team.SetArchived(ctx, teamName, true); err != nil {
+				return err
+			}
+		}
+		var reassignments []*domain.ReviewReassignment
+		for _, id := range busy {
+			res, err := reassignToAuthorTeams(ctx, repos, tu.selector, id, domain.ReasonTeamDeleted)
+			if err != nil {
+				return err
+			}
+			reassignments = append(reassignments, res...)
+		}
+
+		removed, err := repos.User.RemoveFromTeam(ctx, teamName, memberIDs)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Team.Delete(ctx, teamName); err != nil {
+			return err
+		}
+
+		result = &domain.TeamMembersChange{Team: team, UserIDs: removed, Reassignments: reassignments}
+		return nil
+	}, assignmentTx...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.Team{
-		Name:    teamName,
-		Members: members,
-	}, nil
+	return result, nil
+}
+
+// addMembers сохраняет участников в команду teamName и передает открытые ревью перенесенных из других команд
+// активным участникам прежней команды; возвращает id перенесенных и их переназначения. Без move участники
+// других команд дают *domain.OtherTeamError, и никто из списка не сохраняется.
+func (tu *teamUsecase) addMembers(ctx context.Context, repos *domain.Repos, teamName string, members []*domain.User, move bool) ([]string, []*domain.ReviewReassignment, error) {
+	var (
+		moved     []*domain.User
+		conflicts []domain.TeamConflict
+	)
+
+	for _, m := range members {
+		existing, err := repos.User.FetchByID(ctx, m.ID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, err
+		}
+		if existing != nil && existing.TeamName != "" && existing.TeamName != teamName {
+			moved = append(moved, existing)
+			conflicts = append(conflicts, domain.TeamConflict{UserID: existing.ID, TeamName: existing.TeamName})
+		}
+	}
+	if len(conflicts) > 0 && !move {
+		return nil, nil, &domain.OtherTeamError{Conflicts: conflicts}
+	}
+
+	for _, m := range members {
+		u := &domain.User{
+			ID:       m.ID,
+			Name:     m.Name,
+			TeamName: teamName,
+			IsActive: m.IsActive,
+		}
+		if err := repos.User.Upsert(ctx, u); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var (
+		movedIDs      []string
+		reassignments []*domain.ReviewReassignment
+	)
+	for _, u := range moved {
+		res, err := reassignOpenReviews(ctx, repos, tu.selector, u, domain.ReasonMemberMoved)
+		if err != nil {
+			return nil, nil, err
+		}
+		movedIDs = append(movedIDs, u.ID)
+		reassignments = append(reassignments, res...)
+	}
+
+	return movedIDs, reassignments, nil
+}
+
+func fetchTeam(ctx context.Context, repos *domain.Repos, teamName string) (*domain.Team, error) {
+	team, err := repos.Team.FetchByName(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	team.Members, err = repos.User.FetchByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// missingIDs возвращает id из want, которых нет в got, сохраняя порядок want
+func missingIDs(want, got []string) []string {
+	present := make(map[string]struct{}, len(got))
+	for _, id := range got {
+		present[id] = struct{}{}
+	}
+
+	var missing []string
+	for _, id := range want {
+		if _, ok := present[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing
 }
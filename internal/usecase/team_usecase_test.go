@@ -4,6 +4,7 @@ import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -22,6 +23,9 @@ func TestTeamUsecaseAdd_Success(t *testing.T) {
 
 	upserted := make([]*domain.User, 0)
 	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return nil, domain.ErrNotFound
+		},
 		fetchByTeamFn: func(ctx context.Context, teamName string) ([]*domain.User, error) {
 			return nil, nil
 		},
@@ -38,7 +42,7 @@ func TestTeamUsecaseAdd_Success(t *testing.T) {
 		},
 	}
 
-	uc := NewTeamUsecase(teamRepo, userRepo, tx)
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
 
 	team := &domain.Team{
 		Name: "team",
@@ -48,12 +52,12 @@ func TestTeamUsecaseAdd_Success(t *testing.T) {
 		},
 	}
 
-	created, err := uc.Add(context.Background(), team)
+	created, err := uc.Add(context.Background(), team, false)
 	if err != nil {
 		t.Fatalf("Add: %v", err)
 	}
 
-	if created != team {
+	if created.Team != team {
 		t.Fatalf("expected same team pointer")
 	}
 
@@ -73,9 +77,10 @@ func TestTeamUsecaseAdd_Duplicate(t *testing.T) {
 			return true, nil
 		},
 	}
-	uc := NewTeamUsecase(teamRepo, nil, nil)
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo}}
+	uc := NewTeamUsecase(teamRepo, nil, tx, NewRandomSelector())
 
-	_, err := uc.Add(context.Background(), &domain.Team{Name: "team"})
+	_, err := uc.Add(context.Background(), &domain.Team{Name: "team"}, false)
 	if !errors.Is(err, domain.ErrAlreadyExists) {
 		t.Fatalf("expected ErrAlreadyExists, got %v", err)
 	}
@@ -83,8 +88,8 @@ func TestTeamUsecaseAdd_Duplicate(t *testing.T) {
 
 func TestTeamUsecaseListByName_Success(t *testing.T) {
 	teamRepo := &teamRepositoryMock{
-		existsFn: func(ctx context.Context, teamName string) (bool, error) {
-			return true, nil
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
 		},
 	}
 	userRepo := &userRepositoryMock{
@@ -92,8 +97,8 @@ func TestTeamUsecaseListByName_Success(t *testing.T) {
 			return []*domain.User{{ID: "u1"}, {ID: "u2"}}, nil
 		},
 	}
-
-	uc := NewTeamUsecase(teamRepo, userRepo, nil)
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
 
 	team, err := uc.ListByName(context.Background(), "team")
 	if err != nil {
@@ -105,15 +110,666 @@ func TestTeamUsecaseListByName_Success(t *testing.T) {
 }
 
 func TestTeamUsecaseListByName_NotFound(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return nil, domain.ErrNotFound
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo}}
+	uc := NewTeamUsecase(teamRepo, nil, tx, NewRandomSelector())
+
+	_, err := uc.ListByName(context.Background(), "missing")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTeamUsecaseDeactivateUsers_Success(t *testing.T) {
+	var cursor string
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+		lockReviewCursorFn: func(ctx context.Context, teamName string) (string, error) {
+			return "u2", nil
+		},
+		updateReviewCursorFn: func(ctx context.Context, teamName, lastUserID string) error {
+			cursor = lastUserID
+			return nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		deactivateTeamFn: func(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error) {
+			return []string{"u1", "u2"}, nil
+		},
+	}
+	calls := 0
+	prRepo := &prRepositoryMock{
+		reassignOpenFn: func(ctx context.Context, req domain.BulkReassign) ([]*domain.ReviewReassignment, error) {
+			calls++
+			if len(req.UserIDs) != 2 || req.Strategy != domain.ReassignRoundRobin || req.Cursor != "u2" {
+				t.Fatalf("unexpected request: %+v", req)
+			}
+			if req.Reason != domain.ReasonTeamDeactivation || req.Actor != "lead" {
+				t.Fatalf("unexpected audit: %+v", req)
+			}
+			return []*domain.ReviewReassignment{
+				{PRID: "pr1", OldReviewerID: "u1", NewReviewerID: "u3"},
+				{PRID: "pr2", OldReviewerID: "u2", NewReviewerID: "u4"},
+				{PRID: "pr3", OldReviewerID: "u1"},
+			}, nil
+		},
+	}
+	// стратегия команды переопределяет стратегию по умолчанию и в массовом переназначении
+	selector := NewTeamReviewerSelector(NewRandomSelector(), map[string]ReviewerSelector{"team": NewRoundRobinSelector()})
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, selector)
+
+	ctx := domain.WithActor(context.Background(), "lead")
+	res, err := uc.DeactivateUsers(ctx, "team", []string{"u1", "u2"}, false)
+	if err != nil {
+		t.Fatalf("DeactivateUsers: %v", err)
+	}
+	if res.TeamName != "team" || len(res.Deactivated) != 2 || len(res.Reassignments) != 3 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single bulk reassignment, got %d", calls)
+	}
+	if cursor != "u4" {
+		t.Fatalf("expected round robin cursor moved to the last replacement, got %q", cursor)
+	}
+}
+
+func TestTeamUsecaseDeactivateUsers_UnknownMember(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		deactivateTeamFn: func(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error) {
+			return []string{"u1"}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		reassignOpenFn: func(ctx context.Context, req domain.BulkReassign) ([]*domain.ReviewReassignment, error) {
+			t.Fatalf("must not reassign when request is invalid")
+			return nil, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	_, err := uc.DeactivateUsers(context.Background(), "team", []string{"u1", "stranger"}, false)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTeamUsecaseDeactivateUsers_TeamNotFound(t *testing.T) {
 	teamRepo := &teamRepositoryMock{
 		existsFn: func(ctx context.Context, teamName string) (bool, error) {
 			return false, nil
 		},
 	}
-	uc := NewTeamUsecase(teamRepo, nil, nil)
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo}}
+	uc := NewTeamUsecase(teamRepo, nil, tx, NewRandomSelector())
 
-	_, err := uc.ListByName(context.Background(), "missing")
+	_, err := uc.DeactivateUsers(context.Background(), "missing", nil, true)
 	if !errors.Is(err, domain.ErrNotFound) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 }
+
+func TestTeamUsecaseAdd_ListsMembersOfOtherTeams(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return false, nil
+		},
+		createFn: func(ctx context.Context, teamName string) error {
+			return nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			switch id {
+			case "u1":
+				return &domain.User{ID: id, TeamName: "payments"}, nil
+			case "u2":
+				return &domain.User{ID: id, TeamName: "backend"}, nil
+			}
+			return nil, domain.ErrNotFound
+		},
+		upsertFn: func(ctx context.Context, user *domain.User) error {
+			t.Fatalf("must not save members when some belong to other teams: %s", user.ID)
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	team := &domain.Team{Name: "team", Members: []*domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}}}
+	_, err := uc.Add(context.Background(), team, false)
+
+	var conflict *domain.OtherTeamError
+	if !errors.As(err, &conflict) || !errors.Is(err, domain.ErrOtherTeam) {
+		t.Fatalf("expected OtherTeamError, got %v", err)
+	}
+	want := []domain.TeamConflict{{UserID: "u1", TeamName: "payments"}, {UserID: "u2", TeamName: "backend"}}
+	if !reflect.DeepEqual(conflict.Conflicts, want) {
+		t.Fatalf("unexpected conflicts: %+v", conflict.Conflicts)
+	}
+}
+
+func TestTeamUsecaseAdd_MoveExistingReassignsReviews(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return false, nil
+		},
+		createFn: func(ctx context.Context, teamName string) error {
+			return nil
+		},
+	}
+	saved := make(map[string]string)
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			if id == "u1" {
+				return &domain.User{ID: id, TeamName: "payments", IsActive: true}, nil
+			}
+			return nil, domain.ErrNotFound
+		},
+		upsertFn: func(ctx context.Context, user *domain.User) error {
+			saved[user.ID] = user.TeamName
+			return nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			if teamName != "payments" {
+				t.Fatalf("replacement must come from the previous team, got %s", teamName)
+			}
+			return []*domain.User{{ID: "u7"}}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{{ID: "pr1", AuthorID: "u8", Status: domain.StatusOpen}}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u1"}, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldUserID, newUserID string) error {
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	team := &domain.Team{Name: "team", Members: []*domain.User{{ID: "u1"}, {ID: "u2"}}}
+	res, err := uc.Add(context.Background(), team, true)
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if saved["u1"] != "team" || saved["u2"] != "team" {
+		t.Fatalf("expected both members saved to team, got %v", saved)
+	}
+	if !reflect.DeepEqual(res.UserIDs, []string{"u1"}) || len(res.Reassignments) != 1 || res.Reassignments[0].NewReviewerID != "u7" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestTeamUsecaseAddMembers_RequiresMove(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			if id == "u1" {
+				return nil, domain.ErrNotFound
+			}
+			return &domain.User{ID: id, TeamName: "other"}, nil
+		},
+		upsertFn: func(ctx context.Context, user *domain.User) error {
+			if user.ID != "u1" {
+				t.Fatalf("must not overwrite team of %s", user.ID)
+			}
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	_, err := uc.AddMembers(context.Background(), "team", []*domain.User{{ID: "u1"}, {ID: "u2"}}, false)
+	if !errors.Is(err, domain.ErrOtherTeam) {
+		t.Fatalf("expected ErrOtherTeam, got %v", err)
+	}
+}
+
+func TestTeamUsecaseAddMembers_MoveReassignsOldTeamReviews(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
+		},
+	}
+	var upserted *domain.User
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "old", IsActive: true}, nil
+		},
+		upsertFn: func(ctx context.Context, user *domain.User) error {
+			upserted = user
+			return nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			if teamName != "old" {
+				t.Fatalf("replacement must come from the previous team, got %s", teamName)
+			}
+			return []*domain.User{{ID: "u5"}}, nil
+		},
+		fetchByTeamFn: func(ctx context.Context, teamName string) ([]*domain.User, error) {
+			return []*domain.User{upserted}, nil
+		},
+	}
+	var events []*domain.AssignmentEvent
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{{ID: "pr1", AuthorID: "u9", Status: domain.StatusOpen}}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u1"}, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldUserID, newUserID string) error {
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			events = append(events, evs...)
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	res, err := uc.AddMembers(context.Background(), "team", []*domain.User{{ID: "u1", IsActive: true}}, true)
+	if err != nil {
+		t.Fatalf("AddMembers: %v", err)
+	}
+	if upserted.TeamName != "team" {
+		t.Fatalf("expected user moved to team, got %+v", upserted)
+	}
+	if len(res.UserIDs) != 1 || len(res.Reassignments) != 1 || res.Reassignments[0].NewReviewerID != "u5" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(events) != 1 || events[0].Reason != domain.ReasonMemberMoved {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestTeamUsecaseRemoveMembers_ReassignsOpenReviews(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		removeFromTeamFn: func(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+			return userIDs, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			if teamName != "team" {
+				t.Fatalf("unexpected team: %s", teamName)
+			}
+			return nil, nil
+		},
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team"}, nil
+		},
+		fetchByTeamFn: func(ctx context.Context, teamName string) ([]*domain.User, error) {
+			return []*domain.User{{ID: "u2"}}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{
+				{ID: "pr1", AuthorID: "u2", Status: domain.StatusOpen},
+				{ID: "pr2", AuthorID: "u2", Status: domain.StatusMerged},
+			}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u1"}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	res, err := uc.RemoveMembers(context.Background(), "team", []string{"u1"})
+	if err != nil {
+		t.Fatalf("RemoveMembers: %v", err)
+	}
+	if len(res.UserIDs) != 1 || len(res.Team.Members) != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(res.Reassignments) != 1 || res.Reassignments[0].PRID != "pr1" || res.Reassignments[0].NewReviewerID != "" {
+		t.Fatalf("expected open review reported as not reassigned, got %+v", res.Reassignments)
+	}
+}
+
+func TestTeamUsecaseRemoveMembers_RetryDoesNotDuplicateResult(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		removeFromTeamFn: func(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+			return userIDs, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			return nil, nil
+		},
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team"}, nil
+		},
+		fetchByTeamFn: func(ctx context.Context, teamName string) ([]*domain.User, error) {
+			return []*domain.User{{ID: "u2"}}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{{ID: "pr1", AuthorID: "u2", Status: domain.StatusOpen}}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u1"}, nil
+		},
+	}
+	repos := &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}
+	// первая попытка проходит целиком, но транзакция повторяется, как после конфликта сериализации
+	tx := &txManagerStub{withinFn: func(ctx context.Context, fn func(context.Context, *domain.Repos) error) error {
+		if err := fn(ctx, repos); err != nil {
+			return err
+		}
+		return fn(ctx, repos)
+	}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	res, err := uc.RemoveMembers(context.Background(), "team", []string{"u1"})
+	if err != nil {
+		t.Fatalf("RemoveMembers: %v", err)
+	}
+	if len(res.UserIDs) != 1 || len(res.Reassignments) != 1 {
+		t.Fatalf("expected result of the last attempt only, got %+v", res)
+	}
+	if tx.lastOpts.Isolation != domain.Serializable {
+		t.Fatalf("expected removal to run at SERIALIZABLE, got %+v", tx.lastOpts)
+	}
+}
+
+func TestTeamUsecaseRemoveMembers_UnknownMember(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		removeFromTeamFn: func(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+			return []string{"u1"}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	_, err := uc.RemoveMembers(context.Background(), "team", []string{"u1", "stranger"})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTeamUsecaseRename_TargetExists(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return teamName == "taken", nil
+		},
+		renameFn: func(ctx context.Context, teamName, newName string) error {
+			t.Fatalf("must not rename onto existing team")
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo}}
+	uc := NewTeamUsecase(teamRepo, nil, tx, NewRandomSelector())
+
+	_, err := uc.Rename(context.Background(), "team", "taken")
+	if !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+func TestTeamUsecaseUpdateSettings_Validation(t *testing.T) {
+	uc := NewTeamUsecase(&teamRepositoryMock{}, nil, nil, NewRandomSelector())
+
+	cases := []*domain.TeamSettings{
+		{TeamName: "team", MinReviewers: 0, MaxReviewers: 0},
+		{TeamName: "team", MinReviewers: 3, MaxReviewers: 2},
+		{TeamName: "team", MinReviewers: 0, MaxReviewers: domain.MaxReviewersLimit + 1},
+		{TeamName: "team", MinReviewers: 1, MaxReviewers: 2, FallbackTeams: []string{"team"}},
+		{TeamName: "team", MinReviewers: 1, MaxReviewers: 2, FallbackTeams: []string{"a", "b", "a"}},
+	}
+	for _, settings := range cases {
+		if _, err := uc.UpdateSettings(context.Background(), settings); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("%+v: expected ErrInvalidInput, got %v", settings, err)
+		}
+	}
+}
+
+func TestTeamUsecaseUpdateSettings_UnknownFallback(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			if teamName == "team" {
+				return &domain.Team{Name: teamName}, nil
+			}
+			return nil, domain.ErrNotFound
+		},
+		upsertSettingsFn: func(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+			t.Fatalf("must not save settings with unknown fallback team")
+			return nil, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo}}
+	uc := NewTeamUsecase(teamRepo, nil, tx, NewRandomSelector())
+
+	_, err := uc.UpdateSettings(context.Background(), &domain.TeamSettings{TeamName: "team", MaxReviewers: 2, FallbackTeams: []string{"ghost"}})
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTeamUsecaseDelete_RefusesWithOpenReviews(t *testing.T) {
+	teamRepo := &teamRepositoryMock{
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
+		},
+		deleteFn: func(ctx context.Context, teamName string) error {
+			t.Fatalf("must not delete team with open reviews")
+			return nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByTeamFn: func(ctx context.Context, teamName string) ([]*domain.User, error) {
+			return []*domain.User{{ID: "u1"}, {ID: "u2"}}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		countOpenReviewsFn: func(ctx context.Context, ids []string) (map[string]int, error) {
+			return map[string]int{"u2": 1}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	_, err := uc.Delete(context.Background(), "team", false)
+	if !errors.Is(err, domain.ErrTeamHasOpenReviews) {
+		t.Fatalf("expected ErrTeamHasOpenReviews, got %v", err)
+	}
+}
+
+func TestTeamUsecaseDelete_ForceReassignsToAuthorTeam(t *testing.T) {
+	deleted := false
+	teamRepo := &teamRepositoryMock{
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
+		},
+		setArchivedFn: func(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
+		},
+		deleteFn: func(ctx context.Context, teamName string) error {
+			deleted = true
+			return nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByTeamFn: func(ctx context.Context, teamName string) ([]*domain.User, error) {
+			return []*domain.User{{ID: "u1"}, {ID: "u2"}}, nil
+		},
+		removeFromTeamFn: func(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+			return userIDs, nil
+		},
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "backend"}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			if teamName != "backend" {
+				t.Fatalf("expected candidates from author team, got %s", teamName)
+			}
+			return []*domain.User{{ID: "u9"}}, nil
+		},
+	}
+	var events []*domain.AssignmentEvent
+	prRepo := &prRepositoryMock{
+		countOpenReviewsFn: func(ctx context.Context, ids []string) (map[string]int, error) {
+			return map[string]int{"u2": 1}, nil
+		},
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			if userID != "u2" {
+				t.Fatalf("only busy members must be reassigned, got %s", userID)
+			}
+			return []*domain.PullRequest{{ID: "pr1", AuthorID: "a1", Status: domain.StatusOpen}}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u2"}, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldUserID, newUserID string) error {
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			events = append(events, evs...)
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	res, err := uc.Delete(context.Background(), "team", true)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if !deleted || len(res.UserIDs) != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if len(res.Reassignments) != 1 || res.Reassignments[0].NewReviewerID != "u9" {
+		t.Fatalf("unexpected reassignments: %+v", res.Reassignments)
+	}
+	if len(events) != 1 || events[0].Reason != domain.ReasonTeamDeleted {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestTeamUsecaseDelete_AuthorInDeletedTeamUsesFallback(t *testing.T) {
+	var (
+		archived bool
+		unlinked bool
+	)
+	teamRepo := &teamRepositoryMock{
+		fetchByNameFn: func(ctx context.Context, teamName string) (*domain.Team, error) {
+			return &domain.Team{Name: teamName}, nil
+		},
+		setArchivedFn: func(ctx context.Context, teamName string, value bool) (*domain.Team, error) {
+			archived = value
+			return &domain.Team{Name: teamName}, nil
+		},
+		fetchSettingsFn: func(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+			settings := domain.DefaultTeamSettings(teamName)
+			if teamName == "team" {
+				settings.FallbackTeams = []string{"ops"}
+			}
+			return settings, nil
+		},
+		deleteFn: func(ctx context.Context, teamName string) error {
+			return nil
+		},
+	}
+	userRepo := &userRepositoryMock{
+		fetchByTeamFn: func(ctx context.Context, teamName string) ([]*domain.User, error) {
+			return []*domain.User{{ID: "a1"}, {ID: "u2"}, {ID: "u3"}}, nil
+		},
+		removeFromTeamFn: func(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+			unlinked = true
+			return userIDs, nil
+		},
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			if unlinked {
+				return &domain.User{ID: id}, nil
+			}
+			return &domain.User{ID: id, TeamName: "team"}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			switch {
+			case teamName == "team" && archived:
+				return nil, nil
+			case teamName == "team":
+				return []*domain.User{{ID: "u3", TeamName: teamName}}, nil
+			case teamName == "ops":
+				return []*domain.User{{ID: "o1", TeamName: teamName}}, nil
+			}
+			t.Fatalf("unexpected candidate team %q", teamName)
+			return nil, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		countOpenReviewsFn: func(ctx context.Context, ids []string) (map[string]int, error) {
+			return map[string]int{"u2": 1}, nil
+		},
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{{ID: "pr1", AuthorID: "a1", Status: domain.StatusOpen}}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			return []string{"u2"}, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldUserID, newUserID string) error {
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{Team: teamRepo, User: userRepo, PR: prRepo}}
+	uc := NewTeamUsecase(teamRepo, userRepo, tx, NewRandomSelector())
+
+	res, err := uc.Delete(context.Background(), "team", true)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if len(res.Reassignments) != 1 || res.Reassignments[0].NewReviewerID != "o1" {
+		t.Fatalf("expected review handed to fallback team, got %+v", res.Reassignments)
+	}
+}
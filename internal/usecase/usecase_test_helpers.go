@@ -3,14 +3,18 @@ package usecase
 import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"time"
 )
 
 type txManagerStub struct {
 	repos    *domain.Repos
 	withinFn func(ctx context.Context, fn func(context.Context, *domain.Repos) error) error
+	// lastOpts — параметры последней транзакции
+	lastOpts domain.TxOptions
 }
 
-func (t *txManagerStub) WithinTx(ctx context.Context, fn func(context.Context, *domain.Repos) error) error {
+func (t *txManagerStub) WithinTx(ctx context.Context, fn func(context.Context, *domain.Repos) error, opts ...domain.TxOption) error {
+	t.lastOpts = domain.ApplyTxOptions(opts...)
 	if t.withinFn != nil {
 		return t.withinFn(ctx, fn)
 	}
@@ -20,12 +24,25 @@ func (t *txManagerStub) WithinTx(ctx context.Context, fn func(context.Context, *
 type prRepositoryMock struct {
 	createFn           func(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error)
 	fetchByIDFn        func(ctx context.Context, prID string) (*domain.PullRequest, error)
-	updateStatusFn     func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	fetchForUpdateFn   func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	listFn             func(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error)
+	updateStatusFn     func(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error)
+	closeFn            func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	reopenFn           func(ctx context.Context, prID string) (*domain.PullRequest, error)
+	readyFn            func(ctx context.Context, prID string) (*domain.PullRequest, error)
 	listReviewableFn   func(ctx context.Context, userID string) ([]*domain.PullRequest, error)
 	listReviewersFn    func(ctx context.Context, prID string) ([]string, error)
+	listByStateFn      func(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error)
+	listStatesFn       func(ctx context.Context, prID string) ([]*domain.ReviewerState, error)
+	updateReviewFn     func(ctx context.Context, prID, userID string, state domain.ReviewState) error
 	insertReviewerFn   func(ctx context.Context, prID, userID string) error
 	replaceReviewerFn  func(ctx context.Context, prID, oldReviewerID, newReviewerID string) error
+	removeReviewerFn   func(ctx context.Context, prID, userID string) error
 	reviewerAssignedFn func(ctx context.Context, prID, userID string) (bool, error)
+	countOpenReviewsFn func(ctx context.Context, userIDs []string) (map[string]int, error)
+	reassignOpenFn     func(ctx context.Context, req domain.BulkReassign) ([]*domain.ReviewReassignment, error)
+	addEventsFn        func(ctx context.Context, events ...*domain.AssignmentEvent) error
+	listEventsFn       func(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error)
 }
 
 func (m *prRepositoryMock) Create(ctx context.Context, pr *domain.PullRequest) (*domain.PullRequest, error) {
@@ -36,18 +53,67 @@ func (m *prRepositoryMock) FetchByID(ctx context.Context, prID string) (*domain.
 	return m.fetchByIDFn(ctx, prID)
 }
 
-func (m *prRepositoryMock) UpdateStatusMerged(ctx context.Context, prID string) (*domain.PullRequest, error) {
-	return m.updateStatusFn(ctx, prID)
+// FetchByIDForUpdate без заданного fetchForUpdateFn ведет себя как FetchByID
+func (m *prRepositoryMock) FetchByIDForUpdate(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	if m.fetchForUpdateFn == nil {
+		return m.fetchByIDFn(ctx, prID)
+	}
+	return m.fetchForUpdateFn(ctx, prID)
+}
+
+func (m *prRepositoryMock) List(ctx context.Context, filter domain.PRFilter) (*domain.Page[*domain.PullRequest], error) {
+	return m.listFn(ctx, filter)
+}
+
+func (m *prRepositoryMock) UpdateStatusMerged(ctx context.Context, prID, overrideBy string) (*domain.PullRequest, error) {
+	return m.updateStatusFn(ctx, prID, overrideBy)
+}
+
+func (m *prRepositoryMock) UpdateStatusClosed(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return m.closeFn(ctx, prID)
+}
+
+func (m *prRepositoryMock) UpdateStatusOpen(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return m.reopenFn(ctx, prID)
+}
+
+func (m *prRepositoryMock) UpdateReady(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	return m.readyFn(ctx, prID)
 }
 
 func (m *prRepositoryMock) ListReviewableByUserID(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
 	return m.listReviewableFn(ctx, userID)
 }
 
+func (m *prRepositoryMock) ListReviewableByUserIDAndState(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error) {
+	return m.listByStateFn(ctx, userID, state)
+}
+
 func (m *prRepositoryMock) ListReviewers(ctx context.Context, prID string) ([]string, error) {
 	return m.listReviewersFn(ctx, prID)
 }
 
+// ListReviewerStates без listStatesFn строит PENDING-состояния из listReviewersFn
+func (m *prRepositoryMock) ListReviewerStates(ctx context.Context, prID string) ([]*domain.ReviewerState, error) {
+	if m.listStatesFn != nil {
+		return m.listStatesFn(ctx, prID)
+	}
+
+	ids, err := m.listReviewersFn(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*domain.ReviewerState, 0, len(ids))
+	for _, id := range ids {
+		states = append(states, &domain.ReviewerState{UserID: id, State: domain.ReviewPending})
+	}
+	return states, nil
+}
+
+func (m *prRepositoryMock) UpdateReviewState(ctx context.Context, prID, userID string, state domain.ReviewState) error {
+	return m.updateReviewFn(ctx, prID, userID, state)
+}
+
 func (m *prRepositoryMock) InsertReviewer(ctx context.Context, prID, userID string) error {
 	return m.insertReviewerFn(ctx, prID, userID)
 }
@@ -56,17 +122,94 @@ func (m *prRepositoryMock) ReplaceReviewer(ctx context.Context, prID, oldReviewe
 	return m.replaceReviewerFn(ctx, prID, oldReviewerID, newReviewerID)
 }
 
+func (m *prRepositoryMock) RemoveReviewer(ctx context.Context, prID, userID string) error {
+	return m.removeReviewerFn(ctx, prID, userID)
+}
+
 func (m *prRepositoryMock) ReviewerAssigned(ctx context.Context, prID, userID string) (bool, error) {
 	return m.reviewerAssignedFn(ctx, prID, userID)
 }
 
+func (m *prRepositoryMock) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return m.countOpenReviewsFn(ctx, userIDs)
+}
+
+func (m *prRepositoryMock) ReassignOpenReviews(ctx context.Context, req domain.BulkReassign) ([]*domain.ReviewReassignment, error) {
+	return m.reassignOpenFn(ctx, req)
+}
+
+func (m *prRepositoryMock) AddAssignmentEvents(ctx context.Context, events ...*domain.AssignmentEvent) error {
+	return m.addEventsFn(ctx, events...)
+}
+
+func (m *prRepositoryMock) ListAssignmentEvents(ctx context.Context, prID string) ([]*domain.AssignmentEvent, error) {
+	return m.listEventsFn(ctx, prID)
+}
+
 type userRepositoryMock struct {
 	upsertFn         func(ctx context.Context, user *domain.User) error
 	fetchByIDFn      func(ctx context.Context, id string) (*domain.User, error)
+	fetchByIDsFn     func(ctx context.Context, ids []string) ([]*domain.User, error)
 	fetchByTeamFn    func(ctx context.Context, teamName string) ([]*domain.User, error)
 	fetchActiveFn    func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error)
 	existsFn         func(ctx context.Context, userID string) (bool, error)
 	updateIsActiveFn func(ctx context.Context, userID string, active bool) (*domain.User, error)
+	deactivateTeamFn func(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error)
+	removeFromTeamFn func(ctx context.Context, teamName string, userIDs []string) ([]string, error)
+	listFn           func(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error)
+	setMaxOpenFn     func(ctx context.Context, userID string, limit *int) (*domain.User, error)
+	reviewLimitsFn   func(ctx context.Context, userIDs []string) (map[string]int, error)
+	addAbsenceFn     func(ctx context.Context, absence *domain.Absence) (*domain.Absence, error)
+	overlapFn        func(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error)
+	listAbsencesFn   func(ctx context.Context, userID string) ([]*domain.Absence, error)
+	currentAbsenceFn func(ctx context.Context, userID string) (*domain.Absence, error)
+	deleteAbsenceFn  func(ctx context.Context, absenceID int64) (*domain.Absence, error)
+	dueHandoffsFn    func(ctx context.Context, userID string) ([]*domain.Absence, error)
+	markHandedOffFn  func(ctx context.Context, absenceID int64) (*domain.Absence, error)
+}
+
+func (m *userRepositoryMock) AddAbsence(ctx context.Context, absence *domain.Absence) (*domain.Absence, error) {
+	return m.addAbsenceFn(ctx, absence)
+}
+
+func (m *userRepositoryMock) HasOverlappingAbsence(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error) {
+	return m.overlapFn(ctx, userID, startsAt, endsAt)
+}
+
+func (m *userRepositoryMock) ListAbsences(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	return m.listAbsencesFn(ctx, userID)
+}
+
+// FetchCurrentAbsence без заданного currentAbsenceFn считает, что сейчас никто не отсутствует
+func (m *userRepositoryMock) FetchCurrentAbsence(ctx context.Context, userID string) (*domain.Absence, error) {
+	if m.currentAbsenceFn == nil {
+		return nil, domain.ErrNotFound
+	}
+	return m.currentAbsenceFn(ctx, userID)
+}
+
+func (m *userRepositoryMock) DeleteAbsence(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	return m.deleteAbsenceFn(ctx, absenceID)
+}
+
+func (m *userRepositoryMock) ListDueHandoffs(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	return m.dueHandoffsFn(ctx, userID)
+}
+
+func (m *userRepositoryMock) MarkHandedOff(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	return m.markHandedOffFn(ctx, absenceID)
+}
+
+func (m *userRepositoryMock) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+	return m.setMaxOpenFn(ctx, userID, limit)
+}
+
+// FetchReviewLimits без заданного reviewLimitsFn считает, что лимитов ни у кого нет
+func (m *userRepositoryMock) FetchReviewLimits(ctx context.Context, userIDs []string) (map[string]int, error) {
+	if m.reviewLimitsFn == nil {
+		return map[string]int{}, nil
+	}
+	return m.reviewLimitsFn(ctx, userIDs)
 }
 
 func (m *userRepositoryMock) Upsert(ctx context.Context, user *domain.User) error {
@@ -77,6 +220,10 @@ func (m *userRepositoryMock) FetchByID(ctx context.Context, id string) (*domain.
 	return m.fetchByIDFn(ctx, id)
 }
 
+func (m *userRepositoryMock) FetchByIDs(ctx context.Context, ids []string) ([]*domain.User, error) {
+	return m.fetchByIDsFn(ctx, ids)
+}
+
 func (m *userRepositoryMock) FetchByTeam(ctx context.Context, teamName string) ([]*domain.User, error) {
 	return m.fetchByTeamFn(ctx, teamName)
 }
@@ -93,9 +240,42 @@ func (m *userRepositoryMock) UpdateIsActive(ctx context.Context, userID string,
 	return m.updateIsActiveFn(ctx, userID, active)
 }
 
+func (m *userRepositoryMock) DeactivateTeamMembers(ctx context.Context, teamName string, userIDs []string, allExcept bool) ([]string, error) {
+	return m.deactivateTeamFn(ctx, teamName, userIDs, allExcept)
+}
+
+func (m *userRepositoryMock) List(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+	return m.listFn(ctx, filter)
+}
+
+func (m *userRepositoryMock) RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+	return m.removeFromTeamFn(ctx, teamName, userIDs)
+}
+
 type teamRepositoryMock struct {
-	createFn func(ctx context.Context, teamName string) error
-	existsFn func(ctx context.Context, teamName string) (bool, error)
+	createFn             func(ctx context.Context, teamName string) error
+	existsFn             func(ctx context.Context, teamName string) (bool, error)
+	lockReviewCursorFn   func(ctx context.Context, teamName string) (string, error)
+	updateReviewCursorFn func(ctx context.Context, teamName, lastUserID string) error
+	fetchByNameFn        func(ctx context.Context, teamName string) (*domain.Team, error)
+	renameFn             func(ctx context.Context, teamName, newName string) error
+	setArchivedFn        func(ctx context.Context, teamName string, archived bool) (*domain.Team, error)
+	deleteFn             func(ctx context.Context, teamName string) error
+	listFn               func(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error)
+	fetchSettingsFn      func(ctx context.Context, teamName string) (*domain.TeamSettings, error)
+	upsertSettingsFn     func(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error)
+}
+
+// FetchSettings без заданного fetchSettingsFn возвращает настройки по умолчанию
+func (m *teamRepositoryMock) FetchSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	if m.fetchSettingsFn == nil {
+		return domain.DefaultTeamSettings(teamName), nil
+	}
+	return m.fetchSettingsFn(ctx, teamName)
+}
+
+func (m *teamRepositoryMock) UpsertSettings(ctx context.Context, settings *domain.TeamSettings) (*domain.TeamSettings, error) {
+	return m.upsertSettingsFn(ctx, settings)
 }
 
 func (m *teamRepositoryMock) Create(ctx context.Context, teamName string) error {
@@ -105,3 +285,49 @@ func (m *teamRepositoryMock) Create(ctx context.Context, teamName string) error
 func (m *teamRepositoryMock) Exists(ctx context.Context, teamName string) (bool, error) {
 	return m.existsFn(ctx, teamName)
 }
+
+func (m *teamRepositoryMock) FetchByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	return m.fetchByNameFn(ctx, teamName)
+}
+
+func (m *teamRepositoryMock) Rename(ctx context.Context, teamName, newName string) error {
+	return m.renameFn(ctx, teamName, newName)
+}
+
+func (m *teamRepositoryMock) SetArchived(ctx context.Context, teamName string, archived bool) (*domain.Team, error) {
+	return m.setArchivedFn(ctx, teamName, archived)
+}
+
+func (m *teamRepositoryMock) Delete(ctx context.Context, teamName string) error {
+	return m.deleteFn(ctx, teamName)
+}
+
+func (m *teamRepositoryMock) List(ctx context.Context, filter domain.TeamFilter) (*domain.Page[*domain.Team], error) {
+	return m.listFn(ctx, filter)
+}
+
+func (m *teamRepositoryMock) LockReviewCursor(ctx context.Context, teamName string) (string, error) {
+	return m.lockReviewCursorFn(ctx, teamName)
+}
+
+func (m *teamRepositoryMock) UpdateReviewCursor(ctx context.Context, teamName, lastUserID string) error {
+	return m.updateReviewCursorFn(ctx, teamName, lastUserID)
+}
+
+type statsRepositoryMock struct {
+	userAssignmentsFn func(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error)
+	prReassignmentsFn func(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error)
+	teamTotalsFn      func(ctx context.Context, filter domain.StatsFilter) ([]*domain.TeamStats, error)
+}
+
+func (m *statsRepositoryMock) UserAssignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.UserAssignmentStats, error) {
+	return m.userAssignmentsFn(ctx, filter)
+}
+
+func (m *statsRepositoryMock) PRReassignments(ctx context.Context, filter domain.StatsFilter) ([]*domain.PRReassignmentStats, error) {
+	return m.prReassignmentsFn(ctx, filter)
+}
+
+func (m *statsRepositoryMock) TeamTotals(ctx context.Context, filter domain.StatsFilter) ([]*domain.TeamStats, error) {
+	return m.teamTotalsFn(ctx, filter)
+}
@@ -3,35 +3,266 @@ package usecase
 import (
 	"avito-backend-trainee-autumn-2025/internal/domain"
 	"context"
+	"errors"
+	"fmt"
 )
 
 type userUsecase struct {
 	userRepository domain.UserRepository
 	prRepository   domain.PRRepository
 	txManager      domain.TxManager
+	selector       ReviewerSelector
 }
 
-func NewUserUsecase(userRepository domain.UserRepository, prRepository domain.PRRepository, txManager domain.TxManager) domain.UserUsecase {
+func NewUserUsecase(userRepository domain.UserRepository, prRepository domain.PRRepository, txManager domain.TxManager, selector ReviewerSelector) domain.UserUsecase {
 	return &userUsecase{
 		userRepository: userRepository,
 		prRepository:   prRepository,
 		txManager:      txManager,
+		selector:       selector,
 	}
 }
 
-func (u *userUsecase) SetIsActive(ctx context.Context, userID string, active bool) (*domain.User, error) {
-	return u.userRepository.UpdateIsActive(ctx, userID, active)
+// SetIsActive меняет флаг активности. При деактивации открытые ревью пользователя в той же транзакции
+// передаются другим активным участникам его команды; PR без подходящего кандидата возвращаются с пустым NewReviewerID.
+func (u *userUsecase) SetIsActive(ctx context.Context, userID string, active bool) (*domain.User, []*domain.ReviewReassignment, error) {
+	var (
+		user          *domain.User
+		reassignments []*domain.ReviewReassignment
+	)
+
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		var err error
+		user, err = repos.User.UpdateIsActive(ctx, userID, active)
+		if err != nil {
+			return err
+		}
+
+		if active {
+			return nil
+		}
+
+		reassignments, err = reassignOpenReviews(ctx, repos, u.selector, user, domain.ReasonUserDeactivated)
+		return err
+	}, assignmentTx...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, reassignments, nil
 }
 
-func (u *userUsecase) GetReview(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
-	exists, err := u.userRepository.Exists(ctx, userID)
+// SetMaxOpenReviews задает личный лимит одновременно открытых ревью. Уже назначенные ревью не снимаются,
+// лимит учитывается при следующих назначениях; nil возвращает пользователя к лимиту команды.
+func (u *userUsecase) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+	if limit != nil && *limit < 0 {
+		return nil, fmt.Errorf("max_open_reviews must not be negative: %w", domain.ErrInvalidInput)
+	}
+
+	return u.userRepository.SetMaxOpenReviews(ctx, userID, limit)
+}
+
+func (u *userUsecase) GetReview(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error) {
+	var result []*domain.PullRequest
+
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		exists, err := repos.User.Exists(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+
+		if state != "" {
+			result, err = repos.PR.ListReviewableByUserIDAndState(ctx, userID, state)
+			return err
+		}
+
+		result, err = repos.PR.ListReviewableByUserID(ctx, userID)
+		return err
+	}, domain.ReadOnly(), domain.WithIsolation(domain.RepeatableRead))
 	if err != nil {
 		return nil, err
 	}
 
-	if !exists {
-		return nil, domain.ErrNotFound
+	return result, nil
+}
+
+// List возвращает страницу пользователей, по умолчанию по id
+func (u *userUsecase) List(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+	if err := normalizePage(&filter.PageParams, []string{domain.SortByID, domain.SortByName}, domain.SortAsc); err != nil {
+		return nil, err
+	}
+	return u.userRepository.List(ctx, filter)
+}
+
+// AddAbsence регистрирует период отсутствия. Заместитель должен быть активным пользователем, периоды одного пользователя
+// не пересекаются. Если период уже начался и задан заместитель, открытые ревью передаются ему в той же транзакции.
+func (u *userUsecase) AddAbsence(ctx context.Context, absence *domain.Absence) (*domain.Absence, []*domain.ReviewReassignment, error) {
+	switch {
+	case absence.StartsAt.IsZero() || absence.EndsAt.IsZero():
+		return nil, nil, fmt.Errorf("starts_at and ends_at are required: %w", domain.ErrInvalidInput)
+	case !absence.EndsAt.After(absence.StartsAt):
+		return nil, nil, fmt.Errorf("ends_at must be after starts_at: %w", domain.ErrInvalidInput)
+	case absence.DelegateID == absence.UserID:
+		return nil, nil, fmt.Errorf("delegate_id must differ from user_id: %w", domain.ErrInvalidInput)
 	}
 
-	return u.prRepository.ListReviewableByUserID(ctx, userID)
+	var (
+		created       *domain.Absence
+		reassignments []*domain.ReviewReassignment
+	)
+
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		created, reassignments = nil, nil
+
+		if _, err := repos.User.FetchByID(ctx, absence.UserID); err != nil {
+			return err
+		}
+
+		if absence.DelegateID != "" {
+			delegate, err := repos.User.FetchByID(ctx, absence.DelegateID)
+			if errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("delegate %s not found: %w", absence.DelegateID, domain.ErrInvalidInput)
+			}
+			if err != nil {
+				return err
+			}
+			if !delegate.IsActive {
+				return fmt.Errorf("delegate %s is inactive: %w", absence.DelegateID, domain.ErrInvalidInput)
+			}
+		}
+
+		overlap, err := repos.User.HasOverlappingAbsence(ctx, absence.UserID, absence.StartsAt, absence.EndsAt)
+		if err != nil {
+			return err
+		}
+		if overlap {
+			return domain.ErrAbsenceOverlap
+		}
+
+		created, err = repos.User.AddAbsence(ctx, absence)
+		if err != nil {
+			return err
+		}
+
+		due, err := repos.User.ListDueHandoffs(ctx, absence.UserID)
+		if err != nil {
+			return err
+		}
+		for _, a := range due {
+			res, handedOff, err := handOffAbsence(ctx, repos, u.selector, a)
+			if err != nil {
+				return err
+			}
+			reassignments = append(reassignments, res...)
+			if handedOff.ID == created.ID {
+				created = handedOff
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return created, reassignments, nil
+}
+
+func (u *userUsecase) ListAbsences(ctx context.Context, userID string) ([]*domain.Absence, error) {
+	var result []*domain.Absence
+
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		exists, err := repos.User.Exists(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+
+		result, err = repos.User.ListAbsences(ctx, userID)
+		return err
+	}, domain.ReadOnly(), domain.WithIsolation(domain.RepeatableRead))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteAbsence удаляет период отсутствия; уже переданные заместителю ревью не возвращаются
+func (u *userUsecase) DeleteAbsence(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+	return u.userRepository.DeleteAbsence(ctx, absenceID)
+}
+
+// HandOffAbsences обрабатывает начавшиеся периоды отсутствия с заместителем в одной транзакции
+func (u *userUsecase) HandOffAbsences(ctx context.Context) (int, error) {
+	var n int
+
+	err := u.txManager.WithinTx(ctx, func(ctx context.Context, repos *domain.Repos) error {
+		due, err := repos.User.ListDueHandoffs(ctx, "")
+		if err != nil {
+			return err
+		}
+
+		for _, a := range due {
+			if _, _, err := handOffAbsence(ctx, repos, u.selector, a); err != nil {
+				return err
+			}
+		}
+		n = len(due)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// handOffAbsence передает открытые ревью отсутствующего пользователя заместителю и отмечает период обработанным.
+// Если заместитель к этому моменту деактивирован, ревью остаются за пользователем. Если заместитель сам отсутствует,
+// ревью переназначаются по стратегии выбора ревьюверов, как при деактивации пользователя.
+func handOffAbsence(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, absence *domain.Absence) ([]*domain.ReviewReassignment, *domain.Absence, error) {
+	delegate, err := repos.User.FetchByID(ctx, absence.DelegateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reassignments []*domain.ReviewReassignment
+	if delegate.IsActive {
+		reassignments, err = handOffOrReassign(ctx, repos, selector, absence.UserID, delegate.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	handedOff, err := repos.User.MarkHandedOff(ctx, absence.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reassignments, handedOff, nil
+}
+
+// handOffOrReassign передает ревью заместителю, а если у него сейчас идет свой период отсутствия — участникам,
+// выбранным стратегией
+func handOffOrReassign(ctx context.Context, repos *domain.Repos, selector ReviewerSelector, userID, delegateID string) ([]*domain.ReviewReassignment, error) {
+	_, err := repos.User.FetchCurrentAbsence(ctx, delegateID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return handOffReviews(ctx, repos, userID, delegateID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := repos.User.FetchByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return reassignOpenReviews(ctx, repos, selector, user, domain.ReasonUserAbsent)
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestUserUsecaseSetIsActive_Success(t *testing.T) {
@@ -17,15 +18,25 @@ func TestUserUsecaseSetIsActive_Success(t *testing.T) {
 			return &domain.User{ID: userID, IsActive: active}, nil
 		},
 	}
-	uc := NewUserUsecase(userRepo, nil, nil)
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			t.Fatalf("activation must not touch reviews")
+			return nil, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo, PR: prRepo}}
+	uc := NewUserUsecase(userRepo, prRepo, tx, NewRandomSelector())
 
-	user, err := uc.SetIsActive(context.Background(), "u1", true)
+	user, reassigned, err := uc.SetIsActive(context.Background(), "u1", true)
 	if err != nil {
 		t.Fatalf("SetIsActive: %v", err)
 	}
 	if !user.IsActive {
 		t.Fatalf("expected active user")
 	}
+	if len(reassigned) != 0 {
+		t.Fatalf("expected no reassignments, got %v", reassigned)
+	}
 }
 
 func TestUserUsecaseSetIsActive_NotFound(t *testing.T) {
@@ -34,14 +45,107 @@ func TestUserUsecaseSetIsActive_NotFound(t *testing.T) {
 			return nil, domain.ErrNotFound
 		},
 	}
-	uc := NewUserUsecase(userRepo, nil, nil)
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo}}
+	uc := NewUserUsecase(userRepo, nil, tx, NewRandomSelector())
 
-	_, err := uc.SetIsActive(context.Background(), "missing", false)
+	_, _, err := uc.SetIsActive(context.Background(), "missing", false)
 	if !errors.Is(err, domain.ErrNotFound) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 }
 
+func TestUserUsecaseSetIsActive_DeactivateReassignsOpenReviews(t *testing.T) {
+	replaced := make(map[string]string)
+
+	userRepo := &userRepositoryMock{
+		updateIsActiveFn: func(ctx context.Context, userID string, active bool) (*domain.User, error) {
+			return &domain.User{ID: userID, TeamName: "team", IsActive: active}, nil
+		},
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team"}, nil
+		},
+		fetchActiveFn: func(ctx context.Context, teamName string, excludeIDs ...string) ([]*domain.User, error) {
+			if teamName != "team" {
+				t.Fatalf("expected reviewer team, got %s", teamName)
+			}
+			for _, id := range excludeIDs {
+				if id == "cand" {
+					return nil, nil
+				}
+			}
+			return []*domain.User{{ID: "cand", TeamName: teamName, IsActive: true}}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{
+				{ID: "open1", AuthorID: "author", Status: domain.StatusOpen},
+				{ID: "merged", AuthorID: "author", Status: domain.StatusMerged},
+				{ID: "open2", AuthorID: "author", Status: domain.StatusOpen},
+			}, nil
+		},
+		listReviewersFn: func(ctx context.Context, prID string) ([]string, error) {
+			if prID == "open2" {
+				return []string{"u1", "cand"}, nil
+			}
+			return []string{"u1"}, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+			if oldReviewerID != "u1" {
+				t.Fatalf("unexpected old reviewer: %s", oldReviewerID)
+			}
+			replaced[prID] = newReviewerID
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, events ...*domain.AssignmentEvent) error {
+			if events[0].Reason != domain.ReasonUserDeactivated {
+				t.Fatalf("unexpected reason: %s", events[0].Reason)
+			}
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo, PR: prRepo, Team: &teamRepositoryMock{}}}
+	uc := NewUserUsecase(userRepo, prRepo, tx, NewRandomSelector())
+
+	user, reassigned, err := uc.SetIsActive(context.Background(), "u1", false)
+	if err != nil {
+		t.Fatalf("SetIsActive: %v", err)
+	}
+	if user.IsActive {
+		t.Fatalf("expected inactive user")
+	}
+
+	want := []*domain.ReviewReassignment{
+		{PRID: "open1", OldReviewerID: "u1", NewReviewerID: "cand"},
+		{PRID: "open2", OldReviewerID: "u1", NewReviewerID: ""},
+	}
+	if !reflect.DeepEqual(reassigned, want) {
+		t.Fatalf("unexpected reassignments: %+v", reassigned)
+	}
+	if !reflect.DeepEqual(replaced, map[string]string{"open1": "cand"}) {
+		t.Fatalf("unexpected replacements: %v", replaced)
+	}
+}
+
+func TestUserUsecaseSetIsActive_DeactivateFailsOnRepoError(t *testing.T) {
+	userRepo := &userRepositoryMock{
+		updateIsActiveFn: func(ctx context.Context, userID string, active bool) (*domain.User, error) {
+			return &domain.User{ID: userID, TeamName: "team"}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo, PR: prRepo}}
+	uc := NewUserUsecase(userRepo, prRepo, tx, NewRandomSelector())
+
+	if _, _, err := uc.SetIsActive(context.Background(), "u1", false); err == nil {
+		t.Fatalf("expected error to abort transaction")
+	}
+}
+
 func TestUserUsecaseGetReview_Success(t *testing.T) {
 	userRepo := &userRepositoryMock{
 		existsFn: func(ctx context.Context, userID string) (bool, error) {
@@ -53,16 +157,45 @@ func TestUserUsecaseGetReview_Success(t *testing.T) {
 			return []*domain.PullRequest{{ID: "pr1"}, {ID: "pr2"}}, nil
 		},
 	}
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo, PR: prRepo}}
+	uc := NewUserUsecase(userRepo, prRepo, tx, nil)
 
-	uc := NewUserUsecase(userRepo, prRepo, nil)
-
-	prs, err := uc.GetReview(context.Background(), "u1")
+	prs, err := uc.GetReview(context.Background(), "u1", "")
 	if err != nil {
 		t.Fatalf("GetReview: %v", err)
 	}
 	if len(prs) != 2 || !reflect.DeepEqual(prs[0].ID, "pr1") {
 		t.Fatalf("unexpected prs: %+v", prs)
 	}
+	if !tx.lastOpts.ReadOnly {
+		t.Fatalf("expected review list to be read in a read-only transaction, got %+v", tx.lastOpts)
+	}
+}
+
+func TestUserUsecaseGetReview_FiltersByState(t *testing.T) {
+	userRepo := &userRepositoryMock{
+		existsFn: func(ctx context.Context, userID string) (bool, error) {
+			return true, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listByStateFn: func(ctx context.Context, userID string, state domain.ReviewState) ([]*domain.PullRequest, error) {
+			if state != domain.ReviewPending {
+				t.Fatalf("unexpected state filter: %s", state)
+			}
+			return []*domain.PullRequest{{ID: "pr1"}}, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo, PR: prRepo}}
+	uc := NewUserUsecase(userRepo, prRepo, tx, nil)
+
+	prs, err := uc.GetReview(context.Background(), "u1", domain.ReviewPending)
+	if err != nil {
+		t.Fatalf("GetReview: %v", err)
+	}
+	if len(prs) != 1 || prs[0].ID != "pr1" {
+		t.Fatalf("unexpected prs: %+v", prs)
+	}
 }
 
 func TestUserUsecaseGetReview_NotFound(t *testing.T) {
@@ -71,10 +204,209 @@ func TestUserUsecaseGetReview_NotFound(t *testing.T) {
 			return false, nil
 		},
 	}
-	uc := NewUserUsecase(userRepo, nil, nil)
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo}}
+	uc := NewUserUsecase(userRepo, nil, tx, nil)
 
-	_, err := uc.GetReview(context.Background(), "missing")
+	_, err := uc.GetReview(context.Background(), "missing", "")
 	if !errors.Is(err, domain.ErrNotFound) {
 		t.Fatalf("expected ErrNotFound, got %v", err)
 	}
 }
+
+func TestUserUsecaseList_SortByName(t *testing.T) {
+	var got domain.UserFilter
+	userRepo := &userRepositoryMock{
+		listFn: func(ctx context.Context, filter domain.UserFilter) (*domain.Page[*domain.User], error) {
+			got = filter
+			return &domain.Page[*domain.User]{}, nil
+		},
+	}
+	uc := NewUserUsecase(userRepo, nil, nil, NewRandomSelector())
+
+	filter := domain.UserFilter{TeamName: "team", PageParams: domain.PageParams{Sort: domain.SortByName, Limit: 5}}
+	if _, err := uc.List(context.Background(), filter); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got.TeamName != "team" || got.Sort != domain.SortByName || got.Order != domain.SortAsc || got.Limit != 5 {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+
+	filter.Sort = domain.SortByCreatedAt
+	if _, err := uc.List(context.Background(), filter); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for unsupported sort, got %v", err)
+	}
+}
+
+func TestUserUsecaseAddAbsence_HandsOffStartedAbsence(t *testing.T) {
+	start := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	replaced := make(map[string]string)
+	var events []*domain.AssignmentEvent
+
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team", IsActive: true}, nil
+		},
+		overlapFn: func(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error) {
+			return false, nil
+		},
+		addAbsenceFn: func(ctx context.Context, absence *domain.Absence) (*domain.Absence, error) {
+			created := *absence
+			created.ID = 7
+			return &created, nil
+		},
+		dueHandoffsFn: func(ctx context.Context, userID string) ([]*domain.Absence, error) {
+			if userID != "u1" {
+				t.Fatalf("expected handoffs of u1 only, got %q", userID)
+			}
+			return []*domain.Absence{{ID: 7, UserID: "u1", DelegateID: "d1"}}, nil
+		},
+		markHandedOffFn: func(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+			at := start
+			return &domain.Absence{ID: absenceID, UserID: "u1", DelegateID: "d1", HandedOffAt: &at}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{
+				{ID: "pr1", AuthorID: "a1", Status: domain.StatusOpen},
+				{ID: "pr2", AuthorID: "d1", Status: domain.StatusOpen},
+				{ID: "pr3", AuthorID: "a1", Status: domain.StatusMerged},
+			}, nil
+		},
+		reviewerAssignedFn: func(ctx context.Context, prID, userID string) (bool, error) {
+			return false, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+			replaced[prID] = newReviewerID
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			events = append(events, evs...)
+			return nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo, PR: prRepo}}
+	uc := NewUserUsecase(userRepo, prRepo, tx, NewRandomSelector())
+
+	absence, reassigned, err := uc.AddAbsence(context.Background(), &domain.Absence{
+		UserID:     "u1",
+		StartsAt:   start,
+		EndsAt:     start.Add(72 * time.Hour),
+		DelegateID: "d1",
+	})
+	if err != nil {
+		t.Fatalf("AddAbsence: %v", err)
+	}
+	if absence.HandedOffAt == nil {
+		t.Fatalf("expected absence marked as handed off: %+v", absence)
+	}
+	if !reflect.DeepEqual(replaced, map[string]string{"pr1": "d1"}) {
+		t.Fatalf("unexpected replacements: %v", replaced)
+	}
+	if len(reassigned) != 2 || reassigned[0].NewReviewerID != "d1" || reassigned[1].NewReviewerID != "" {
+		t.Fatalf("unexpected reassignments: %+v", reassigned)
+	}
+	if len(events) != 1 || events[0].Reason != domain.ReasonUserAbsent || events[0].PreviousUserID != "u1" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestUserUsecaseAddAbsence_RetryKeepsLastAttempt(t *testing.T) {
+	start := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, TeamName: "team", IsActive: true}, nil
+		},
+		overlapFn: func(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error) {
+			return false, nil
+		},
+		addAbsenceFn: func(ctx context.Context, absence *domain.Absence) (*domain.Absence, error) {
+			created := *absence
+			created.ID = 7
+			return &created, nil
+		},
+		dueHandoffsFn: func(ctx context.Context, userID string) ([]*domain.Absence, error) {
+			return []*domain.Absence{{ID: 7, UserID: "u1", DelegateID: "d1"}}, nil
+		},
+		markHandedOffFn: func(ctx context.Context, absenceID int64) (*domain.Absence, error) {
+			at := start
+			return &domain.Absence{ID: absenceID, UserID: "u1", DelegateID: "d1", HandedOffAt: &at}, nil
+		},
+	}
+	prRepo := &prRepositoryMock{
+		listReviewableFn: func(ctx context.Context, userID string) ([]*domain.PullRequest, error) {
+			return []*domain.PullRequest{{ID: "pr1", AuthorID: "a1", Status: domain.StatusOpen}}, nil
+		},
+		reviewerAssignedFn: func(ctx context.Context, prID, userID string) (bool, error) {
+			return false, nil
+		},
+		replaceReviewerFn: func(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
+			return nil
+		},
+		addEventsFn: func(ctx context.Context, evs ...*domain.AssignmentEvent) error {
+			return nil
+		},
+	}
+	repos := &domain.Repos{User: userRepo, PR: prRepo}
+	// первая попытка проходит целиком, но транзакция повторяется, как после конфликта сериализации
+	tx := &txManagerStub{withinFn: func(ctx context.Context, fn func(context.Context, *domain.Repos) error) error {
+		if err := fn(ctx, repos); err != nil {
+			return err
+		}
+		return fn(ctx, repos)
+	}}
+	uc := NewUserUsecase(userRepo, prRepo, tx, NewRandomSelector())
+
+	_, reassigned, err := uc.AddAbsence(context.Background(), &domain.Absence{
+		UserID:     "u1",
+		StartsAt:   start,
+		EndsAt:     start.Add(72 * time.Hour),
+		DelegateID: "d1",
+	})
+	if err != nil {
+		t.Fatalf("AddAbsence: %v", err)
+	}
+	if len(reassigned) != 1 {
+		t.Fatalf("expected reassignments of the last attempt only, got %+v", reassigned)
+	}
+}
+
+func TestUserUsecaseAddAbsence_Overlap(t *testing.T) {
+	start := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	userRepo := &userRepositoryMock{
+		fetchByIDFn: func(ctx context.Context, id string) (*domain.User, error) {
+			return &domain.User{ID: id, IsActive: true}, nil
+		},
+		overlapFn: func(ctx context.Context, userID string, startsAt, endsAt time.Time) (bool, error) {
+			return true, nil
+		},
+		addAbsenceFn: func(ctx context.Context, absence *domain.Absence) (*domain.Absence, error) {
+			t.Fatalf("overlapping absence must not be saved")
+			return nil, nil
+		},
+	}
+	tx := &txManagerStub{repos: &domain.Repos{User: userRepo}}
+	uc := NewUserUsecase(userRepo, nil, tx, NewRandomSelector())
+
+	_, _, err := uc.AddAbsence(context.Background(), &domain.Absence{UserID: "u1", StartsAt: start, EndsAt: start.Add(time.Hour)})
+	if !errors.Is(err, domain.ErrAbsenceOverlap) {
+		t.Fatalf("expected ErrAbsenceOverlap, got %v", err)
+	}
+}
+
+func TestUserUsecaseAddAbsence_InvalidInput(t *testing.T) {
+	start := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	uc := NewUserUsecase(&userRepositoryMock{}, nil, &txManagerStub{}, NewRandomSelector())
+
+	cases := []*domain.Absence{
+		{UserID: "u1", StartsAt: start},
+		{UserID: "u1", StartsAt: start, EndsAt: start},
+		{UserID: "u1", StartsAt: start, EndsAt: start.Add(time.Hour), DelegateID: "u1"},
+	}
+	for _, absence := range cases {
+		if _, _, err := uc.AddAbsence(context.Background(), absence); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("expected ErrInvalidInput for %+v, got %v", absence, err)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+CREATE TABLE team_review_cursors
+(
+    team_name    TEXT PRIMARY KEY REFERENCES teams (name),
+    last_user_id TEXT NOT NULL DEFAULT ''
+);
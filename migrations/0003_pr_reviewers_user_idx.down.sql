@@ -0,0 +1 @@
+DROP INDEX idx_pr_reviewers_user_id;
@@ -0,0 +1,5 @@
+DROP INDEX idx_pull_requests_merged_at;
+DROP INDEX idx_pull_requests_created_at;
+
+ALTER TABLE pull_requests
+    DROP COLUMN reassign_count;
@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+    ADD COLUMN reassign_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_pull_requests_created_at ON pull_requests (created_at);
+CREATE INDEX idx_pull_requests_merged_at ON pull_requests (merged_at);
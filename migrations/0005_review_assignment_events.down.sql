@@ -0,0 +1,4 @@
+DROP TRIGGER trg_review_assignment_events_append_only ON review_assignment_events;
+DROP FUNCTION review_assignment_events_append_only();
+DROP INDEX idx_review_assignment_events_pr_id;
+DROP TABLE review_assignment_events;
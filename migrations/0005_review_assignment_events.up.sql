@@ -0,0 +1,26 @@
+CREATE TABLE review_assignment_events
+(
+    id               BIGSERIAL PRIMARY KEY,
+    pr_id            TEXT        NOT NULL REFERENCES pull_requests (id),
+    event_type       TEXT        NOT NULL CHECK (event_type IN ('ASSIGNED', 'REASSIGNED', 'UNASSIGNED')),
+    user_id          TEXT REFERENCES users (id),
+    previous_user_id TEXT REFERENCES users (id),
+    actor            TEXT        NOT NULL,
+    reason           TEXT        NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_review_assignment_events_pr_id ON review_assignment_events (pr_id, id);
+
+CREATE FUNCTION review_assignment_events_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'review_assignment_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_review_assignment_events_append_only
+    BEFORE UPDATE OR DELETE
+    ON review_assignment_events
+    FOR EACH ROW
+EXECUTE FUNCTION review_assignment_events_append_only();
@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests
+    DROP CONSTRAINT pull_requests_status_check;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('OPEN', 'MERGED', 'CLOSED'));
+ALTER TABLE pull_requests
+    ADD COLUMN closed_at TIMESTAMPTZ;
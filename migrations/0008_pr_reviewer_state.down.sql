@@ -0,0 +1,4 @@
+ALTER TABLE pr_reviewers
+    DROP COLUMN state_updated_at;
+ALTER TABLE pr_reviewers
+    DROP COLUMN state;
@@ -0,0 +1,5 @@
+ALTER TABLE pr_reviewers
+    ADD COLUMN state TEXT NOT NULL DEFAULT 'PENDING'
+        CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'DISMISSED'));
+ALTER TABLE pr_reviewers
+    ADD COLUMN state_updated_at TIMESTAMPTZ;
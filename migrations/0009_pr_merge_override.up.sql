@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+    ADD COLUMN merge_override_by TEXT;
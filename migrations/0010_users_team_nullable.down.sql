@@ -0,0 +1,14 @@
+-- Пользователи без команды переносятся в служебную команду unassigned, иначе NOT NULL не вернуть.
+-- Они деактивируются, чтобы, как и без команды, не становиться кандидатами на ревью; PR и история сохраняются.
+INSERT INTO teams (name)
+SELECT 'unassigned'
+WHERE EXISTS (SELECT 1 FROM users WHERE team_name IS NULL)
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE users
+SET team_name = 'unassigned',
+    is_active = false
+WHERE team_name IS NULL;
+
+ALTER TABLE users
+    ALTER COLUMN team_name SET NOT NULL;
@@ -0,0 +1,12 @@
+ALTER TABLE teams
+    DROP COLUMN archived_at;
+
+ALTER TABLE team_review_cursors
+    DROP CONSTRAINT team_review_cursors_team_name_fkey;
+ALTER TABLE team_review_cursors
+    ADD CONSTRAINT team_review_cursors_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name);
+
+ALTER TABLE users
+    DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE users
+    ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name);
@@ -0,0 +1,4 @@
+DROP INDEX idx_users_name_id;
+DROP INDEX idx_pull_requests_author_id;
+DROP INDEX idx_pull_requests_status_created_at_id;
+DROP INDEX idx_pull_requests_created_at_id;
@@ -0,0 +1,4 @@
+CREATE INDEX idx_pull_requests_created_at_id ON pull_requests (created_at, id);
+CREATE INDEX idx_pull_requests_status_created_at_id ON pull_requests (status, created_at, id);
+CREATE INDEX idx_pull_requests_author_id ON pull_requests (author_id);
+CREATE INDEX idx_users_name_id ON users (name, id);
@@ -0,0 +1,3 @@
+DROP TABLE team_fallback_teams;
+
+DROP TABLE team_settings;
@@ -0,0 +1,17 @@
+CREATE TABLE team_settings
+(
+    team_name     TEXT PRIMARY KEY REFERENCES teams (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    min_reviewers INT NOT NULL DEFAULT 0,
+    max_reviewers INT NOT NULL DEFAULT 2,
+    CHECK (min_reviewers >= 0 AND min_reviewers <= max_reviewers)
+);
+
+CREATE TABLE team_fallback_teams
+(
+    team_name          TEXT NOT NULL REFERENCES teams (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    fallback_team_name TEXT NOT NULL REFERENCES teams (name) ON UPDATE CASCADE ON DELETE CASCADE,
+    position           INT  NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    UNIQUE (team_name, position),
+    CHECK (fallback_team_name <> team_name)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE team_settings
+    DROP COLUMN default_max_open_reviews;
+
+ALTER TABLE users
+    DROP COLUMN max_open_reviews;
@@ -0,0 +1,21 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE user_absences
+(
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       TEXT        NOT NULL REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    starts_at     TIMESTAMPTZ NOT NULL,
+    ends_at       TIMESTAMPTZ NOT NULL,
+    delegate_id   TEXT REFERENCES users (id) ON UPDATE CASCADE ON DELETE SET NULL,
+    handed_off_at TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at),
+    CHECK (delegate_id <> user_id),
+    -- периоды одного пользователя не пересекаются даже при параллельной записи
+    EXCLUDE USING gist (user_id WITH =, tstzrange(starts_at, ends_at) WITH &&)
+);
+
+CREATE INDEX idx_user_absences_user_id_ends_at ON user_absences (user_id, ends_at);
+
+CREATE INDEX idx_user_absences_pending_handoff ON user_absences (starts_at)
+    WHERE delegate_id IS NOT NULL AND handed_off_at IS NULL;
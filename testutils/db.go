@@ -52,6 +52,11 @@ func PrepareTestTablesWithFixtures(ctx context.Context, db *pgxpool.Pool) error
 	defer tx.Rollback(ctx)
 
 	_, err = tx.Exec(ctx, `
+        TRUNCATE review_assignment_events CASCADE;
+        TRUNCATE user_absences CASCADE;
+        TRUNCATE team_review_cursors CASCADE;
+        TRUNCATE team_settings CASCADE;
+        TRUNCATE team_fallback_teams CASCADE;
         TRUNCATE pr_reviewers CASCADE;
         TRUNCATE pull_requests CASCADE;
         TRUNCATE users CASCADE;
@@ -0,0 +1,41 @@
+package testutils
+
+import (
+	"avito-backend-trainee-autumn-2025/internal/domain"
+	"context"
+)
+
+// LoadFixtures заполняет пустое хранилище теми же данными, что и PrepareTestTablesWithFixtures, через его репозитории
+func LoadFixtures(ctx context.Context, repos *domain.Repos) error {
+	for _, team := range []*domain.Team{TeamTest, TeamOther} {
+		if err := repos.Team.Create(ctx, team.Name); err != nil {
+			return err
+		}
+		for _, u := range team.Members {
+			user := *u
+			if err := repos.User.Upsert(ctx, &user); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, pr := range []*domain.PullRequest{PR1, PR2, PR3} {
+		if _, err := repos.PR.Create(ctx, pr); err != nil {
+			return err
+		}
+	}
+
+	reviewers := []struct{ prID, userID string }{
+		{PR1ID, User2ID},
+		{PR1ID, User3ID},
+		{PR2ID, User2ID},
+		{PR3ID, User5ID},
+	}
+	for _, r := range reviewers {
+		if err := repos.PR.InsertReviewer(ctx, r.prID, r.userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}